@@ -34,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/internal/debug"
 	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/internal/telemetry"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/node"
@@ -86,6 +87,8 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.TxPoolLifecycleSlotsFlag,
+		utils.TxPoolAdmissionFlag,
 		utils.BlobPoolDataDirFlag,
 		utils.BlobPoolDataCapFlag,
 		utils.BlobPoolPriceBumpFlag,
@@ -117,6 +120,7 @@ var (
 		utils.CacheNoPrefetchFlag,
 		utils.CachePreimagesFlag,
 		utils.CacheLogSizeFlag,
+		utils.LogIndexFlag,
 		utils.FDLimitFlag,
 		utils.CryptoKZGFlag,
 		utils.ListenPortFlag,
@@ -131,9 +135,12 @@ var (
 		utils.MinerExtraDataFlag,
 		utils.MinerRecommitIntervalFlag,
 		utils.MinerPendingFeeRecipientFlag,
+		utils.MinerOrderingPolicyFlag,
 		utils.MinerNewPayloadTimeoutFlag, // deprecated
 		utils.CeloFeeCurrencyDefault,
 		utils.CeloFeeCurrencyLimits,
+		utils.CeloFeeCurrencyMaxRateAge,
+		utils.CeloFeeCurrencyMaxRateChange,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV4Flag,
@@ -158,6 +165,11 @@ var (
 		utils.GpoIgnoreGasPriceFlag,
 		utils.GpoMinSuggestedPriorityFeeFlag,
 		utils.RollupSequencerHTTPFlag,
+		utils.RollupSequencerRetriesFlag,
+		utils.RollupSequencerOutboxTTLFlag,
+		utils.RollupSequencerTxConditionalEnabledFlag,
+		utils.RollupSequencerTxConditionalCostRateLimitFlag,
+		utils.RollupSequencerBundlesEnabledFlag,
 		utils.RollupHistoricalRPCFlag,
 		utils.RollupHistoricalRPCTimeoutFlag,
 		utils.RollupDisableTxPoolGossipFlag,
@@ -193,6 +205,7 @@ var (
 		utils.GraphQLVirtualHostsFlag,
 		utils.HTTPApiFlag,
 		utils.HTTPPathPrefixFlag,
+		utils.HTTPSSEFlag,
 		utils.WSEnabledFlag,
 		utils.WSListenAddrFlag,
 		utils.WSPortFlag,
@@ -208,6 +221,19 @@ var (
 		utils.AllowUnprotectedTxs,
 		utils.BatchRequestLimit,
 		utils.BatchResponseMaxSize,
+		utils.RPCRateLimitFlag,
+		utils.RPCRateLimitBurstFlag,
+		utils.RPCRateLimitHeaderFlag,
+		utils.RPCRateLimitClaimFlag,
+		utils.RPCRateLimitCostsFlag,
+		utils.RPCRateLimitQuotasFlag,
+		utils.RPCResponseCacheFlag,
+		utils.RPCTelemetryFlag,
+		utils.RPCTelemetrySampleFlag,
+		utils.RPCTelemetryRemoteSamplingFlag,
+		utils.RPCTelemetryFileFlag,
+		utils.RPCTelemetryEndpointFlag,
+		utils.RPCPersistentFiltersFlag,
 	}
 
 	metricsFlags = []cli.Flag{
@@ -364,6 +390,9 @@ func prepare(ctx *cli.Context) {
 	// Start metrics export if enabled
 	utils.SetupMetrics(ctx)
 
+	// Start tracing of RPC requests if enabled
+	utils.SetupTelemetry(ctx)
+
 	// Start system runtime metrics collection
 	go metrics.CollectProcessMetrics(3 * time.Second)
 }
@@ -377,6 +406,7 @@ func geth(ctx *cli.Context) error {
 	}
 
 	prepare(ctx)
+	defer telemetry.Close()
 	stack := makeFullNode(ctx)
 	defer stack.Close()
 
@@ -24,6 +24,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"math/big"
 	"net"
@@ -40,6 +41,7 @@ import (
 	bparams "github.com/ethereum/go-ethereum/beacon/params"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/fdlimit"
+	"github.com/ethereum/go-ethereum/contracts"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
@@ -60,6 +62,7 @@ import (
 	"github.com/ethereum/go-ethereum/graphql"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/internal/telemetry"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/metrics/exp"
@@ -450,6 +453,17 @@ var (
 		Value:    ethconfig.Defaults.TxPool.Lifetime,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolLifecycleSlotsFlag = &cli.Uint64Flag{
+		Name:     "txpool.lifecycleslots",
+		Usage:    "Number of transactions whose lifecycle events are retained for txpool_txHistory (0 = disabled)",
+		Value:    ethconfig.Defaults.TxPool.LifecycleSlots,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolAdmissionFlag = &cli.StringFlag{
+		Name:     "txpool.admission",
+		Usage:    "TOML file of the transaction pool admission policies, reloaded when modified",
+		Category: flags.TxPoolCategory,
+	}
 	// Blob transaction pool settings
 	BlobPoolDataDirFlag = &cli.StringFlag{
 		Name:     "blobpool.datadir",
@@ -516,6 +530,11 @@ var (
 		Category: flags.PerfCategory,
 		Value:    ethconfig.Defaults.FilterLogCacheSize,
 	}
+	LogIndexFlag = &cli.BoolFlag{
+		Name:     "logindex",
+		Usage:    "Maintain an index of logs by address and event signature to speed up log queries over long block ranges",
+		Category: flags.PerfCategory,
+	}
 	FDLimitFlag = &cli.IntFlag{
 		Name:     "fdlimit",
 		Usage:    "Raise the open file descriptor resource limit (default = system fd limit)",
@@ -558,6 +577,12 @@ var (
 		Value:    ethconfig.Defaults.Miner.Recommit,
 		Category: flags.MinerCategory,
 	}
+	MinerOrderingPolicyFlag = &cli.StringFlag{
+		Name:     "miner.ordering",
+		Usage:    "Transaction ordering policy of built blocks (priority, fifo, fairshare)",
+		Value:    ethconfig.Defaults.Miner.OrderingPolicy,
+		Category: flags.MinerCategory,
+	}
 	MinerPendingFeeRecipientFlag = &cli.StringFlag{
 		Name:     "miner.pending.feeRecipient",
 		Usage:    "0x prefixed public address for the pending block producer (not used for actual block production)",
@@ -574,6 +599,16 @@ var (
 		Usage:    "Comma separated currency address-to-block percentage mappings (<address>=<fraction>)",
 		Category: flags.MinerCategory,
 	}
+	CeloFeeCurrencyMaxRateAge = &cli.DurationFlag{
+		Name:     "celo.feecurrency.maxrateage",
+		Usage:    "Maximum age of a fee currency's oracle rate for its txs to be admitted to the txpool and mined locally (0 = no limit)",
+		Category: flags.MinerCategory,
+	}
+	CeloFeeCurrencyMaxRateChange = &cli.Float64Flag{
+		Name:     "celo.feecurrency.maxratechange",
+		Usage:    "Maximum factor a fee currency's exchange rate may change between blocks for its txs to be admitted to the txpool and mined locally (0 = no limit)",
+		Category: flags.MinerCategory,
+	}
 
 	// Account settings
 	UnlockedAccountFlag = &cli.StringFlag{
@@ -737,6 +772,11 @@ var (
 		Value:    "",
 		Category: flags.APICategory,
 	}
+	HTTPSSEFlag = &cli.BoolFlag{
+		Name:     "http.sse",
+		Usage:    "Enable subscriptions over HTTP-RPC, served as Server-Sent Events",
+		Category: flags.APICategory,
+	}
 	GraphQLEnabledFlag = &cli.BoolFlag{
 		Name:     "graphql",
 		Usage:    "Enable GraphQL on the HTTP-RPC server. Note that GraphQL can only be started if an HTTP server is started as well.",
@@ -816,6 +856,72 @@ var (
 		Value:    node.DefaultConfig.BatchResponseMaxSize,
 		Category: flags.APICategory,
 	}
+	RPCRateLimitFlag = &cli.Float64Flag{
+		Name:     "rpc.ratelimit",
+		Usage:    "Cost units per second each client may spend on HTTP and WebSocket RPC calls (0 = unlimited)",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitBurstFlag = &cli.IntFlag{
+		Name:     "rpc.ratelimit.burst",
+		Usage:    "Cost units each client may spend at once (defaults to the rate)",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitHeaderFlag = &cli.StringFlag{
+		Name:     "rpc.ratelimit.header",
+		Usage:    "HTTP header carrying the API key identifying rate limited clients with a quota, instead of their IP address",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitClaimFlag = &cli.StringFlag{
+		Name:     "rpc.ratelimit.claim",
+		Usage:    "JWT claim identifying clients of the authenticated RPC, which are rate limited by their quotas only",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitCostsFlag = &cli.StringFlag{
+		Name:     "rpc.ratelimit.costs",
+		Usage:    "Comma separated list of method=cost entries overriding the rate limiting costs, a trailing '*' matches by prefix",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitQuotasFlag = &cli.StringFlag{
+		Name:     "rpc.ratelimit.quotas",
+		Usage:    "Comma separated list of key=rate:burst entries of clients with their own rate limit, keyed by API key, claim or IP address",
+		Category: flags.APICategory,
+	}
+	RPCResponseCacheFlag = &cli.IntFlag{
+		Name:     "rpc.responsecache",
+		Usage:    "Megabytes of memory allocated to caching HTTP and WebSocket RPC results of finalized blocks (0 = disabled)",
+		Category: flags.APICategory,
+	}
+	RPCTelemetryFlag = &cli.BoolFlag{
+		Name:     "rpc.telemetry",
+		Usage:    "Enable tracing of RPC requests",
+		Category: flags.APICategory,
+	}
+	RPCTelemetrySampleFlag = &cli.Float64Flag{
+		Name:     "rpc.telemetry.sample",
+		Usage:    "Fraction of RPC requests traced",
+		Value:    1,
+		Category: flags.APICategory,
+	}
+	RPCTelemetryRemoteSamplingFlag = &cli.BoolFlag{
+		Name:     "rpc.telemetry.remotesampling",
+		Usage:    "Let the sampled flag of the caller's traceparent header decide whether a request is traced",
+		Category: flags.APICategory,
+	}
+	RPCTelemetryFileFlag = &cli.StringFlag{
+		Name:     "rpc.telemetry.file",
+		Usage:    "File to append trace spans to in the OTLP/JSON encoding",
+		Category: flags.APICategory,
+	}
+	RPCTelemetryEndpointFlag = &cli.StringFlag{
+		Name:     "rpc.telemetry.endpoint",
+		Usage:    "OTLP/HTTP collector endpoint to export trace spans to (e.g. http://localhost:4318/v1/traces)",
+		Category: flags.APICategory,
+	}
+	RPCPersistentFiltersFlag = &cli.BoolFlag{
+		Name:     "rpc.persistentfilters",
+		Usage:    "Store log and block filters in the database, so that polling clients don't miss events across restarts",
+		Category: flags.APICategory,
+	}
 	EnablePersonal = &cli.BoolFlag{
 		Name:     "rpc.enabledeprecatedpersonal",
 		Usage:    "Enables the (deprecated) personal namespace",
@@ -948,7 +1054,35 @@ var (
 	// Rollup Flags
 	RollupSequencerHTTPFlag = &cli.StringFlag{
 		Name:     "rollup.sequencerhttp",
-		Usage:    "HTTP endpoint for the sequencer mempool",
+		Usage:    "HTTP endpoint for the sequencer mempool (comma separated list for failover, in order of preference)",
+		Category: flags.RollupCategory,
+	}
+	RollupSequencerRetriesFlag = &cli.IntFlag{
+		Name:     "rollup.sequencerretries",
+		Usage:    "Number of retries after transient errors forwarding transactions to the sequencer",
+		Value:    ethconfig.Defaults.RollupSequencerRetries,
+		Category: flags.RollupCategory,
+	}
+	RollupSequencerOutboxTTLFlag = &cli.DurationFlag{
+		Name:     "rollup.sequenceroutboxttl",
+		Usage:    "Time to keep retrying transactions accepted while the sequencer is unavailable (0 = disabled)",
+		Value:    ethconfig.Defaults.RollupSequencerOutboxTTL,
+		Category: flags.RollupCategory,
+	}
+	RollupSequencerTxConditionalEnabledFlag = &cli.BoolFlag{
+		Name:     "rollup.sequencertxconditionalenabled",
+		Usage:    "Serve eth_sendRawTransactionConditional, for sequencers only",
+		Category: flags.RollupCategory,
+	}
+	RollupSequencerTxConditionalCostRateLimitFlag = &cli.IntFlag{
+		Name:     "rollup.sequencertxconditionalcostratelimit",
+		Usage:    "Maximum cost of conditional transactions accepted per second (0 = unlimited)",
+		Value:    ethconfig.Defaults.RollupSequencerTxConditionalCostRateLimit,
+		Category: flags.RollupCategory,
+	}
+	RollupSequencerBundlesEnabledFlag = &cli.BoolFlag{
+		Name:     "rollup.sequencerbundlesenabled",
+		Usage:    "Serve eth_sendBundle and eth_callBundle, for sequencers only",
 		Category: flags.RollupCategory,
 	}
 
@@ -1311,6 +1445,9 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(HTTPPathPrefixFlag.Name) {
 		cfg.HTTPPathPrefix = ctx.String(HTTPPathPrefixFlag.Name)
 	}
+	if ctx.IsSet(HTTPSSEFlag.Name) {
+		cfg.HTTPSSE = ctx.Bool(HTTPSSEFlag.Name)
+	}
 	if ctx.IsSet(AllowUnprotectedTxs.Name) {
 		cfg.AllowUnprotectedTxs = ctx.Bool(AllowUnprotectedTxs.Name)
 	}
@@ -1322,6 +1459,46 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(BatchResponseMaxSize.Name) {
 		cfg.BatchResponseMaxSize = ctx.Int(BatchResponseMaxSize.Name)
 	}
+	setRPCRateLimit(ctx, &cfg.RPCRateLimit)
+
+	if ctx.IsSet(RPCResponseCacheFlag.Name) {
+		cfg.RPCResponseCache = ctx.Int(RPCResponseCacheFlag.Name) * 1024 * 1024
+	}
+}
+
+// setRPCRateLimit applies the RPC rate limiting flags to the config.
+func setRPCRateLimit(ctx *cli.Context, cfg *rpc.RateLimitConfig) {
+	if ctx.IsSet(RPCRateLimitFlag.Name) {
+		cfg.Rate = ctx.Float64(RPCRateLimitFlag.Name)
+	}
+	if ctx.IsSet(RPCRateLimitBurstFlag.Name) {
+		cfg.Burst = ctx.Int(RPCRateLimitBurstFlag.Name)
+	}
+	if ctx.IsSet(RPCRateLimitHeaderFlag.Name) {
+		cfg.KeyHeader = ctx.String(RPCRateLimitHeaderFlag.Name)
+	}
+	if ctx.IsSet(RPCRateLimitClaimFlag.Name) {
+		cfg.KeyClaim = ctx.String(RPCRateLimitClaimFlag.Name)
+	}
+	if ctx.IsSet(RPCRateLimitCostsFlag.Name) {
+		costs, err := rpc.ParseMethodCosts(ctx.String(RPCRateLimitCostsFlag.Name))
+		if err != nil {
+			Fatalf("Option %q: %v", RPCRateLimitCostsFlag.Name, err)
+		}
+		merged := maps.Clone(cfg.MethodCosts)
+		if merged == nil {
+			merged = make(map[string]int)
+		}
+		maps.Copy(merged, costs)
+		cfg.MethodCosts = merged
+	}
+	if ctx.IsSet(RPCRateLimitQuotasFlag.Name) {
+		quotas, err := rpc.ParseRateQuotas(ctx.String(RPCRateLimitQuotasFlag.Name))
+		if err != nil {
+			Fatalf("Option %q: %v", RPCRateLimitQuotasFlag.Name, err)
+		}
+		cfg.Quotas = quotas
+	}
 }
 
 // setGraphQL creates the GraphQL listener interface string from the set
@@ -1688,6 +1865,12 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.Duration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.IsSet(TxPoolLifecycleSlotsFlag.Name) {
+		cfg.LifecycleSlots = ctx.Uint64(TxPoolLifecycleSlotsFlag.Name)
+	}
+	if ctx.IsSet(TxPoolAdmissionFlag.Name) {
+		cfg.Admission = ctx.String(TxPoolAdmissionFlag.Name)
+	}
 	if ctx.IsSet(MinerEffectiveGasLimitFlag.Name) {
 		// While technically this is a miner config parameter, we also want the txpool to enforce
 		// it to avoid accepting transactions that can never be included in a block.
@@ -1733,6 +1916,12 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.IsSet(RollupComputePendingBlock.Name) {
 		cfg.RollupComputePendingBlock = ctx.Bool(RollupComputePendingBlock.Name)
 	}
+	if ctx.IsSet(MinerOrderingPolicyFlag.Name) {
+		cfg.OrderingPolicy = ctx.String(MinerOrderingPolicyFlag.Name)
+		if _, err := miner.LookupOrderingPolicy(cfg.OrderingPolicy); err != nil {
+			Fatalf("Invalid --%s: %v, available: %s", MinerOrderingPolicyFlag.Name, err, strings.Join(miner.OrderingPolicies(), ", "))
+		}
+	}
 }
 
 func setCeloMiner(ctx *cli.Context, cfg *miner.Config, networkId uint64) {
@@ -1768,6 +1957,18 @@ func setCeloMiner(ctx *cli.Context, cfg *miner.Config, networkId uint64) {
 	}
 }
 
+func setCeloExchangeRateGuard(ctx *cli.Context, cfg *ethconfig.Config) {
+	guard := contracts.ExchangeRateGuardConfig{
+		MaxAge:    ctx.Duration(CeloFeeCurrencyMaxRateAge.Name),
+		MaxChange: ctx.Float64(CeloFeeCurrencyMaxRateChange.Name),
+	}
+	if guard.MaxChange != 0 && guard.MaxChange <= 1 {
+		Fatalf("Invalid fee currency max rate change %v, must be greater than 1", guard.MaxChange)
+	}
+	cfg.TxPool.ExchangeRateGuard = guard
+	cfg.Miner.ExchangeRateGuard = guard
+}
+
 func setRequiredBlocks(ctx *cli.Context, cfg *ethconfig.Config) {
 	requiredBlocks := ctx.String(EthRequiredBlocksFlag.Name)
 	if requiredBlocks == "" {
@@ -1940,6 +2141,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(CacheLogSizeFlag.Name) {
 		cfg.FilterLogCacheSize = ctx.Int(CacheLogSizeFlag.Name)
 	}
+	if ctx.IsSet(LogIndexFlag.Name) {
+		cfg.LogIndex = ctx.Bool(LogIndexFlag.Name)
+	}
+	if ctx.IsSet(RPCPersistentFiltersFlag.Name) {
+		cfg.PersistentFilters = ctx.Bool(RPCPersistentFiltersFlag.Name)
+	}
 	if !ctx.Bool(SnapshotFlag.Name) || cfg.SnapshotCache == 0 {
 		// If snap-sync is requested, this flag is also required
 		if cfg.SyncMode == downloader.SnapSync {
@@ -1994,6 +2201,23 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(RollupSequencerHTTPFlag.Name) && !ctx.IsSet(MiningEnabledFlag.Name) {
 		cfg.RollupSequencerHTTP = ctx.String(RollupSequencerHTTPFlag.Name)
 	}
+	if ctx.IsSet(RollupSequencerRetriesFlag.Name) {
+		cfg.RollupSequencerRetries = ctx.Int(RollupSequencerRetriesFlag.Name)
+	}
+	if ctx.IsSet(RollupSequencerOutboxTTLFlag.Name) {
+		cfg.RollupSequencerOutboxTTL = ctx.Duration(RollupSequencerOutboxTTLFlag.Name)
+	}
+	cfg.RollupSequencerTxConditionalEnabled = ctx.Bool(RollupSequencerTxConditionalEnabledFlag.Name)
+	if ctx.IsSet(RollupSequencerTxConditionalCostRateLimitFlag.Name) {
+		cfg.RollupSequencerTxConditionalCostRateLimit = ctx.Int(RollupSequencerTxConditionalCostRateLimitFlag.Name)
+	}
+	if cfg.RollupSequencerTxConditionalEnabled && cfg.RollupSequencerHTTP != "" {
+		Fatalf("--%s is only supported on the sequencer, not with --%s", RollupSequencerTxConditionalEnabledFlag.Name, RollupSequencerHTTPFlag.Name)
+	}
+	cfg.RollupSequencerBundlesEnabled = ctx.Bool(RollupSequencerBundlesEnabledFlag.Name)
+	if cfg.RollupSequencerBundlesEnabled && cfg.RollupSequencerHTTP != "" {
+		Fatalf("--%s is only supported on the sequencer, not with --%s", RollupSequencerBundlesEnabledFlag.Name, RollupSequencerHTTPFlag.Name)
+	}
 	if ctx.IsSet(RollupHistoricalRPCFlag.Name) {
 		cfg.RollupHistoricalRPC = ctx.String(RollupHistoricalRPCFlag.Name)
 	}
@@ -2140,6 +2364,7 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	}
 
 	setCeloMiner(ctx, &cfg.Miner, cfg.NetworkId)
+	setCeloExchangeRateGuard(ctx, cfg)
 }
 
 // SetDNSDiscoveryDefaults configures DNS discovery with the given URL if
@@ -2185,10 +2410,11 @@ func RegisterGraphQLService(stack *node.Node, backend ethapi.Backend, filterSyst
 func RegisterFilterAPI(stack *node.Node, backend ethapi.Backend, ethcfg *ethconfig.Config) *filters.FilterSystem {
 	filterSystem := filters.NewFilterSystem(backend, filters.Config{
 		LogCacheSize: ethcfg.FilterLogCacheSize,
+		Persistent:   ethcfg.PersistentFilters,
 	})
 	stack.RegisterAPIs([]rpc.API{{
 		Namespace: "eth",
-		Service:   filters.NewFilterAPI(filterSystem),
+		Service:   filters.NewStreamingFilterAPI(filterSystem),
 	}})
 	return filterSystem
 }
@@ -2199,6 +2425,24 @@ func RegisterFullSyncTester(stack *node.Node, eth *eth.Ethereum, target common.H
 	log.Info("Registered full-sync tester", "hash", target)
 }
 
+// SetupTelemetry enables tracing of RPC requests if requested.
+func SetupTelemetry(ctx *cli.Context) {
+	if !ctx.Bool(RPCTelemetryFlag.Name) {
+		return
+	}
+	config := telemetry.Config{
+		SampleRatio:    ctx.Float64(RPCTelemetrySampleFlag.Name),
+		RemoteSampling: ctx.Bool(RPCTelemetryRemoteSamplingFlag.Name),
+		File:           ctx.String(RPCTelemetryFileFlag.Name),
+		Endpoint:       ctx.String(RPCTelemetryEndpointFlag.Name),
+		ServiceName:    "geth",
+	}
+	if err := telemetry.Enable(config); err != nil {
+		Fatalf("Failed to enable RPC telemetry: %v", err)
+	}
+	log.Info("Enabled RPC telemetry", "sample", config.SampleRatio, "remotesampling", config.RemoteSampling, "file", config.File, "endpoint", config.Endpoint)
+}
+
 func SetupMetrics(ctx *cli.Context) {
 	if metrics.Enabled {
 		log.Info("Enabling metrics collection")
@@ -82,3 +82,16 @@ func (c *SizeConstrainedCache[K, V]) Get(key K) (V, bool) {
 
 	return c.lru.Get(key)
 }
+
+// Remove drops an item from the cache. Returns true if the key was present.
+func (c *SizeConstrainedCache[K, V]) Remove(key K) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	value, ok := c.lru.Peek(key)
+	if ok {
+		c.size -= uint64(len(value))
+		c.lru.Remove(key)
+	}
+	return ok
+}
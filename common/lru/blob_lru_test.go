@@ -153,3 +153,23 @@ func TestSizeConstrainedCacheEmpties(t *testing.T) {
 		}
 	}
 }
+
+// This test checks that removing an item releases its size.
+func TestSizeConstrainedCacheRemove(t *testing.T) {
+	lru := NewSizeConstrainedCache[testKey, []byte](100)
+	lru.Add(mkKey(0), []byte("value-0000"))
+	lru.Add(mkKey(1), []byte("value-0001"))
+
+	if !lru.Remove(mkKey(0)) {
+		t.Fatal("existing item not removed")
+	}
+	if lru.Remove(mkKey(0)) {
+		t.Fatal("missing item removed")
+	}
+	if _, ok := lru.Get(mkKey(0)); ok {
+		t.Fatal("removed item still present")
+	}
+	if have, want := lru.size, uint64(10); have != want {
+		t.Fatalf("size wrong, have %d want %d", have, want)
+	}
+}
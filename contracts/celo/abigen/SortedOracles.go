@@ -0,0 +1,257 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package abigen
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// SortedOraclesMetaData contains all meta data concerning the SortedOracles contract.
+var SortedOraclesMetaData = &bind.MetaData{
+	ABI: "[{\"type\":\"function\",\"name\":\"getExchangeRate\",\"inputs\":[{\"name\":\"token\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"numerator\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"denominator\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"medianTimestamp\",\"inputs\":[{\"name\":\"token\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"}]",
+}
+
+// SortedOraclesABI is the input ABI used to generate the binding from.
+// Deprecated: Use SortedOraclesMetaData.ABI instead.
+var SortedOraclesABI = SortedOraclesMetaData.ABI
+
+// SortedOracles is an auto generated Go binding around an Ethereum contract.
+type SortedOracles struct {
+	SortedOraclesCaller     // Read-only binding to the contract
+	SortedOraclesTransactor // Write-only binding to the contract
+	SortedOraclesFilterer   // Log filterer for contract events
+}
+
+// SortedOraclesCaller is an auto generated read-only Go binding around an Ethereum contract.
+type SortedOraclesCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SortedOraclesTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type SortedOraclesTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SortedOraclesFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type SortedOraclesFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SortedOraclesSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type SortedOraclesSession struct {
+	Contract     *SortedOracles    // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// SortedOraclesCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type SortedOraclesCallerSession struct {
+	Contract *SortedOraclesCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts        // Call options to use throughout this session
+}
+
+// SortedOraclesTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type SortedOraclesTransactorSession struct {
+	Contract     *SortedOraclesTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts        // Transaction auth options to use throughout this session
+}
+
+// SortedOraclesRaw is an auto generated low-level Go binding around an Ethereum contract.
+type SortedOraclesRaw struct {
+	Contract *SortedOracles // Generic contract binding to access the raw methods on
+}
+
+// SortedOraclesCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type SortedOraclesCallerRaw struct {
+	Contract *SortedOraclesCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// SortedOraclesTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type SortedOraclesTransactorRaw struct {
+	Contract *SortedOraclesTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewSortedOracles creates a new instance of SortedOracles, bound to a specific deployed contract.
+func NewSortedOracles(address common.Address, backend bind.ContractBackend) (*SortedOracles, error) {
+	contract, err := bindSortedOracles(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &SortedOracles{SortedOraclesCaller: SortedOraclesCaller{contract: contract}, SortedOraclesTransactor: SortedOraclesTransactor{contract: contract}, SortedOraclesFilterer: SortedOraclesFilterer{contract: contract}}, nil
+}
+
+// NewSortedOraclesCaller creates a new read-only instance of SortedOracles, bound to a specific deployed contract.
+func NewSortedOraclesCaller(address common.Address, caller bind.ContractCaller) (*SortedOraclesCaller, error) {
+	contract, err := bindSortedOracles(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SortedOraclesCaller{contract: contract}, nil
+}
+
+// NewSortedOraclesTransactor creates a new write-only instance of SortedOracles, bound to a specific deployed contract.
+func NewSortedOraclesTransactor(address common.Address, transactor bind.ContractTransactor) (*SortedOraclesTransactor, error) {
+	contract, err := bindSortedOracles(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SortedOraclesTransactor{contract: contract}, nil
+}
+
+// NewSortedOraclesFilterer creates a new log filterer instance of SortedOracles, bound to a specific deployed contract.
+func NewSortedOraclesFilterer(address common.Address, filterer bind.ContractFilterer) (*SortedOraclesFilterer, error) {
+	contract, err := bindSortedOracles(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &SortedOraclesFilterer{contract: contract}, nil
+}
+
+// bindSortedOracles binds a generic wrapper to an already deployed contract.
+func bindSortedOracles(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := SortedOraclesMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_SortedOracles *SortedOraclesRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _SortedOracles.Contract.SortedOraclesCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_SortedOracles *SortedOraclesRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _SortedOracles.Contract.SortedOraclesTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_SortedOracles *SortedOraclesRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _SortedOracles.Contract.SortedOraclesTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_SortedOracles *SortedOraclesCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _SortedOracles.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_SortedOracles *SortedOraclesTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _SortedOracles.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_SortedOracles *SortedOraclesTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _SortedOracles.Contract.contract.Transact(opts, method, params...)
+}
+
+// GetExchangeRate is a free data retrieval call binding the contract method 0xefb7601d.
+//
+// Solidity: function getExchangeRate(address token) view returns(uint256 numerator, uint256 denominator)
+func (_SortedOracles *SortedOraclesCaller) GetExchangeRate(opts *bind.CallOpts, token common.Address) (struct {
+	Numerator   *big.Int
+	Denominator *big.Int
+}, error) {
+	var out []interface{}
+	err := _SortedOracles.contract.Call(opts, &out, "getExchangeRate", token)
+
+	outstruct := new(struct {
+		Numerator   *big.Int
+		Denominator *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Numerator = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	outstruct.Denominator = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetExchangeRate is a free data retrieval call binding the contract method 0xefb7601d.
+//
+// Solidity: function getExchangeRate(address token) view returns(uint256 numerator, uint256 denominator)
+func (_SortedOracles *SortedOraclesSession) GetExchangeRate(token common.Address) (struct {
+	Numerator   *big.Int
+	Denominator *big.Int
+}, error) {
+	return _SortedOracles.Contract.GetExchangeRate(&_SortedOracles.CallOpts, token)
+}
+
+// GetExchangeRate is a free data retrieval call binding the contract method 0xefb7601d.
+//
+// Solidity: function getExchangeRate(address token) view returns(uint256 numerator, uint256 denominator)
+func (_SortedOracles *SortedOraclesCallerSession) GetExchangeRate(token common.Address) (struct {
+	Numerator   *big.Int
+	Denominator *big.Int
+}, error) {
+	return _SortedOracles.Contract.GetExchangeRate(&_SortedOracles.CallOpts, token)
+}
+
+// MedianTimestamp is a free data retrieval call binding the contract method 0x071b48fc.
+//
+// Solidity: function medianTimestamp(address token) view returns(uint256)
+func (_SortedOracles *SortedOraclesCaller) MedianTimestamp(opts *bind.CallOpts, token common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _SortedOracles.contract.Call(opts, &out, "medianTimestamp", token)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// MedianTimestamp is a free data retrieval call binding the contract method 0x071b48fc.
+//
+// Solidity: function medianTimestamp(address token) view returns(uint256)
+func (_SortedOracles *SortedOraclesSession) MedianTimestamp(token common.Address) (*big.Int, error) {
+	return _SortedOracles.Contract.MedianTimestamp(&_SortedOracles.CallOpts, token)
+}
+
+// MedianTimestamp is a free data retrieval call binding the contract method 0x071b48fc.
+//
+// Solidity: function medianTimestamp(address token) view returns(uint256)
+func (_SortedOracles *SortedOraclesCallerSession) MedianTimestamp(token common.Address) (*big.Int, error) {
+	return _SortedOracles.Contract.MedianTimestamp(&_SortedOracles.CallOpts, token)
+}
@@ -4,6 +4,7 @@ import _ "embed"
 
 //go:generate go run ../../cmd/abigen --pkg abigen --out abigen/FeeCurrency.go --abi compiled/FeeCurrency.abi --type FeeCurrency
 //go:generate go run ../../cmd/abigen --pkg abigen --out abigen/FeeCurrencyDirectory.go --abi compiled/IFeeCurrencyDirectory.abi --type FeeCurrencyDirectory
+//go:generate go run ../../cmd/abigen --pkg abigen --out abigen/SortedOracles.go --abi compiled/ISortedOracles.abi --type SortedOracles
 
 //go:embed compiled/GoldToken.bin-runtime
 var CeloTokenBytecodeRaw []byte
@@ -15,13 +15,13 @@ done
 CONTRACTS_DIR=${CELO_MONOREPO:-~/celo-monorepo}/packages/protocol
 forge build --root "$CONTRACTS_DIR"
 
-for contract in GoldToken FeeCurrencyDirectory IFeeCurrencyDirectory MockOracle
+for contract in GoldToken FeeCurrencyDirectory IFeeCurrencyDirectory ISortedOracles MockOracle
 do
 	contract_json="$CONTRACTS_DIR/out/$contract.sol/$contract.json"
 	jq .abi "$contract_json" > "$SCRIPT_DIR/$contract.abi"
 	jq .deployedBytecode.object -r "$contract_json" > "$SCRIPT_DIR/$contract.bin-runtime"
 done
 
-# We only need the abi for the interface (IFeeCurrencyDirectory) and the
+# We only need the abi for the interfaces (IFeeCurrencyDirectory, ISortedOracles) and the
 # bytecode for the implementation (FeeCurrencyDirectory), so let's delete the other.
-rm "$SCRIPT_DIR/IFeeCurrencyDirectory.bin-runtime" "$SCRIPT_DIR/FeeCurrencyDirectory.abi"
+rm "$SCRIPT_DIR/IFeeCurrencyDirectory.bin-runtime" "$SCRIPT_DIR/ISortedOracles.bin-runtime" "$SCRIPT_DIR/FeeCurrencyDirectory.abi"
@@ -0,0 +1,179 @@
+package contracts
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/contracts/addresses"
+	"github.com/ethereum/go-ethereum/contracts/celo/abigen"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// exchangeRateHistory is the number of recent blocks for which the guard
+// remembers exchange rates to detect jumps between a block and its parent.
+const exchangeRateHistory = 64
+
+var (
+	staleRateCounter  = metrics.NewRegisteredCounter(feeCurrencyGasMetricsName+"/rate/stale", nil)
+	rateJumpCounter   = metrics.NewRegisteredCounter(feeCurrencyGasMetricsName+"/rate/jump", nil)
+	excludedRateGauge = metrics.NewRegisteredGauge(feeCurrencyGasMetricsName+"/rate/excluded", nil)
+)
+
+// ExchangeRateGuardConfig configures when the exchange rate of a fee currency
+// is considered unreliable. The zero value disables all checks.
+type ExchangeRateGuardConfig struct {
+	MaxAge    time.Duration // Maximum age of the oracle's median rate (0 = no limit)
+	MaxChange float64       // Maximum factor by which a rate may change between blocks (0 = no limit)
+}
+
+// Enabled returns whether any check is configured.
+func (c ExchangeRateGuardConfig) Enabled() bool {
+	return c.MaxAge > 0 || c.MaxChange > 0
+}
+
+// ExchangeRateGuard is a local node policy which excludes fee currencies with
+// stale or erratic exchange rates from txpool admission and block building.
+// It must never be used when validating imported blocks. A nil guard
+// excludes nothing.
+type ExchangeRateGuard struct {
+	config ExchangeRateGuardConfig
+	rates  *lru.Cache[common.Hash, common.ExchangeRates] // Rates by block hash, to compare against the parent
+	state  map[common.Address]exclusionReason            // Currencies excluded by the last check, to report changes only
+	lock   sync.Mutex
+
+	timestamps func(*CeloBackend, common.ExchangeRates) map[common.Address]uint64 // Oracle update times, replaceable for tests
+}
+
+// NewExchangeRateGuard creates an exchange rate guard, or returns nil if the
+// config doesn't enable any check.
+func NewExchangeRateGuard(config ExchangeRateGuardConfig) *ExchangeRateGuard {
+	if !config.Enabled() {
+		return nil
+	}
+	return &ExchangeRateGuard{
+		config: config,
+		rates:  lru.NewCache[common.Hash, common.ExchangeRates](exchangeRateHistory),
+		state:  make(map[common.Address]exclusionReason),
+
+		timestamps: getExchangeRateTimestamps,
+	}
+}
+
+// exclusionReason is the reason why the exchange rate of a currency is not trusted.
+type exclusionReason int
+
+const (
+	rateJumped exclusionReason = iota + 1
+	rateStale
+)
+
+// Excluded returns the fee currencies whose exchange rate should not be
+// trusted at time now, based on the state after the given header. Both
+// backend and rates must be based on that same state.
+//
+// Excluded is called for every block built and every txpool reset, so it only
+// logs and counts a currency when it gets excluded or admitted again.
+func (g *ExchangeRateGuard) Excluded(header *types.Header, now uint64, backend *CeloBackend, rates common.ExchangeRates) common.AddressSet {
+	excluded := common.AddressSet{}
+	if g == nil || header == nil {
+		return excluded
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	state := make(map[common.Address]exclusionReason)
+	g.rates.Add(header.Hash(), rates)
+	if g.config.MaxChange > 0 {
+		if parentRates, ok := g.rates.Get(header.ParentHash); ok {
+			for currency, rate := range rates {
+				parentRate, ok := parentRates[currency]
+				if !ok || !rateChangeExceeds(parentRate, rate, g.config.MaxChange) {
+					continue
+				}
+				if g.state[currency] != rateJumped {
+					log.Warn("Fee currency exchange rate jumped, excluding currency", "currency", currency, "block", header.Number, "parent", parentRate.FloatString(18), "rate", rate.FloatString(18))
+					rateJumpCounter.Inc(1)
+				}
+				state[currency] = rateJumped
+			}
+		}
+	}
+	if g.config.MaxAge > 0 {
+		maxAge := uint64(g.config.MaxAge.Seconds())
+		for currency, updated := range g.timestamps(backend, rates) {
+			if updated+maxAge >= now || state[currency] != 0 {
+				continue
+			}
+			if g.state[currency] != rateStale {
+				log.Warn("Fee currency exchange rate is stale, excluding currency", "currency", currency, "block", header.Number, "updated", updated, "age", now-updated)
+				staleRateCounter.Inc(1)
+			}
+			state[currency] = rateStale
+		}
+	}
+	for currency := range g.state {
+		if _, ok := state[currency]; !ok {
+			log.Info("Fee currency exchange rate is trusted again", "currency", currency, "block", header.Number)
+		}
+	}
+	g.state = state
+
+	for currency := range state {
+		excluded[currency] = struct{}{}
+	}
+	excludedRateGauge.Update(int64(len(excluded)))
+	return excluded
+}
+
+// rateChangeExceeds returns whether rate differs from prev by more than the
+// given factor in either direction.
+func rateChangeExceeds(prev, rate *big.Rat, factor float64) bool {
+	limit := new(big.Rat).SetFloat64(factor)
+	if limit == nil || prev.Sign() <= 0 || rate.Sign() <= 0 {
+		return false
+	}
+	change := new(big.Rat).Quo(rate, prev)
+	if change.Cmp(big.NewRat(1, 1)) < 0 {
+		change.Inv(change)
+	}
+	return change.Cmp(limit) > 0
+}
+
+// getExchangeRateTimestamps returns the time of the last median update of the
+// oracle configured in the FeeCurrencyDirectory for each of the given
+// currencies. Currencies whose oracle does not expose a timestamp are omitted.
+func getExchangeRateTimestamps(backend *CeloBackend, rates common.ExchangeRates) map[common.Address]uint64 {
+	timestamps := make(map[common.Address]uint64)
+	directory, err := abigen.NewFeeCurrencyDirectoryCaller(addresses.GetAddresses(backend.ChainConfig.ChainID).FeeCurrencyDirectory, backend)
+	if err != nil {
+		log.Error("Failed to access FeeCurrencyDirectory", "err", err)
+		return timestamps
+	}
+	for currency := range rates {
+		config, err := directory.GetCurrencyConfig(&bind.CallOpts{}, currency)
+		if err != nil {
+			log.Error("Failed to get currency config for fee currency", "err", err, "tokenAddress", currency.Hex())
+			continue
+		}
+		oracle, err := abigen.NewSortedOraclesCaller(config.Oracle, backend)
+		if err != nil {
+			continue
+		}
+		updated, err := oracle.MedianTimestamp(&bind.CallOpts{}, currency)
+		if err != nil {
+			log.Debug("Oracle does not provide median timestamp", "err", err, "tokenAddress", currency.Hex(), "oracle", config.Oracle.Hex())
+			continue
+		}
+		if !updated.IsUint64() {
+			continue
+		}
+		timestamps[currency] = updated.Uint64()
+	}
+	return timestamps
+}
@@ -0,0 +1,91 @@
+package contracts
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestRateChangeExceeds(t *testing.T) {
+	tests := []struct {
+		prev, rate *big.Rat
+		factor     float64
+		exceeds    bool
+	}{
+		{big.NewRat(1, 1), big.NewRat(1, 1), 1.5, false},
+		{big.NewRat(2, 1), big.NewRat(3, 1), 1.5, false},
+		{big.NewRat(2, 1), big.NewRat(4, 1), 1.5, true},
+		{big.NewRat(3, 1), big.NewRat(2, 1), 1.5, false},
+		{big.NewRat(4, 1), big.NewRat(2, 1), 1.5, true},
+	}
+	for i, tt := range tests {
+		if have := rateChangeExceeds(tt.prev, tt.rate, tt.factor); have != tt.exceeds {
+			t.Errorf("test %d: have %v, want %v", i, have, tt.exceeds)
+		}
+	}
+}
+
+func TestExchangeRateGuardJump(t *testing.T) {
+	var (
+		cusd   = common.HexToAddress("0xc05d")
+		ceur   = common.HexToAddress("0xce5e")
+		guard  = NewExchangeRateGuard(ExchangeRateGuardConfig{MaxChange: 2})
+		parent = &types.Header{Number: big.NewInt(1)}
+		child  = &types.Header{Number: big.NewInt(2), ParentHash: parent.Hash()}
+	)
+	excluded := guard.Excluded(parent, 0, nil, common.ExchangeRates{cusd: big.NewRat(2, 1), ceur: big.NewRat(1, 1)})
+	if len(excluded) != 0 {
+		t.Fatalf("unexpected exclusions without parent rates: %v", excluded)
+	}
+	excluded = guard.Excluded(child, 0, nil, common.ExchangeRates{cusd: big.NewRat(5, 1), ceur: big.NewRat(3, 2)})
+	if _, ok := excluded[cusd]; !ok || len(excluded) != 1 {
+		t.Fatalf("expected only cUSD to be excluded, got %v", excluded)
+	}
+	if excluded := (*ExchangeRateGuard)(nil).Excluded(child, 0, nil, nil); len(excluded) != 0 {
+		t.Fatalf("nil guard excluded currencies: %v", excluded)
+	}
+	if guard := NewExchangeRateGuard(ExchangeRateGuardConfig{}); guard != nil {
+		t.Fatal("expected disabled guard to be nil")
+	}
+}
+
+func TestExchangeRateGuardStale(t *testing.T) {
+	var (
+		cusd   = common.HexToAddress("0xc05d")
+		ceur   = common.HexToAddress("0xce5e")
+		celo   = common.HexToAddress("0xce10")
+		guard  = NewExchangeRateGuard(ExchangeRateGuardConfig{MaxAge: time.Minute})
+		header = &types.Header{Number: big.NewInt(1)}
+		rates  = common.ExchangeRates{cusd: big.NewRat(2, 1), ceur: big.NewRat(1, 1), celo: big.NewRat(1, 1)}
+	)
+	// The oracle of celo doesn't provide update times and is never considered stale
+	guard.timestamps = func(*CeloBackend, common.ExchangeRates) map[common.Address]uint64 {
+		return map[common.Address]uint64{cusd: 1000, ceur: 1030}
+	}
+	if excluded := guard.Excluded(header, 1060, nil, rates); len(excluded) != 0 {
+		t.Fatalf("unexpected exclusions of fresh rates: %v", excluded)
+	}
+	excluded := guard.Excluded(header, 1061, nil, rates)
+	if _, ok := excluded[cusd]; !ok || len(excluded) != 1 {
+		t.Fatalf("expected only cUSD to be excluded, got %v", excluded)
+	}
+	excluded = guard.Excluded(header, 2000, nil, rates)
+	if _, ok := excluded[ceur]; !ok || len(excluded) != 2 {
+		t.Fatalf("expected cUSD and cEUR to be excluded, got %v", excluded)
+	}	// Exclusions are only reported when a currency's state changes
+	if len(guard.state) != 2 || guard.state[cusd] != rateStale || guard.state[ceur] != rateStale {
+		t.Fatalf("unexpected exclusion state: %v", guard.state)
+	}
+	guard.timestamps = func(*CeloBackend, common.ExchangeRates) map[common.Address]uint64 {
+		return map[common.Address]uint64{cusd: 1000, ceur: 2000}
+	}
+	if excluded := guard.Excluded(header, 2000, nil, rates); len(excluded) != 1 {
+		t.Fatalf("expected cEUR to be admitted again, got %v", excluded)
+	}
+	if len(guard.state) != 1 || guard.state[cusd] != rateStale {
+		t.Fatalf("unexpected exclusion state after re-admission: %v", guard.state)
+	}
+}
@@ -167,6 +167,9 @@ func CreditFees(
 		// this will never happen
 		return fmt.Errorf("%w: %x", exchange.ErrUnregisteredFeeCurrency, feeCurrency)
 	}
+	if evm.Config.OnFeeCurrencyGas != nil {
+		evm.Config.OnFeeCurrencyGas(*feeCurrency, gasUsedDebit, gasUsed, intrinsicGas)
+	}
 	gasUsedForDebitAndCredit := gasUsedDebit + gasUsed
 	if gasUsedForDebitAndCredit > intrinsicGas {
 		log.Info(
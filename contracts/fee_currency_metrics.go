@@ -0,0 +1,210 @@
+package contracts
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// feeCurrencyGasMetricsName is the prefix of the per fee currency gas metrics.
+	feeCurrencyGasMetricsName = "contracts/feecurrency"
+
+	// FeeCurrencyGasStatsBlocks is the number of most recent blocks for which
+	// debit and credit gas observations are retained.
+	FeeCurrencyGasStatsBlocks = 1024
+)
+
+var (
+	intrinsicGasOverrunCounter = metrics.NewRegisteredCounter(feeCurrencyGasMetricsName+"/overrun", nil)
+
+	feeCurrencyGas = newFeeCurrencyGasStats(FeeCurrencyGasStatsBlocks)
+)
+
+func feeCurrencyGasSample() metrics.Sample {
+	return metrics.NewExpDecaySample(1028, 0.015)
+}
+
+// feeCurrencyGasObservation is the gas used by a single debit+credit pair.
+type feeCurrencyGasObservation struct {
+	feeCurrency  common.Address
+	gasDebit     uint64
+	gasCredit    uint64
+	intrinsicGas uint64
+}
+
+// FeeCurrencyGasRecorder collects the fee currency gas used while processing a
+// single block. The observations are only added to the metrics and statistics
+// by Commit, once the block was imported successfully.
+type FeeCurrencyGasRecorder struct {
+	observations []feeCurrencyGasObservation
+}
+
+// Record stores the gas used by a single debit+credit pair. It has the signature
+// of vm.Config.OnFeeCurrencyGas.
+func (r *FeeCurrencyGasRecorder) Record(feeCurrency common.Address, gasUsedDebit, gasUsedCredit, intrinsicGas uint64) {
+	r.observations = append(r.observations, feeCurrencyGasObservation{
+		feeCurrency:  feeCurrency,
+		gasDebit:     gasUsedDebit,
+		gasCredit:    gasUsedCredit,
+		intrinsicGas: intrinsicGas,
+	})
+}
+
+// Commit updates the fee currency gas metrics and the recent block statistics
+// with the observations of the imported block.
+func (r *FeeCurrencyGasRecorder) Commit(number uint64, hash common.Hash) {
+	block := make(map[common.Address]*FeeCurrencyGasStats)
+	for _, obs := range r.observations {
+		prefix := fmt.Sprintf("%s/%s", feeCurrencyGasMetricsName, obs.feeCurrency.Hex())
+		metrics.GetOrRegisterHistogramLazy(prefix+"/debit", nil, feeCurrencyGasSample).Update(int64(obs.gasDebit))
+		metrics.GetOrRegisterHistogramLazy(prefix+"/credit", nil, feeCurrencyGasSample).Update(int64(obs.gasCredit))
+
+		gasUsed := obs.gasDebit + obs.gasCredit
+		overrun := gasUsed > obs.intrinsicGas
+		if overrun {
+			intrinsicGasOverrunCounter.Inc(1)
+			metrics.GetOrRegisterCounter(prefix+"/overrun", nil).Inc(1)
+		}
+		stats, ok := block[obs.feeCurrency]
+		if !ok {
+			stats = new(FeeCurrencyGasStats)
+			block[obs.feeCurrency] = stats
+		}
+		stats.add(gasUsed, overrun)
+	}
+	feeCurrencyGas.add(number, hash, block)
+	r.observations = nil
+}
+
+// FeeCurrencyGasStats aggregates the gas used for debiting and crediting
+// fees in a single fee currency.
+type FeeCurrencyGasStats struct {
+	Count    uint64 // Number of debit+credit pairs observed
+	Min      uint64 // Minimum gas used by a debit+credit pair
+	Max      uint64 // Maximum gas used by a debit+credit pair
+	Sum      uint64 // Total gas used by all observed debit+credit pairs
+	Overruns uint64 // Number of pairs exceeding the configured intrinsic gas
+}
+
+func (s *FeeCurrencyGasStats) add(gasUsed uint64, overrun bool) {
+	if s.Count == 0 || gasUsed < s.Min {
+		s.Min = gasUsed
+	}
+	if gasUsed > s.Max {
+		s.Max = gasUsed
+	}
+	s.Count++
+	if s.Sum+gasUsed >= s.Sum {
+		s.Sum += gasUsed
+	} else {
+		s.Sum = math.MaxUint64
+	}
+	if overrun {
+		s.Overruns++
+	}
+}
+
+func (s *FeeCurrencyGasStats) merge(o *FeeCurrencyGasStats) {
+	if o.Count == 0 {
+		return
+	}
+	if s.Count == 0 || o.Min < s.Min {
+		s.Min = o.Min
+	}
+	if o.Max > s.Max {
+		s.Max = o.Max
+	}
+	s.Count += o.Count
+	if s.Sum+o.Sum >= s.Sum {
+		s.Sum += o.Sum
+	} else {
+		s.Sum = math.MaxUint64
+	}
+	s.Overruns += o.Overruns
+}
+
+// Avg returns the average gas used by a debit+credit pair.
+func (s *FeeCurrencyGasStats) Avg() uint64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / s.Count
+}
+
+// feeCurrencyGasStats keeps per block fee currency gas statistics for a
+// bounded window of recent blocks. Blocks are tracked by hash, so blocks
+// reorged out of the chain can be excluded from reports.
+type feeCurrencyGasStats struct {
+	limit  uint64
+	blocks map[uint64]map[common.Hash]map[common.Address]*FeeCurrencyGasStats
+	head   uint64
+	lock   sync.Mutex
+}
+
+func newFeeCurrencyGasStats(limit uint64) *feeCurrencyGasStats {
+	return &feeCurrencyGasStats{
+		limit:  limit,
+		blocks: make(map[uint64]map[common.Hash]map[common.Address]*FeeCurrencyGasStats),
+	}
+}
+
+func (s *feeCurrencyGasStats) add(number uint64, hash common.Hash, block map[common.Address]*FeeCurrencyGasStats) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if number+s.limit <= s.head {
+		return // too old to be retained
+	}
+	if number > s.head {
+		s.head = number
+		for n := range s.blocks {
+			if n+s.limit <= s.head {
+				delete(s.blocks, n)
+			}
+		}
+	}
+	blocks, ok := s.blocks[number]
+	if !ok {
+		blocks = make(map[common.Hash]map[common.Address]*FeeCurrencyGasStats)
+		s.blocks[number] = blocks
+	}
+	blocks[hash] = block
+}
+
+func (s *feeCurrencyGasStats) report(from, to uint64, canonical func(uint64) common.Hash) map[common.Address]*FeeCurrencyGasStats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	report := make(map[common.Address]*FeeCurrencyGasStats)
+	for number, blocks := range s.blocks {
+		if number < from || number > to {
+			continue
+		}
+		block, ok := blocks[canonical(number)]
+		if !ok {
+			continue
+		}
+		for currency, stats := range block {
+			total, ok := report[currency]
+			if !ok {
+				total = new(FeeCurrencyGasStats)
+				report[currency] = total
+			}
+			total.merge(stats)
+		}
+	}
+	return report
+}
+
+// FeeCurrencyGasReport returns the debit+credit gas statistics per fee
+// currency observed by this node while importing the canonical blocks in the
+// inclusive range [from, to]. The canonical function returns the hash of the
+// canonical block with the given number. Only the most recent
+// FeeCurrencyGasStatsBlocks blocks are retained.
+func FeeCurrencyGasReport(from, to uint64, canonical func(uint64) common.Hash) map[common.Address]*FeeCurrencyGasStats {
+	return feeCurrencyGas.report(from, to, canonical)
+}
@@ -0,0 +1,80 @@
+package contracts
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestFeeCurrencyGasStats(t *testing.T) {
+	var (
+		cusd      = common.HexToAddress("0xc05d")
+		ceur      = common.HexToAddress("0xce5e")
+		stats     = newFeeCurrencyGasStats(3)
+		canonical = map[uint64]common.Hash{1: {0x1}, 2: {0x2}, 4: {0x4}}
+		lookup    = func(n uint64) common.Hash { return canonical[n] }
+		block     = func(obs ...feeCurrencyGasObservation) map[common.Address]*FeeCurrencyGasStats {
+			b := make(map[common.Address]*FeeCurrencyGasStats)
+			for _, o := range obs {
+				s, ok := b[o.feeCurrency]
+				if !ok {
+					s = new(FeeCurrencyGasStats)
+					b[o.feeCurrency] = s
+				}
+				s.add(o.gasDebit+o.gasCredit, o.gasDebit+o.gasCredit > o.intrinsicGas)
+			}
+			return b
+		}
+	)
+	stats.add(1, common.Hash{0x1}, block(feeCurrencyGasObservation{cusd, 5_000, 5_000, 25_000}))
+	stats.add(2, common.Hash{0x2}, block(
+		feeCurrencyGasObservation{cusd, 15_000, 15_000, 25_000},
+		feeCurrencyGasObservation{cusd, 10_000, 10_000, 25_000},
+		feeCurrencyGasObservation{ceur, 25_000, 25_000, 25_000},
+	))
+	// Blocks reorged out of the chain are not reported
+	stats.add(2, common.Hash{0xff}, block(feeCurrencyGasObservation{cusd, 1, 0, 25_000}))
+
+	report := stats.report(1, 2, lookup)
+	if have := *report[cusd]; have != (FeeCurrencyGasStats{Count: 3, Min: 10_000, Max: 30_000, Sum: 60_000, Overruns: 1}) {
+		t.Fatalf("unexpected cUSD stats: %+v", have)
+	}
+	if have := report[cusd].Avg(); have != 20_000 {
+		t.Fatalf("unexpected cUSD average: %d", have)
+	}
+	if have := report[ceur].Count; have != 1 {
+		t.Fatalf("unexpected cEUR count: %d", have)
+	}
+
+	// Moving the head evicts blocks outside of the retention window
+	stats.add(4, common.Hash{0x4}, block(feeCurrencyGasObservation{cusd, 20_000, 20_000, 50_000}))
+	report = stats.report(0, 4, lookup)
+	if have := *report[cusd]; have != (FeeCurrencyGasStats{Count: 3, Min: 20_000, Max: 40_000, Sum: 90_000, Overruns: 1}) {
+		t.Fatalf("unexpected cUSD stats after eviction: %+v", have)
+	}
+	// Observations for evicted blocks are dropped
+	stats.add(1, common.Hash{0x1}, block(feeCurrencyGasObservation{cusd, 1, 0, 25_000}))
+	if have := stats.report(0, 4, lookup)[cusd].Min; have != 20_000 {
+		t.Fatalf("old observation was retained: min %d", have)
+	}
+}
+
+func TestFeeCurrencyGasRecorder(t *testing.T) {
+	var (
+		cusd     = common.HexToAddress("0xc05d")
+		recorder = new(FeeCurrencyGasRecorder)
+		hash     = common.Hash{0xaa}
+		number   = uint64(1 << 40) // avoid clashing with other blocks in the global stats
+		lookup   = func(uint64) common.Hash { return hash }
+	)
+	recorder.Record(cusd, 20_000, 15_000, 50_000)
+	recorder.Record(cusd, 30_000, 25_000, 50_000)
+	if report := FeeCurrencyGasReport(number, number, lookup); len(report) != 0 {
+		t.Fatalf("observations reported before commit: %v", report)
+	}
+	recorder.Commit(number, hash)
+	report := FeeCurrencyGasReport(number, number, lookup)
+	if have := *report[cusd]; have != (FeeCurrencyGasStats{Count: 2, Min: 35_000, Max: 55_000, Sum: 90_000, Overruns: 1}) {
+		t.Fatalf("unexpected cUSD stats: %+v", have)
+	}
+}
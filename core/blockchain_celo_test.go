@@ -144,3 +144,108 @@ func testNativeTransferWithFeeCurrency(t *testing.T, scheme string, feeCurrencyA
 		t.Fatalf("fee handler balance incorrect: expected %d, got %d", expected, actual)
 	}
 }
+
+// TestNativeTransferWithCeloDenominatedTx tests that the fees of a CIP-66
+// transaction, which are denominated in the native token, are converted into
+// the fee currency when they are debited and credited.
+func TestNativeTransferWithCeloDenominatedTx(t *testing.T) {
+	testNativeTransferWithCeloDenominatedTx(t, rawdb.HashScheme, nil)
+}
+
+// Test that a CIP-66 transaction is invalid if its maximum fee, converted into
+// the fee currency, exceeds maxFeeInFeeCurrency.
+func TestNativeTransferWithCeloDenominatedTxAndTooLowMaxFee(t *testing.T) {
+	assert.PanicsWithError(t, "max fee exceeds maxFeeInFeeCurrency: address 0x71562b71999873DB5b286dF957af199Ec94617F7, max fee 350000000000000, maxFeeInFeeCurrency 349999999999999, fee currency: "+DevFeeCurrencyAddr.Hex(),
+		func() { testNativeTransferWithCeloDenominatedTx(t, rawdb.HashScheme, big.NewInt(349999999999999)) },
+	)
+}
+
+func testNativeTransferWithCeloDenominatedTx(t *testing.T, scheme string, maxFeeInFeeCurrency *big.Int) {
+	var (
+		aa     = common.HexToAddress("0x000000000000000000000000000000000000aaaa")
+		engine = ethash.NewFaker()
+
+		// A sender who makes transactions, has some funds
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		config  = *params.AllEthashProtocolChanges
+		funds   = DevBalance
+		gspec   = &Genesis{
+			Config: &config,
+			Alloc:  celoGenesisAccounts(addr1),
+		}
+		feeCurrencyAddr = DevFeeCurrencyAddr
+	)
+	gspec.Config.Cel2Time = uint64ptr(0)
+	gspec.Config.Cip66Time = uint64ptr(0)
+	if maxFeeInFeeCurrency == nil {
+		maxFeeInFeeCurrency = new(big.Int).Set(funds)
+	}
+
+	signer := types.LatestSigner(gspec.Config)
+
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 1, func(i int, b *BlockGen) {
+		b.SetCoinbase(common.Address{1})
+
+		txdata := &types.CeloDenominatedTx{
+			ChainID:             gspec.Config.ChainID,
+			Nonce:               0,
+			To:                  &aa,
+			Gas:                 100000,
+			GasFeeCap:           new(big.Int).Mul(b.header.BaseFee, big.NewInt(2)),
+			GasTipCap:           big.NewInt(2),
+			Data:                []byte{},
+			FeeCurrency:         &feeCurrencyAddr,
+			MaxFeeInFeeCurrency: maxFeeInFeeCurrency,
+		}
+		tx := types.NewTx(txdata)
+		tx, _ = types.SignTx(tx, signer, key1)
+
+		b.AddTx(tx)
+	})
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), DefaultCacheConfigWithScheme(scheme), gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+
+	block := chain.GetBlockByNumber(1)
+	state, _ := chain.State()
+
+	backend := contracts.CeloBackend{
+		ChainConfig: chain.chainConfig,
+		State:       state,
+	}
+	exchangeRates, err := contracts.GetExchangeRates(&backend)
+	if err != nil {
+		t.Fatal("could not get exchange rates")
+	}
+	gasUsed := new(big.Int).SetUint64(block.GasUsed())
+	gasPrice := new(big.Int).Add(block.BaseFee(), block.Transactions()[0].GasTipCap())
+	totalFee, _ := exchange.ConvertCeloToCurrency(exchangeRates, &feeCurrencyAddr, new(big.Int).Mul(gasUsed, gasPrice))
+	baseFee, _ := exchange.ConvertCeloToCurrency(exchangeRates, &feeCurrencyAddr, new(big.Int).Mul(gasUsed, block.BaseFee()))
+
+	// The miner received the converted tip.
+	actual, _ := contracts.GetBalanceERC20(&backend, block.Coinbase(), feeCurrencyAddr)
+	expected := new(big.Int).Sub(totalFee, baseFee)
+	if actual.Cmp(expected) != 0 {
+		t.Fatalf("miner balance incorrect: expected %d, got %d", expected, actual)
+	}
+
+	// The sender paid for the gas used in the fee currency.
+	actual, _ = contracts.GetBalanceERC20(&backend, addr1, feeCurrencyAddr)
+	actual = new(big.Int).Sub(funds, actual)
+	if actual.Cmp(totalFee) != 0 {
+		t.Fatalf("sender balance incorrect: expected %d, got %d", totalFee, actual)
+	}
+
+	// The converted base fee has been moved to the fee handler.
+	actual, _ = contracts.GetBalanceERC20(&backend, addresses.MainnetAddresses.FeeHandler, feeCurrencyAddr)
+	if actual.Cmp(baseFee) != 0 {
+		t.Fatalf("fee handler balance incorrect: expected %d, got %d", baseFee, actual)
+	}
+}
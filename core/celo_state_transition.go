@@ -21,6 +21,70 @@ func (msg *Message) IsFeeCurrencyDenominated() bool {
 	return msg.FeeCurrency != nil && msg.MaxFeeInFeeCurrency == nil
 }
 
+// DenominationCurrency returns the currency in which the gas-price related
+// fields are denominated, nil meaning the native token.
+func (msg *Message) DenominationCurrency() *common.Address {
+	if !msg.IsFeeCurrencyDenominated() {
+		return nil
+	}
+	return msg.FeeCurrency
+}
+
+// convertNativeFees converts the fees of a CIP-66 transaction, which are
+// denominated in the native token, into its fee currency. It returns the fee
+// to debit and the maximum fee to check the balance against, and fails if the
+// latter exceeds the transaction's maxFeeInFeeCurrency.
+func (st *StateTransition) convertNativeFees(l1Cost *big.Int) (fee, maxFee *big.Int, err error) {
+	rates := st.evm.Context.FeeCurrencyContext.ExchangeRates
+	gas := new(big.Int).SetUint64(st.msg.GasLimit)
+	fee, err = exchange.ConvertCeloToCurrency(rates, st.msg.FeeCurrency, new(big.Int).Mul(gas, st.msg.GasPrice))
+	if err != nil {
+		return nil, nil, err
+	}
+	gasFeeCap := st.msg.GasFeeCap
+	if gasFeeCap == nil {
+		gasFeeCap = st.msg.GasPrice
+	}
+	maxFee, err = exchange.ConvertCeloToCurrency(rates, st.msg.FeeCurrency, new(big.Int).Mul(gas, gasFeeCap))
+	if err != nil {
+		return nil, nil, err
+	}
+	if l1Cost != nil {
+		l1CostInFeeCurrency, err := exchange.ConvertCeloToCurrency(rates, st.msg.FeeCurrency, l1Cost)
+		if err != nil {
+			return nil, nil, err
+		}
+		fee.Add(fee, l1CostInFeeCurrency)
+		maxFee.Add(maxFee, l1CostInFeeCurrency)
+	}
+	if maxFee.Cmp(st.msg.MaxFeeInFeeCurrency) > 0 {
+		return nil, nil, fmt.Errorf("%w: address %v, max fee %v, maxFeeInFeeCurrency %v, fee currency: %v", ErrMaxFeeInFeeCurrencyExceeded,
+			st.msg.From.Hex(), maxFee, st.msg.MaxFeeInFeeCurrency, st.msg.FeeCurrency.Hex())
+	}
+	return fee, maxFee, nil
+}
+
+// convertNativeTxFees converts the refund, tip and base fee of a CIP-66
+// transaction into its fee currency. The amounts are derived from the
+// converted total fee, so that they add up to the amount debited in
+// convertNativeFees (apart from the L1 cost) despite rounding.
+func (st *StateTransition) convertNativeTxFees(refund, totalTxFee, baseTxFee *big.Int) (*big.Int, *big.Int, *big.Int, error) {
+	rates := st.evm.Context.FeeCurrencyContext.ExchangeRates
+	fee, err := exchange.ConvertCeloToCurrency(rates, st.msg.FeeCurrency, new(big.Int).Add(refund, totalTxFee))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	total, err := exchange.ConvertCeloToCurrency(rates, st.msg.FeeCurrency, totalTxFee)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	base, err := exchange.ConvertCeloToCurrency(rates, st.msg.FeeCurrency, baseTxFee)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return fee.Sub(fee, total), total.Sub(total, base), base, nil
+}
+
 // canPayFee checks whether accountOwner's balance can cover transaction fee.
 func (st *StateTransition) canPayFee(checkAmountForGas *big.Int) error {
 	var checkAmountInCelo, checkAmountInAlternativeCurrency *big.Int
@@ -147,6 +211,12 @@ func (st *StateTransition) distributeTxFees() error {
 			st.state.AddBalance(params.OptimismL1FeeRecipient, l1CostU256, tracing.BalanceIncreaseRewardTransactionFee)
 		}
 	} else {
+		if st.msg.MaxFeeInFeeCurrency != nil {
+			var err error
+			if refund, tipTxFee, baseTxFee, err = st.convertNativeTxFees(refund, totalTxFee, baseTxFee); err != nil {
+				return err
+			}
+		}
 		if l1Cost != nil {
 			l1Cost, _ = exchange.ConvertCeloToCurrency(st.evm.Context.FeeCurrencyContext.ExchangeRates, feeCurrency, l1Cost)
 		}
@@ -176,7 +246,7 @@ func (st *StateTransition) distributeTxFees() error {
 
 // calculateBaseFee returns the correct base fee to use during fee calculations
 // This is the base fee from the header if no fee currency is used, but the
-// base fee converted to fee currency when a fee currency is used.
+// base fee converted to fee currency when the fees are denominated in a fee currency.
 func (st *StateTransition) calculateBaseFee() *big.Int {
 	baseFee := st.evm.Context.BaseFee
 	if baseFee == nil {
@@ -184,7 +254,7 @@ func (st *StateTransition) calculateBaseFee() *big.Int {
 		baseFee = big.NewInt(0)
 	}
 
-	if st.msg.FeeCurrency != nil {
+	if st.msg.IsFeeCurrencyDenominated() {
 		// Existence of the fee currency has been checked in `preCheck`
 		baseFee, _ = exchange.ConvertCeloToCurrency(st.evm.Context.FeeCurrencyContext.ExchangeRates, st.msg.FeeCurrency, baseFee)
 	}
@@ -118,4 +118,17 @@ var (
 
 	// ErrCel2NotEnabled is returned if a feature requires the Cel2 fork, but that is not enabled.
 	ErrCel2NotEnabled = errors.New("required cel2 fork not enabled")
+
+	// ErrCip66NotEnabled is returned if a CIP-66 transaction is included
+	// before the CIP-66 fork is enabled.
+	ErrCip66NotEnabled = errors.New("required cip66 fork not enabled")
+
+	// ErrCip66MissingFeeCurrency is returned if a CIP-66 transaction does not
+	// specify the fee currency to pay its fees with.
+	ErrCip66MissingFeeCurrency = errors.New("cip66 transaction without fee currency")
+
+	// ErrMaxFeeInFeeCurrencyExceeded is returned if the maximum fee of a CIP-66
+	// transaction, converted into its fee currency, exceeds its
+	// maxFeeInFeeCurrency.
+	ErrMaxFeeInFeeCurrencyExceeded = errors.New("max fee exceeds maxFeeInFeeCurrency")
 )
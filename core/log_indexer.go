@@ -0,0 +1,123 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+const (
+	// logIndexThrottling is the time to wait between processing two consecutive
+	// log index sections, to prevent disk overload while catching up.
+	logIndexThrottling = 100 * time.Millisecond
+)
+
+// logIndexKey identifies the logs of an address, or of an address and first topic.
+type logIndexKey struct {
+	address  common.Address
+	topic    common.Hash
+	hasTopic bool
+}
+
+// LogIndexer implements a core.ChainIndexer, building an index of the positions of
+// logs by emitting address and by address and first topic (the event signature),
+// so log queries don't have to check the receipts of every bloom filter match.
+type LogIndexer struct {
+	db      ethdb.Database                      // database instance to read receipts from and write the index into
+	section uint64                              // section number being processed currently
+	head    common.Hash                         // hash of the last header processed
+	entries map[logIndexKey][]rawdb.LogPosition // log positions of the current section
+}
+
+// NewLogIndexer returns a chain indexer that generates the log index for the
+// canonical chain.
+func NewLogIndexer(db ethdb.Database, size, confirms uint64) *ChainIndexer {
+	backend := &LogIndexer{
+		db: db,
+	}
+	table := rawdb.NewTable(db, string(rawdb.LogIndexIndexPrefix))
+
+	return NewChainIndexer(db, table, backend, size, confirms, logIndexThrottling, "logindex")
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new log index section.
+func (l *LogIndexer) Reset(ctx context.Context, section uint64, lastSectionHead common.Hash) error {
+	l.section, l.head = section, common.Hash{}
+	l.entries = make(map[logIndexKey][]rawdb.LogPosition)
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, adding the logs of a new header
+// into the index.
+func (l *LogIndexer) Process(ctx context.Context, header *types.Header) error {
+	var (
+		hash   = header.Hash()
+		number = header.Number.Uint64()
+		logs   = rawdb.ReadLogs(l.db, hash, number)
+	)
+	if logs == nil && header.ReceiptHash != types.EmptyReceiptsHash {
+		return fmt.Errorf("receipts of block #%d [%x..] not found", number, hash[:4])
+	}
+	var index uint
+	for _, txLogs := range logs {
+		for _, log := range txLogs {
+			pos := rawdb.LogPosition{Number: number, Index: index}
+			key := logIndexKey{address: log.Address}
+			l.entries[key] = append(l.entries[key], pos)
+			if len(log.Topics) > 0 {
+				key := logIndexKey{address: log.Address, topic: log.Topics[0], hasTopic: true}
+				l.entries[key] = append(l.entries[key], pos)
+			}
+			index++
+		}
+	}
+	l.head = hash
+	return nil
+}
+
+// Commit implements core.ChainIndexerBackend, writing the log index of the section
+// into the database.
+func (l *LogIndexer) Commit() error {
+	batch := l.db.NewBatch()
+	for key, positions := range l.entries {
+		var topic *common.Hash
+		if key.hasTopic {
+			topic = &key.topic
+		}
+		rawdb.WriteLogIndex(batch, key.address, topic, l.section, l.head, positions)
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	rawdb.WriteLogIndexSection(batch, l.section, l.head)
+	return batch.Write()
+}
+
+// Prune returns an empty error since we don't support pruning here.
+func (l *LogIndexer) Prune(threshold uint64) error {
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ReadFilters retrieves all persistent RPC filters, keyed by their id.
+func ReadFilters(db ethdb.Iteratee) map[string][]byte {
+	filters := make(map[string][]byte)
+	it := db.NewIterator(filterPrefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		id := string(it.Key()[len(filterPrefix):])
+		filters[id] = common.CopyBytes(it.Value())
+	}
+	return filters
+}
+
+// WriteFilter stores the serialized persistent RPC filter with the given id.
+func WriteFilter(db ethdb.KeyValueWriter, id string, filter []byte) {
+	if err := db.Put(filterKey(id), filter); err != nil {
+		log.Crit("Failed to store RPC filter", "err", err)
+	}
+}
+
+// DeleteFilter removes the persistent RPC filter with the given id.
+func DeleteFilter(db ethdb.KeyValueWriter, id string) {
+	if err := db.Delete(filterKey(id)); err != nil {
+		log.Crit("Failed to delete RPC filter", "err", err)
+	}
+}
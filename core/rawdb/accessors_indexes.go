@@ -18,6 +18,8 @@ package rawdb
 
 import (
 	"bytes"
+	"encoding/binary"
+	"errors"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -179,3 +181,67 @@ func DeleteBloombits(db ethdb.Database, bit uint, from uint64, to uint64) {
 		log.Crit("Failed to delete bloom bits", "err", it.Error())
 	}
 }
+
+// LogPosition is the position of a log in the chain, stored in the log index.
+type LogPosition struct {
+	Number uint64 // block number
+	Index  uint   // index of the log in the block
+}
+
+// ReadLogIndex retrieves the positions of the logs emitted by the address within
+// the given section. If topic is not nil, only logs with the given first topic are
+// included. The positions are in chain order.
+func ReadLogIndex(db ethdb.KeyValueReader, address common.Address, topic *common.Hash, section uint64, head common.Hash) ([]LogPosition, error) {
+	data, _ := db.Get(logIndexKey(address, topic, section, head))
+	var (
+		positions []LogPosition
+		number    uint64
+	)
+	for len(data) > 0 {
+		delta, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errors.New("invalid log index entry")
+		}
+		index, m := binary.Uvarint(data[n:])
+		if m <= 0 {
+			return nil, errors.New("invalid log index entry")
+		}
+		number += delta
+		positions = append(positions, LogPosition{Number: number, Index: uint(index)})
+		data = data[n+m:]
+	}
+	return positions, nil
+}
+
+// WriteLogIndex stores the positions of the logs emitted by the address, or with
+// the address and first topic, within the given section. The positions must be in
+// chain order.
+func WriteLogIndex(db ethdb.KeyValueWriter, address common.Address, topic *common.Hash, section uint64, head common.Hash, positions []LogPosition) {
+	var (
+		enc    []byte
+		number uint64
+	)
+	for _, pos := range positions {
+		enc = binary.AppendUvarint(enc, pos.Number-number)
+		enc = binary.AppendUvarint(enc, uint64(pos.Index))
+		number = pos.Number
+	}
+	if err := db.Put(logIndexKey(address, topic, section, head), enc); err != nil {
+		log.Crit("Failed to store log index", "err", err)
+	}
+}
+
+// HasLogIndexSection checks if the log index of the section with the given head
+// has been written.
+func HasLogIndexSection(db ethdb.KeyValueReader, section uint64, head common.Hash) bool {
+	ok, _ := db.Has(logIndexSectionKey(section, head))
+	return ok
+}
+
+// WriteLogIndexSection marks the log index of the section with the given head as
+// complete.
+func WriteLogIndexSection(db ethdb.KeyValueWriter, section uint64, head common.Hash) {
+	if err := db.Put(logIndexSectionKey(section, head), nil); err != nil {
+		log.Crit("Failed to store log index section", "err", err)
+	}
+}
@@ -107,6 +107,8 @@ var (
 
 	txLookupPrefix        = []byte("l") // txLookupPrefix + hash -> transaction/receipt lookup metadata
 	bloomBitsPrefix       = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
+	logIndexPrefix        = []byte("x") // logIndexPrefix + address + [topic0] + section (uint64 big endian) + hash -> log positions
+	logIndexSectionPrefix = []byte("X") // logIndexSectionPrefix + section (uint64 big endian) + hash -> empty marker of an indexed section
 	SnapshotAccountPrefix = []byte("a") // SnapshotAccountPrefix + account hash -> account trie value
 	SnapshotStoragePrefix = []byte("o") // SnapshotStoragePrefix + account hash + storage hash -> storage trie value
 	CodePrefix            = []byte("c") // CodePrefix + code hash -> account code
@@ -131,6 +133,9 @@ var (
 	// BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
 	BloomBitsIndexPrefix = []byte("iB")
 
+	// LogIndexIndexPrefix is the data table of the log indexer to track its progress
+	LogIndexIndexPrefix = []byte("iL")
+
 	ChtPrefix           = []byte("chtRootV2-") // ChtPrefix + chtNum (uint64 big endian) -> trie root hash
 	ChtTablePrefix      = []byte("cht-")
 	ChtIndexTablePrefix = []byte("chtIndexV2-")
@@ -141,6 +146,8 @@ var (
 
 	CliqueSnapshotPrefix = []byte("clique-")
 
+	filterPrefix = []byte("rpc-filter-") // filterPrefix + filter id -> persistent RPC filter
+
 	BestUpdateKey         = []byte("update-")    // bigEndian64(syncPeriod) -> RLP(types.LightClientUpdate)  (nextCommittee only referenced by root hash)
 	FixedCommitteeRootKey = []byte("fixedRoot-") // bigEndian64(syncPeriod) -> committee root hash
 	SyncCommitteeKey      = []byte("committee-") // bigEndian64(syncPeriod) -> serialized committee
@@ -233,6 +240,22 @@ func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	return key
 }
 
+// logIndexKey = logIndexPrefix + address + [topic0] + section (uint64 big endian) + hash
+func logIndexKey(address common.Address, topic *common.Hash, section uint64, hash common.Hash) []byte {
+	key := append(append([]byte{}, logIndexPrefix...), address.Bytes()...)
+	if topic != nil {
+		key = append(key, topic.Bytes()...)
+	}
+	key = binary.BigEndian.AppendUint64(key, section)
+	return append(key, hash.Bytes()...)
+}
+
+// logIndexSectionKey = logIndexSectionPrefix + section (uint64 big endian) + hash
+func logIndexSectionKey(section uint64, hash common.Hash) []byte {
+	key := binary.BigEndian.AppendUint64(append([]byte{}, logIndexSectionPrefix...), section)
+	return append(key, hash.Bytes()...)
+}
+
 // skeletonHeaderKey = skeletonHeaderPrefix + num (uint64 big endian)
 func skeletonHeaderKey(number uint64) []byte {
 	return append(skeletonHeaderPrefix, encodeBlockNumber(number)...)
@@ -267,6 +290,11 @@ func genesisStateSpecKey(hash common.Hash) []byte {
 	return append(genesisPrefix, hash.Bytes()...)
 }
 
+// filterKey = filterPrefix + filter id
+func filterKey(id string) []byte {
+	return append(append([]byte{}, filterPrefix...), id...)
+}
+
 // stateIDKey = stateIDPrefix + root (32 bytes)
 func stateIDKey(root common.Hash) []byte {
 	return append(stateIDPrefix, root.Bytes()...)
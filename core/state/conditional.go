@@ -0,0 +1,53 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	ErrConditionalStorageRoot = errors.New("storage root mismatch")
+	ErrConditionalStorageSlot = errors.New("storage slot mismatch")
+)
+
+// CheckTransactionConditional checks that the known accounts of the conditional
+// match the state. The block number and timestamp ranges are not checked.
+func (s *StateDB) CheckTransactionConditional(cond *types.TransactionConditional) error {
+	for addr, account := range cond.KnownAccounts {
+		if account.StorageRoot != nil {
+			root := s.GetStorageRoot(addr)
+			if root == (common.Hash{}) {
+				root = types.EmptyRootHash
+			}
+			if root != *account.StorageRoot {
+				return fmt.Errorf("%w: account %s: have %s, want %s", ErrConditionalStorageRoot, addr, root, account.StorageRoot)
+			}
+			continue
+		}
+		for slot, want := range account.StorageSlots {
+			if have := s.GetState(addr, slot); have != want {
+				return fmt.Errorf("%w: account %s slot %s: have %s, want %s", ErrConditionalStorageSlot, addr, slot, have, want)
+			}
+		}
+	}
+	return nil
+}
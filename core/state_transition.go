@@ -211,7 +211,7 @@ func TransactionToMessage(tx *types.Transaction, s types.Signer, baseFee *big.In
 		BlobGasFeeCap:     tx.BlobGasFeeCap(),
 
 		FeeCurrency:         tx.FeeCurrency(),
-		MaxFeeInFeeCurrency: nil, // Will only be set once CIP-66 is implemented
+		MaxFeeInFeeCurrency: tx.MaxFeeInFeeCurrency(),
 	}
 	// If baseFee provided, set gasPrice to effectiveGasPrice.
 	if baseFee != nil {
@@ -325,6 +325,13 @@ func (st *StateTransition) buyGas() error {
 			mgval.Add(mgval, blobFee)
 		}
 	}
+	if st.msg.MaxFeeInFeeCurrency != nil {
+		// CIP-66: the fees are denominated in the native token, but paid in the fee currency
+		var err error
+		if mgval, balanceCheck, err = st.convertNativeFees(l1Cost); err != nil {
+			return err
+		}
+	}
 	if err := st.canPayFee(balanceCheck); err != nil {
 		return err
 	}
@@ -392,6 +399,14 @@ func (st *StateTransition) preCheck() error {
 			}
 		}
 	}
+	if msg.MaxFeeInFeeCurrency != nil {
+		if !st.evm.ChainConfig().IsCip66(st.evm.Context.Time) {
+			return ErrCip66NotEnabled
+		}
+		if msg.FeeCurrency == nil {
+			return fmt.Errorf("%w: address %v", ErrCip66MissingFeeCurrency, msg.From.Hex())
+		}
+	}
 
 	// Make sure that transaction gasFeeCap is greater than the baseFee (post london)
 	if st.evm.ChainConfig().IsLondon(st.evm.Context.BlockNumber) {
@@ -413,7 +428,7 @@ func (st *StateTransition) preCheck() error {
 
 			// This will panic if baseFee is nil, but basefee presence is verified
 			// as part of header validation.
-			baseFeeInFeeCurrency, err := exchange.ConvertCeloToCurrency(st.evm.Context.FeeCurrencyContext.ExchangeRates, msg.FeeCurrency, st.evm.Context.BaseFee)
+			baseFeeInFeeCurrency, err := exchange.ConvertCeloToCurrency(st.evm.Context.FeeCurrencyContext.ExchangeRates, msg.DenominationCurrency(), st.evm.Context.BaseFee)
 			if err != nil {
 				return fmt.Errorf("preCheck: %w", err)
 			}
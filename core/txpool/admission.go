@@ -0,0 +1,620 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/naoina/toml"
+)
+
+const (
+	// admissionReloadInterval is the interval at which the admission policy
+	// file is checked for modifications.
+	admissionReloadInterval = 5 * time.Second
+
+	// maxRateLimitedSenders is the number of senders whose rate limit state
+	// is retained.
+	maxRateLimitedSenders = 65536
+
+	// admissionRejectedMeterName is the prefix of the per policy metric of
+	// rejected transactions.
+	admissionRejectedMeterName = "txpool/admission/rejected"
+)
+
+var (
+	admissionRejectedMeter = metrics.NewRegisteredMeter(admissionRejectedMeterName, nil)
+	admissionReloadMeter   = metrics.NewRegisteredMeter("txpool/admission/reload", nil)
+	admissionFailureMeter  = metrics.NewRegisteredMeter("txpool/admission/reloadfailure", nil)
+	admissionPoliciesGauge = metrics.NewRegisteredGauge("txpool/admission/policies", nil)
+)
+
+// admissionTxTypes are the names of the transaction types in the calldata size
+// limits of the admission config.
+var admissionTxTypes = map[string]uint8{
+	"legacy":           types.LegacyTxType,
+	"accesslist":       types.AccessListTxType,
+	"dynamicfee":       types.DynamicFeeTxType,
+	"blob":             types.BlobTxType,
+	"celodenominated":  types.CeloDenominatedTxType,
+	"celodynamicfeev2": types.CeloDynamicFeeTxV2Type,
+	"celodynamicfee":   types.CeloDynamicFeeTxType,
+}
+
+// admissionTOMLSettings decodes the admission config using the field names as
+// keys, rejecting unknown fields.
+var admissionTOMLSettings = toml.Config{
+	NormFieldName: func(rt reflect.Type, key string) string {
+		return key
+	},
+	FieldToKey: func(rt reflect.Type, field string) string {
+		return field
+	},
+	MissingField: func(rt reflect.Type, field string) error {
+		return fmt.Errorf("field '%s' is not defined in %s", field, rt.String())
+	},
+}
+
+// AdmissionConfig is the set of policies transactions must satisfy to enter the
+// pool, as loaded from a TOML file. All policies are optional.
+type AdmissionConfig struct {
+	DeniedSenders    []common.Address // Senders whose transactions are rejected
+	DeniedRecipients []common.Address // Recipients whose transactions are rejected
+
+	// DeniedSelectors are the method selectors rejected in calls to any contract
+	// ("0x095ea7b3"), or to a single contract ("0x<address>:0x095ea7b3").
+	DeniedSelectors []string
+
+	SenderRateLimit float64 // Transactions accepted per second from a single sender, 0 for no limit
+	SenderRateBurst int     // Transactions accepted at once from a single sender, at least one
+
+	// MaxCalldata is the maximum calldata size in bytes per transaction type
+	// ("legacy", "accesslist", "dynamicfee", "blob", "celodynamicfeev2", ...),
+	// with "default" applying to all other types.
+	MaxCalldata map[string]int
+
+	// FeeCurrencies are the fee currencies transactions may pay with, with the
+	// zero address for the native currency. Any currency is accepted if empty.
+	FeeCurrencies []common.Address
+}
+
+// LoadAdmissionConfig reads the admission config from a TOML file.
+func LoadAdmissionConfig(file string) (*AdmissionConfig, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	config := new(AdmissionConfig)
+	err = admissionTOMLSettings.NewDecoder(bufio.NewReader(f)).Decode(config)
+	// Add file name to errors that have a line number.
+	if _, ok := err.(*toml.LineError); ok {
+		err = errors.New(file + ", " + err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Policies creates the admission policies of the config, in the order they are
+// applied.
+func (c *AdmissionConfig) Policies() ([]AdmissionPolicy, error) {
+	var policies []AdmissionPolicy
+	if len(c.DeniedSenders) > 0 {
+		policies = append(policies, &senderPolicy{denied: addressSet(c.DeniedSenders)})
+	}
+	if len(c.DeniedRecipients) > 0 {
+		policies = append(policies, &recipientPolicy{denied: addressSet(c.DeniedRecipients)})
+	}
+	if len(c.DeniedSelectors) > 0 {
+		policy, err := newSelectorPolicy(c.DeniedSelectors)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	if len(c.MaxCalldata) > 0 {
+		policy, err := newCalldataPolicy(c.MaxCalldata)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	if len(c.FeeCurrencies) > 0 {
+		policies = append(policies, &feeCurrencyPolicy{allowed: addressSet(c.FeeCurrencies)})
+	}
+	if c.SenderRateLimit < 0 || c.SenderRateBurst < 0 {
+		return nil, errors.New("negative sender rate limit")
+	}
+	if c.SenderRateLimit > 0 {
+		burst := c.SenderRateBurst
+		if burst == 0 {
+			burst = 1
+		}
+		policies = append(policies, &rateLimitPolicy{
+			limit: c.SenderRateLimit,
+			burst: burst,
+			state: &rateLimitState{senders: lru.NewBasicLRU[common.Address, *tokenBucket](maxRateLimitedSenders)},
+		})
+	}
+	return policies, nil
+}
+
+// AdmissionPolicy decides whether a transaction may enter the pool.
+type AdmissionPolicy interface {
+	// Name returns the name of the policy, used in metrics.
+	Name() string
+
+	// Admit returns an error if the transaction from the given sender must be
+	// rejected.
+	Admit(tx *types.Transaction, from common.Address) error
+}
+
+// AdmissionRecorder is implemented by admission policies which account for the
+// transactions added to the pool, like rate limits. Such policies charge new
+// transactions when admitting them, and are refunded if a transaction is not
+// added to the pool after all. They are not applied to the transactions already
+// pooled.
+type AdmissionRecorder interface {
+	AdmissionPolicy
+
+	// Record reports whether the admitted transaction from the given sender was
+	// added to the pool.
+	Record(tx *types.Transaction, from common.Address, added bool)
+}
+
+// AdmissionScreener is implemented by subpools which evict their pooled
+// transactions denied by the admission policies whenever they are reset.
+type AdmissionScreener interface {
+	// SetAdmission sets the policies pooled transactions are screened with.
+	SetAdmission(admission *Admission)
+}
+
+// Admission is a chain of admission policies which all transactions added to
+// the pool must satisfy. The policies loaded from the config file are reloaded
+// whenever the file changes.
+//
+// A nil Admission is valid and admits all transactions.
+type Admission struct {
+	file   string
+	signer types.Signer
+	extra  []AdmissionPolicy // Policies applied after those of the config file
+
+	policies atomic.Pointer[[]AdmissionPolicy]
+	modTime  time.Time  // Modification time of the loaded config file
+	lock     sync.Mutex // Lock serializing reloads
+
+	reloadFeed event.Feed // Feed notifying of reloaded policies
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewAdmission creates an admission policy chain from the given config file,
+// followed by the extra policies. Senders are recovered using the signer.
+func NewAdmission(file string, signer types.Signer, extra ...AdmissionPolicy) (*Admission, error) {
+	a := &Admission{
+		file:   file,
+		signer: signer,
+		extra:  extra,
+		quit:   make(chan struct{}),
+	}
+	if _, err := a.reload(true); err != nil {
+		return nil, err
+	}
+	a.wg.Add(1)
+	go a.loop()
+	return a, nil
+}
+
+// SubscribeReloads subscribes to notifications of the policies being reloaded
+// from the config file.
+func (a *Admission) SubscribeReloads(ch chan<- struct{}) event.Subscription {
+	return a.reloadFeed.Subscribe(ch)
+}
+
+// Close stops watching the config file for modifications.
+func (a *Admission) Close() {
+	if a == nil {
+		return
+	}
+	close(a.quit)
+	a.wg.Wait()
+}
+
+// Reload loads the policies from the config file, retaining the current ones
+// if it is invalid.
+func (a *Admission) Reload() error {
+	_, err := a.reload(true)
+	return err
+}
+
+// reload loads the policies from the config file if forced or if the file was
+// modified since last loaded, and reports whether they were reloaded.
+func (a *Admission) reload(force bool) (bool, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	info, err := os.Stat(a.file)
+	if err != nil {
+		return false, err
+	}
+	if !force && info.ModTime().Equal(a.modTime) {
+		return false, nil
+	}
+	// Don't retry an invalid file until it's modified again
+	a.modTime = info.ModTime()
+
+	config, err := LoadAdmissionConfig(a.file)
+	if err != nil {
+		return false, err
+	}
+	policies, err := config.Policies()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", a.file, err)
+	}
+	policies = append(policies, a.extra...)
+
+	// Retain the state of the rate limits across reloads
+	if old := a.policies.Load(); old != nil {
+		for _, policy := range policies {
+			if p, ok := policy.(*rateLimitPolicy); ok {
+				p.inherit(*old)
+			}
+		}
+	}
+	a.policies.Store(&policies)
+
+	admissionPoliciesGauge.Update(int64(len(policies)))
+	return true, nil
+}
+
+// Admit checks a new transaction against all policies, returning an error
+// wrapping ErrAdmissionDenied if any rejects it. Whether an admitted transaction
+// was added to the pool must be recorded afterwards.
+func (a *Admission) Admit(tx *types.Transaction) error {
+	return a.admit(tx, true)
+}
+
+// Screen checks a pooled transaction against all policies not accounting for
+// added transactions, returning an error wrapping ErrAdmissionDenied if any
+// rejects it.
+func (a *Admission) Screen(tx *types.Transaction) error {
+	return a.admit(tx, false)
+}
+
+// Record reports to the policies accounting for added transactions whether the
+// admitted transaction was added to the pool, refunding it if not.
+func (a *Admission) Record(tx *types.Transaction, added bool) {
+	if a == nil {
+		return
+	}
+	from, err := types.Sender(a.signer, tx)
+	if err != nil {
+		return
+	}
+	for _, policy := range *a.policies.Load() {
+		if recorder, ok := policy.(AdmissionRecorder); ok {
+			recorder.Record(tx, from, added)
+		}
+	}
+}
+
+// admit checks the transaction against the policies, skipping those accounting
+// for added transactions unless requested.
+func (a *Admission) admit(tx *types.Transaction, recorders bool) error {
+	if a == nil {
+		return nil
+	}
+	policies := *a.policies.Load()
+	if len(policies) == 0 {
+		return nil
+	}
+	from, err := types.Sender(a.signer, tx)
+	if err != nil {
+		return ErrInvalidSender
+	}
+	for i, policy := range policies {
+		if _, ok := policy.(AdmissionRecorder); ok && !recorders {
+			continue
+		}
+		if err := policy.Admit(tx, from); err != nil {
+			// Refund the policies which already charged the transaction
+			if recorders {
+				for _, charged := range policies[:i] {
+					if recorder, ok := charged.(AdmissionRecorder); ok {
+						recorder.Record(tx, from, false)
+					}
+				}
+			}
+			admissionRejectedMeter.Mark(1)
+			if metrics.Enabled {
+				metrics.GetOrRegisterMeter(admissionRejectedMeterName+"/"+policy.Name(), nil).Mark(1)
+			}
+			log.Trace("Transaction denied admission", "hash", tx.Hash(), "from", from, "policy", policy.Name(), "err", err)
+			return fmt.Errorf("%w: %v", ErrAdmissionDenied, err)
+		}
+	}
+	return nil
+}
+
+// loop reloads the policies whenever the config file is modified.
+func (a *Admission) loop() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(admissionReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reloaded, err := a.reload(false)
+			if err != nil {
+				admissionFailureMeter.Mark(1)
+				log.Error("Failed to reload txpool admission policies", "file", a.file, "err", err)
+				continue
+			}
+			if reloaded {
+				admissionReloadMeter.Mark(1)
+				log.Info("Reloaded txpool admission policies", "file", a.file)
+				a.reloadFeed.Send(struct{}{})
+			}
+		case <-a.quit:
+			return
+		}
+	}
+}
+
+// addressSet converts a list of addresses into a set.
+func addressSet(addrs []common.Address) map[common.Address]struct{} {
+	set := make(map[common.Address]struct{}, len(addrs))
+	for _, addr := range addrs {
+		set[addr] = struct{}{}
+	}
+	return set
+}
+
+// senderPolicy rejects transactions from denied senders.
+type senderPolicy struct {
+	denied map[common.Address]struct{}
+}
+
+func (p *senderPolicy) Name() string { return "sender" }
+
+func (p *senderPolicy) Admit(tx *types.Transaction, from common.Address) error {
+	if _, ok := p.denied[from]; ok {
+		return fmt.Errorf("sender %v denied", from)
+	}
+	return nil
+}
+
+// recipientPolicy rejects transactions to denied recipients.
+type recipientPolicy struct {
+	denied map[common.Address]struct{}
+}
+
+func (p *recipientPolicy) Name() string { return "recipient" }
+
+func (p *recipientPolicy) Admit(tx *types.Transaction, from common.Address) error {
+	if to := tx.To(); to != nil {
+		if _, ok := p.denied[*to]; ok {
+			return fmt.Errorf("recipient %v denied", *to)
+		}
+	}
+	return nil
+}
+
+// selectorPolicy rejects calls of denied contract methods.
+type selectorPolicy struct {
+	any      map[[4]byte]struct{}                    // Selectors denied for all contracts
+	contract map[common.Address]map[[4]byte]struct{} // Selectors denied per contract
+}
+
+func newSelectorPolicy(selectors []string) (*selectorPolicy, error) {
+	p := &selectorPolicy{
+		any:      make(map[[4]byte]struct{}),
+		contract: make(map[common.Address]map[[4]byte]struct{}),
+	}
+	for _, entry := range selectors {
+		addr, sel, scoped := strings.Cut(entry, ":")
+		if !scoped {
+			sel = addr
+		}
+		blob, err := hexutil.Decode(sel)
+		if err != nil || len(blob) != 4 {
+			return nil, fmt.Errorf("invalid selector %q", entry)
+		}
+		selector := [4]byte(blob)
+		if !scoped {
+			p.any[selector] = struct{}{}
+			continue
+		}
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("invalid selector contract %q", entry)
+		}
+		contract := common.HexToAddress(addr)
+		if p.contract[contract] == nil {
+			p.contract[contract] = make(map[[4]byte]struct{})
+		}
+		p.contract[contract][selector] = struct{}{}
+	}
+	return p, nil
+}
+
+func (p *selectorPolicy) Name() string { return "selector" }
+
+func (p *selectorPolicy) Admit(tx *types.Transaction, from common.Address) error {
+	data := tx.Data()
+	if tx.To() == nil || len(data) < 4 {
+		return nil
+	}
+	selector := [4]byte(data[:4])
+	if _, ok := p.any[selector]; ok {
+		return fmt.Errorf("selector %#x denied", selector)
+	}
+	if _, ok := p.contract[*tx.To()][selector]; ok {
+		return fmt.Errorf("selector %#x of %v denied", selector, *tx.To())
+	}
+	return nil
+}
+
+// calldataPolicy limits the calldata size of transactions by type.
+type calldataPolicy struct {
+	limits map[uint8]int
+	def    int // Limit of the types without one, 0 for none
+}
+
+func newCalldataPolicy(limits map[string]int) (*calldataPolicy, error) {
+	p := &calldataPolicy{limits: make(map[uint8]int)}
+	for name, limit := range limits {
+		if limit <= 0 {
+			return nil, fmt.Errorf("invalid calldata limit %d for %q", limit, name)
+		}
+		if name == "default" {
+			p.def = limit
+			continue
+		}
+		typ, ok := admissionTxTypes[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown transaction type %q", name)
+		}
+		p.limits[typ] = limit
+	}
+	return p, nil
+}
+
+func (p *calldataPolicy) Name() string { return "calldata" }
+
+func (p *calldataPolicy) Admit(tx *types.Transaction, from common.Address) error {
+	limit, ok := p.limits[tx.Type()]
+	if !ok {
+		limit = p.def
+	}
+	if size := len(tx.Data()); limit > 0 && size > limit {
+		return fmt.Errorf("calldata size %d exceeds limit %d for type %d", size, limit, tx.Type())
+	}
+	return nil
+}
+
+// feeCurrencyPolicy restricts the fee currencies transactions may pay with.
+type feeCurrencyPolicy struct {
+	allowed map[common.Address]struct{} // Native currency as the zero address
+}
+
+func (p *feeCurrencyPolicy) Name() string { return "feecurrency" }
+
+func (p *feeCurrencyPolicy) Admit(tx *types.Transaction, from common.Address) error {
+	var currency common.Address
+	if fc := tx.FeeCurrency(); fc != nil {
+		currency = *fc
+	}
+	if _, ok := p.allowed[currency]; !ok {
+		return fmt.Errorf("fee currency %v not allowed", currency)
+	}
+	return nil
+}
+
+// rateLimitPolicy limits the rate of transactions added per sender. A token is
+// taken when a transaction is admitted, and returned if it is not added to the
+// pool after all, so rejected duplicates or replays don't drain the budget of a
+// sender.
+type rateLimitPolicy struct {
+	limit float64 // Tokens added per second
+	burst int     // Maximum number of tokens
+	state *rateLimitState
+}
+
+// rateLimitState is the token budget of the senders, retained across reloads of
+// the policy.
+type rateLimitState struct {
+	senders lru.BasicLRU[common.Address, *tokenBucket]
+	lock    sync.Mutex
+}
+
+// tokenBucket is the token budget of a single sender.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time // Time the tokens were last refilled
+}
+
+// inherit takes over the sender budgets of the rate limit among the previous
+// policies, if there is one.
+func (p *rateLimitPolicy) inherit(policies []AdmissionPolicy) {
+	for _, policy := range policies {
+		if old, ok := policy.(*rateLimitPolicy); ok {
+			p.state = old.state
+			return
+		}
+	}
+}
+
+func (p *rateLimitPolicy) Name() string { return "ratelimit" }
+
+func (p *rateLimitPolicy) Admit(tx *types.Transaction, from common.Address) error {
+	p.state.lock.Lock()
+	defer p.state.lock.Unlock()
+
+	bucket := p.bucket(from)
+	if bucket.tokens < 1 {
+		return fmt.Errorf("sender %v exceeds rate limit", from)
+	}
+	bucket.tokens--
+	return nil
+}
+
+func (p *rateLimitPolicy) Record(tx *types.Transaction, from common.Address, added bool) {
+	if added {
+		return
+	}
+	p.state.lock.Lock()
+	defer p.state.lock.Unlock()
+
+	bucket := p.bucket(from)
+	bucket.tokens = min(bucket.tokens+1, float64(p.burst))
+}
+
+// bucket returns the refilled token budget of the sender. It must be called
+// with the state lock held.
+func (p *rateLimitPolicy) bucket(from common.Address) *tokenBucket {
+	now := time.Now()
+	bucket, ok := p.state.senders.Get(from)
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(p.burst), last: now}
+		p.state.senders.Add(from, bucket)
+		return bucket
+	}
+	bucket.tokens = min(bucket.tokens+now.Sub(bucket.last).Seconds()*p.limit, float64(p.burst))
+	bucket.last = now
+	return bucket
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tests that the admission policies of the config file reject transactions,
+// and are reloaded when the file changes.
+func TestAdmission(t *testing.T) {
+	var (
+		signer      = types.LatestSignerForChainID(big.NewInt(1))
+		deniedKey   = mustGenerateKey(t)
+		key         = mustGenerateKey(t)
+		contract    = common.HexToAddress("0xc0ffee")
+		feeCurrency = common.HexToAddress("0xcafe")
+		file        = filepath.Join(t.TempDir(), "admission.toml")
+	)
+	write := func(config string) {
+		t.Helper()
+		if err := os.WriteFile(file, []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	newTx := func(key *ecdsa.PrivateKey, to common.Address, data []byte, feeCurrency *common.Address) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.CeloDynamicFeeTxV2{
+			ChainID:     big.NewInt(1),
+			To:          &to,
+			Data:        data,
+			Gas:         100000,
+			GasFeeCap:   big.NewInt(1),
+			GasTipCap:   big.NewInt(1),
+			FeeCurrency: feeCurrency,
+		})
+	}
+	write(fmt.Sprintf(`
+DeniedSenders = ["%v"]
+DeniedRecipients = ["0x000000000000000000000000000000000000dead"]
+DeniedSelectors = ["0x095ea7b3", "%v:0xa9059cbb"]
+SenderRateLimit = 0.001
+SenderRateBurst = 2
+FeeCurrencies = ["0x0000000000000000000000000000000000000000"]
+
+[MaxCalldata]
+celodynamicfeev2 = 8
+`, crypto.PubkeyToAddress(deniedKey.PublicKey), contract))
+
+	admission, err := NewAdmission(file, signer)
+	if err != nil {
+		t.Fatalf("failed to load admission policies: %v", err)
+	}
+	defer admission.Close()
+
+	tests := []struct {
+		tx     *types.Transaction
+		denied bool
+	}{
+		{newTx(deniedKey, contract, nil, nil), true},                                         // Denied sender
+		{newTx(key, common.HexToAddress("0xdead"), nil, nil), true},                          // Denied recipient
+		{newTx(key, common.HexToAddress("0xbeef"), common.FromHex("0x095ea7b3"), nil), true}, // Denied for all contracts
+		{newTx(key, contract, common.FromHex("0xa9059cbb"), nil), true},                      // Denied for the contract
+		{newTx(key, common.HexToAddress("0xbeef"), common.FromHex("0xa9059cbb"), nil), false},
+		{newTx(key, contract, make([]byte, 9), nil), true},     // Calldata too large
+		{newTx(key, contract, nil, &feeCurrency), true},        // Fee currency not allowed
+		{newTx(key, contract, nil, nil), false},                // Second of the burst
+		{newTx(key, contract, nil, nil), true},                 // Rate limited
+		{newTx(mustGenerateKey(t), contract, nil, nil), false}, // Other sender not rate limited
+	}
+	for i, tt := range tests {
+		err := admission.Admit(tt.tx)
+		if denied := errors.Is(err, ErrAdmissionDenied); denied != tt.denied {
+			t.Errorf("test %d: denied mismatch: have %v (%v), want %v", i, denied, err, tt.denied)
+		}
+		if err == nil {
+			admission.Record(tt.tx, true)
+		}
+	}
+	// Pooled transactions are not screened by the rate limit
+	if err := admission.Screen(newTx(key, contract, nil, nil)); err != nil {
+		t.Errorf("pooled tx denied by rate limit: %v", err)
+	}
+	if err := admission.Screen(newTx(deniedKey, contract, nil, nil)); !errors.Is(err, ErrAdmissionDenied) {
+		t.Errorf("pooled tx of denied sender admitted: %v", err)
+	}
+	// Transactions not added to the pool don't count towards the rate limit
+	other := mustGenerateKey(t)
+	for i := 0; i < 3; i++ {
+		tx := newTx(other, contract, nil, nil)
+		if err := admission.Admit(tx); err != nil {
+			t.Fatalf("refunded tx %d rate limited: %v", i, err)
+		}
+		admission.Record(tx, false)
+	}
+	// Reloading the config retains the budgets of the senders
+	write(fmt.Sprintf(`
+DeniedSenders = ["%v"]
+SenderRateLimit = 0.001
+SenderRateBurst = 2
+`, crypto.PubkeyToAddress(deniedKey.PublicKey)))
+	if err := admission.Reload(); err != nil {
+		t.Fatalf("failed to reload admission policies: %v", err)
+	}
+	if err := admission.Admit(newTx(key, contract, nil, nil)); !errors.Is(err, ErrAdmissionDenied) {
+		t.Errorf("rate limit reset by reload: %v", err)
+	}
+	// Reloading an invalid config retains the current policies
+	write(`DeniedSelectors = ["0x01"]`)
+	if err := admission.Reload(); err == nil {
+		t.Fatal("invalid config loaded")
+	}
+	if err := admission.Admit(newTx(deniedKey, contract, nil, nil)); !errors.Is(err, ErrAdmissionDenied) {
+		t.Errorf("policies dropped by invalid config: %v", err)
+	}
+	// Reloading a valid config replaces the policies
+	write(`DeniedRecipients = ["0x000000000000000000000000000000000000dead"]`)
+	if err := admission.Reload(); err != nil {
+		t.Fatalf("failed to reload admission policies: %v", err)
+	}
+	if err := admission.Admit(newTx(deniedKey, contract, nil, nil)); err != nil {
+		t.Errorf("sender denied after reload: %v", err)
+	}
+	if err := admission.Admit(newTx(deniedKey, common.HexToAddress("0xdead"), nil, nil)); !errors.Is(err, ErrAdmissionDenied) {
+		t.Errorf("recipient admitted after reload: %v", err)
+	}
+	// Unknown fields are rejected
+	write(`DeniedSender = []`)
+	if _, err := NewAdmission(file, signer); err == nil {
+		t.Error("config with unknown field loaded")
+	}
+}
+
+func mustGenerateKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
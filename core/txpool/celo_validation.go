@@ -1,10 +1,12 @@
 package txpool
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/exchange"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
 )
@@ -55,6 +57,18 @@ func CeloValidateTransaction(tx *types.Transaction, head *types.Header,
 	if !common.IsCurrencyAllowed(currencyCtx.ExchangeRates, tx.FeeCurrency()) {
 		return exchange.ErrUnregisteredFeeCurrency
 	}
+	if maxFee := tx.MaxFeeInFeeCurrency(); maxFee != nil {
+		// CIP-66: the fee cap is given in the native token, make sure that it
+		// does not exceed maxFeeInFeeCurrency at the current exchange rate.
+		cost := new(big.Int).Mul(tx.GasFeeCap(), new(big.Int).SetUint64(tx.Gas()))
+		costInFeeCurrency, err := exchange.ConvertCeloToCurrency(currencyCtx.ExchangeRates, tx.FeeCurrency(), cost)
+		if err != nil {
+			return err
+		}
+		if costInFeeCurrency.Cmp(maxFee) > 0 {
+			return fmt.Errorf("%w: max fee %v, maxFeeInFeeCurrency %v, fee currency: %v", core.ErrMaxFeeInFeeCurrencyExceeded, costInFeeCurrency, maxFee, tx.FeeCurrency().Hex())
+		}
+	}
 
 	return nil
 }
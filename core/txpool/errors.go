@@ -60,4 +60,13 @@ var (
 	// input transaction of non-blob type when a blob transaction from this sender
 	// remains pending (and vice-versa).
 	ErrAlreadyReserved = errors.New("address already reserved")
+
+	// ErrExchangeRateExcluded is returned if a transaction pays fees in a
+	// fee currency whose exchange rate is currently considered stale or
+	// erratic by the local node's exchange rate guard.
+	ErrExchangeRateExcluded = errors.New("fee-currency exchange rate excluded by local policy")
+
+	// ErrAdmissionDenied is returned if a transaction is rejected by one of the
+	// configured admission policies of the pool.
+	ErrAdmissionDenied = errors.New("transaction denied by admission policy")
 )
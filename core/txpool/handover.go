@@ -0,0 +1,140 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// handoverVersion is the version of the pool export format, written as the
+// first item of the stream.
+const handoverVersion = 1
+
+// handoverBatchSize is the maximum number of imported transactions added to the
+// pool at once.
+const handoverBatchSize = 1024
+
+var errUnsupportedHandover = errors.New("unsupported txpool export version")
+
+// HandoverTx is a pool transaction along with the metadata needed to restore it
+// into another pool.
+type HandoverTx struct {
+	Tx      *types.Transaction
+	Time    uint64 // Arrival time in unix nanoseconds
+	Local   bool   // Whether the sender is treated as local
+	Pending bool   // Whether the transaction was executable
+}
+
+// Export writes all pending and queued transactions to w as an RLP stream, in
+// the order they arrived at the pool. It returns the number of transactions
+// exported.
+func (p *TxPool) Export(w io.Writer) (int, error) {
+	locals := make(map[common.Address]struct{})
+	for _, addr := range p.Locals() {
+		locals[addr] = struct{}{}
+	}
+	var txs []*HandoverTx
+	collect := func(content map[common.Address][]*types.Transaction, isPending bool) {
+		for addr, list := range content {
+			_, local := locals[addr]
+			for _, tx := range list {
+				txs = append(txs, &HandoverTx{Tx: tx, Time: uint64(tx.Time().UnixNano()), Local: local, Pending: isPending})
+			}
+		}
+	}
+	pending, queued := p.Content()
+	collect(pending, true)
+	collect(queued, false)
+
+	// Order by arrival, falling back to the nonce order for equal times
+	sort.SliceStable(txs, func(i, j int) bool {
+		if txs[i].Time != txs[j].Time {
+			return txs[i].Time < txs[j].Time
+		}
+		return txs[i].Tx.Nonce() < txs[j].Tx.Nonce()
+	})
+
+	if err := rlp.Encode(w, uint64(handoverVersion)); err != nil {
+		return 0, err
+	}
+	for _, tx := range txs {
+		if err := rlp.Encode(w, tx); err != nil {
+			return 0, err
+		}
+	}
+	return len(txs), nil
+}
+
+// Import reads transactions exported by Export from r and adds them to the
+// pool in their original arrival order, preserving their arrival time and
+// local flag. The transactions are validated against the current head as any
+// other transaction. The stream is processed in batches, calling done with the
+// result of adding each transaction. It returns the number of transactions read.
+func (p *TxPool) Import(r io.Reader, done func(tx *HandoverTx, err error)) (int, error) {
+	stream := rlp.NewStream(r, 0)
+
+	version, err := stream.Uint64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read export version: %w", err)
+	}
+	if version != handoverVersion {
+		return 0, fmt.Errorf("%w: %d", errUnsupportedHandover, version)
+	}
+	// Add consecutive runs of transactions with the same local flag in a
+	// single batch, so their order is retained
+	var (
+		batch = make([]*HandoverTx, 0, handoverBatchSize)
+		count int
+	)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		txs := make([]*types.Transaction, len(batch))
+		for i, tx := range batch {
+			txs[i] = tx.Tx
+		}
+		for i, err := range p.Add(txs, batch[0].Local, true) {
+			done(batch[i], err)
+		}
+		batch = batch[:0]
+	}
+	for ; ; count++ {
+		tx := new(HandoverTx)
+		if err := stream.Decode(tx); err == io.EOF {
+			break
+		} else if err != nil {
+			flush()
+			return count, fmt.Errorf("transaction %d: failed to parse: %v", count, err)
+		}
+		tx.Tx.SetTime(time.Unix(0, int64(tx.Time)))
+		if len(batch) == handoverBatchSize || (len(batch) > 0 && batch[0].Local != tx.Local) {
+			flush()
+		}
+		batch = append(batch, tx)
+	}
+	flush()
+	return count, nil
+}
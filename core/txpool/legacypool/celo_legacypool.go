@@ -3,21 +3,39 @@ package legacypool
 import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/contracts"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/holiman/uint256"
 )
 
 // filter Filters transactions from the given list, according to remaining balance (per currency)
-// and gasLimit. Returns drops and invalid txs.
+// and gasLimit. Returns drops and invalid txs, recording the reason of each drop.
 func (pool *LegacyPool) filter(list *list, addr common.Address, gasLimit uint64) (types.Transactions, types.Transactions) {
-	// CELO: drop all transactions that no longer have a registered currency
-	dropsAllowlist, invalidsAllowlist := list.FilterAllowlisted(pool.feeCurrencyContext.ExchangeRates)
+	// Drop all conditional transactions which can no longer be included
+	dropsConditional, invalidsConditional := list.FilterConditional(pool.currentHead.Load())
+	pool.trackDropped(dropsConditional, txpool.TxDropConditional)
+
+	// Drop all transactions denied by the admission policies
+	dropsAdmission, invalidsAdmission := list.FilterAdmission(pool.admission.Load())
+	pool.trackDropped(dropsAdmission, txpool.TxDropAdmission)
+
+	// CELO: drop all transactions that no longer have a registered currency,
+	// or whose currency is excluded by the exchange rate guard
+	dropsAllowlist, invalidsAllowlist := list.FilterAllowlisted(pool.admittedExchangeRates)
 	// Check from which currencies we need to get balances
 	currenciesInList := list.FeeCurrencies()
 	drops, invalids := list.Filter(pool.getBalances(addr, currenciesInList), gasLimit)
-	totalDrops := append(dropsAllowlist, drops...)
-	totalInvalids := append(invalidsAllowlist, invalids...)
+	pool.trackDropped(dropsAllowlist, txpool.TxDropFeeCurrency)
+	for _, tx := range drops {
+		if tx.Gas() > gasLimit {
+			pool.trackDropped(types.Transactions{tx}, txpool.TxDropGasLimit)
+		} else {
+			pool.trackDropped(types.Transactions{tx}, txpool.TxDropBalance)
+		}
+	}
+	totalDrops := append(append(append(dropsConditional, dropsAdmission...), dropsAllowlist...), drops...)
+	totalInvalids := append(append(append(invalidsConditional, invalidsAdmission...), invalidsAllowlist...), invalids...)
 	return totalDrops, totalInvalids
 }
 
@@ -40,4 +58,17 @@ func (pool *LegacyPool) recreateCeloProperties() {
 	}
 
 	pool.feeCurrencyContext = feeCurrencyContext
+
+	head := pool.currentHead.Load()
+	pool.excludedFeeCurrencies = pool.exchangeRateGuard.Excluded(head, head.Time, pool.celoBackend, feeCurrencyContext.ExchangeRates)
+
+	pool.admittedExchangeRates = feeCurrencyContext.ExchangeRates
+	if len(pool.excludedFeeCurrencies) > 0 {
+		pool.admittedExchangeRates = make(common.ExchangeRates, len(feeCurrencyContext.ExchangeRates))
+		for currency, rate := range feeCurrencyContext.ExchangeRates {
+			if _, ok := pool.excludedFeeCurrencies[currency]; !ok {
+				pool.admittedExchangeRates[currency] = rate
+			}
+		}
+	}
 }
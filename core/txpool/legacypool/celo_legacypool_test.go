@@ -0,0 +1,77 @@
+package legacypool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+// Tests that transactions paying fees in a currency newly excluded by the exchange
+// rate guard are evicted from the pool, and no longer admitted.
+func TestExchangeRateGuardEviction(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		genesis = core.DeveloperGenesisBlock(10_000_000, nil)
+		block   = genesis.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+		oracle  = common.HexToAddress("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb0001") // oracle of core.DevFeeCurrencyAddr
+	)
+	statedb, _ := state.New(block.Root(), state.NewDatabase(db), nil)
+	blockchain := newTestBlockChain(genesis.Config, 10_000_000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.ExchangeRateGuard = contracts.ExchangeRateGuardConfig{MaxChange: 2}
+	pool := New(config, blockchain)
+	if err := pool.Init(config.PriceLimit, block.Header(), makeAddressReserver()); err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	<-pool.initDoneCh
+
+	signer := types.LatestSigner(genesis.Config)
+	feeCurrencyTx := func(nonce uint64) *types.Transaction {
+		return types.MustSignNewTx(core.DevPrivateKey, signer, &types.CeloDynamicFeeTxV2{
+			ChainID:     genesis.Config.ChainID,
+			Nonce:       nonce,
+			GasTipCap:   big.NewInt(1),
+			GasFeeCap:   big.NewInt(1_000_000_000),
+			Gas:         100_000,
+			To:          &common.Address{},
+			Value:       big.NewInt(0),
+			FeeCurrency: &core.DevFeeCurrencyAddr,
+		})
+	}
+	tx := feeCurrencyTx(0)
+	if err := pool.addRemoteSync(tx); err != nil {
+		t.Fatalf("failed to add fee currency transaction: %v", err)
+	}
+
+	// Make the exchange rate jump within the next block
+	numerator := statedb.GetState(oracle, common.Hash{}).Big()
+	statedb.SetState(oracle, common.Hash{}, common.BigToHash(new(big.Int).Mul(numerator, big.NewInt(10))))
+	head := &types.Header{
+		ParentHash: block.Hash(),
+		Number:     big.NewInt(1),
+		GasLimit:   block.GasLimit(),
+		BaseFee:    block.BaseFee(),
+		Time:       block.Time() + 1,
+	}
+	<-pool.requestReset(block.Header(), head)
+
+	if pool.Has(tx.Hash()) {
+		t.Fatal("transaction in excluded fee currency not evicted")
+	}
+	if err := pool.addRemoteSync(feeCurrencyTx(0)); err != txpool.ErrExchangeRateExcluded {
+		t.Fatalf("unexpected admission error: have %v, want %v", err, txpool.ErrExchangeRateExcluded)
+	}
+}
@@ -1,14 +1,80 @@
 package legacypool
 
 import (
+	"fmt"
 	"math"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/exchange"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/holiman/uint256"
 )
 
+// checkReplacement returns nil if tx is allowed to replace old, which has the
+// same nonce. Both the fee cap and the tip of tx must be higher than those of
+// old, and at least priceBump percent higher.
+//
+// If the fees of the transactions are denominated in different currencies,
+// the values are compared by their native equivalent using the given exchange
+// rates. Since the rates may have changed since old was submitted, the bump is
+// always evaluated with the current rates. Replacing a transaction paying with
+// an unregistered currency, or with one, is not allowed.
+func checkReplacement(old, tx *types.Transaction, priceBump uint64, rates common.ExchangeRates) error {
+	// threshold = old * (100 + priceBump) / 100
+	bump := big.NewInt(100 + int64(priceBump))
+	thresholdFeeCap := new(big.Int).Mul(bump, old.GasFeeCap())
+	thresholdFeeCap.Div(thresholdFeeCap, big.NewInt(100))
+	thresholdTip := new(big.Int).Mul(bump, old.GasTipCap())
+	thresholdTip.Div(thresholdTip, big.NewInt(100))
+
+	if err := checkBump("gas fee cap", tx.GasFeeCap(), tx.DenominationCurrency(), old.GasFeeCap(), thresholdFeeCap, old.DenominationCurrency(), rates); err != nil {
+		return err
+	}
+	return checkBump("gas tip cap", tx.GasTipCap(), tx.DenominationCurrency(), old.GasTipCap(), thresholdTip, old.DenominationCurrency(), rates)
+}
+
+// checkBump ensures that val is higher than prev as well as not lower than
+// threshold. The former is needed for the check to be accurate for low
+// (Wei-level) gas price replacements.
+func checkBump(field string, val *big.Int, currency *common.Address, prev, threshold *big.Int, prevCurrency *common.Address, rates common.ExchangeRates) error {
+	cmpPrev, err := exchange.CompareValue(rates, val, currency, prev, prevCurrency)
+	if err != nil {
+		return fmt.Errorf("%w: %w", txpool.ErrReplaceUnderpriced, err)
+	}
+	cmpThreshold, err := exchange.CompareValue(rates, val, currency, threshold, prevCurrency)
+	if err != nil {
+		return fmt.Errorf("%w: %w", txpool.ErrReplaceUnderpriced, err)
+	}
+	if cmpPrev <= 0 || cmpThreshold < 0 {
+		if common.AreSameAddress(currency, prevCurrency) {
+			return fmt.Errorf("%w: %s %v, need at least %v", txpool.ErrReplaceUnderpriced, field, val, threshold)
+		}
+		return fmt.Errorf("%w: %s %v in %s, need native equivalent of at least %v in %s",
+			txpool.ErrReplaceUnderpriced, field, val, currencyName(currency), threshold, currencyName(prevCurrency))
+	}
+	return nil
+}
+
+func currencyName(feeCurrency *common.Address) string {
+	if feeCurrency == nil {
+		return "native currency"
+	}
+	return feeCurrency.Hex()
+}
+
+// replacementError returns the reason why tx could not replace the
+// transaction with the same nonce in the list.
+func (l *list) replacementError(tx *types.Transaction, priceBump uint64, rates common.ExchangeRates) error {
+	if old := l.txs.Get(tx.Nonce()); old != nil {
+		if err := checkReplacement(old, tx, priceBump, rates); err != nil {
+			return err
+		}
+	}
+	return txpool.ErrReplaceUnderpriced
+}
+
 func (l *list) FilterAllowlisted(rates common.ExchangeRates) (types.Transactions, types.Transactions) {
 	removed := l.txs.Filter(func(tx *types.Transaction) bool {
 		return !common.IsCurrencyAllowed(rates, tx.FeeCurrency())
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
@@ -197,3 +198,99 @@ func TestFilterBalanceGasLimit(t *testing.T) {
 	assert.Equal(t, removed[0], toBeRemoved)
 	assert.Equal(t, uint64(0), list.TotalCostFor(&curr2).Uint64())
 }
+
+func TestListCrossCurrencyReplacement(t *testing.T) {
+	cusd := common.HexToAddress("0002")
+	unregistered := common.HexToAddress("0004")
+	rates := common.ExchangeRates{cusd: big.NewRat(2, 1)} // 1 native = 2 cUSD
+
+	// Replacing a native tx requires a bumped native equivalent in cUSD
+	list := newList(false)
+	list.Add(txC(0, 100, 100, 21000, nil), DefaultConfig.PriceBump, nil, rates)
+
+	tx := txC(0, 219, 220, 21000, &cusd)
+	if inserted, _ := list.Add(tx, DefaultConfig.PriceBump, nil, rates); inserted {
+		t.Fatal("replacement with too low fee cap accepted")
+	}
+	err := list.replacementError(tx, DefaultConfig.PriceBump, rates)
+	assert.ErrorIs(t, err, txpool.ErrReplaceUnderpriced)
+	assert.ErrorContains(t, err, "gas fee cap 219 in "+cusd.Hex()+", need native equivalent of at least 110 in native currency")
+
+	tx = txC(0, 220, 219, 21000, &cusd)
+	if inserted, _ := list.Add(tx, DefaultConfig.PriceBump, nil, rates); inserted {
+		t.Fatal("replacement with too low tip accepted")
+	}
+	assert.ErrorContains(t, list.replacementError(tx, DefaultConfig.PriceBump, rates), "gas tip cap 219")
+
+	tx = txC(0, 220, 220, 21000, &cusd)
+	if inserted, old := list.Add(tx, DefaultConfig.PriceBump, nil, rates); !inserted || old == nil {
+		t.Fatal("valid cross-currency replacement rejected")
+	}
+	assert.Equal(t, uint64(0), list.TotalCostFor(nil).Uint64())
+	assert.Equal(t, uint64(220*21000), list.TotalCostFor(&cusd).Uint64())
+
+	// The rates at the time of the replacement are used: after cUSD lost half
+	// of its value, the bumped old tx is only worth 60.5 native instead of 121.
+	changedRates := common.ExchangeRates{cusd: big.NewRat(4, 1)}
+	tx = txC(0, 61, 61, 21000, nil)
+	if inserted, _ := list.Add(tx, DefaultConfig.PriceBump, nil, rates); inserted {
+		t.Fatal("replacement accepted with outdated rates")
+	}
+	if inserted, _ := list.Add(tx, DefaultConfig.PriceBump, nil, changedRates); !inserted {
+		t.Fatal("replacement rejected with current rates")
+	}
+
+	// Replacing with an unregistered currency is never allowed
+	tx = txC(0, 1000, 1000, 21000, &unregistered)
+	if inserted, _ := list.Add(tx, DefaultConfig.PriceBump, nil, changedRates); inserted {
+		t.Fatal("replacement with unregistered currency accepted")
+	}
+	assert.ErrorIs(t, list.replacementError(tx, DefaultConfig.PriceBump, changedRates), txpool.ErrReplaceUnderpriced)
+}
+
+func txD(nonce int, feeCap int, tipCap int, gas int, currency *common.Address, maxFeeInFeeCurrency int) *types.Transaction {
+	return types.NewTx(&types.CeloDenominatedTx{
+		GasFeeCap:           big.NewInt(int64(feeCap)),
+		GasTipCap:           big.NewInt(int64(tipCap)),
+		FeeCurrency:         currency,
+		MaxFeeInFeeCurrency: big.NewInt(int64(maxFeeInFeeCurrency)),
+		Gas:                 uint64(gas),
+		Nonce:               uint64(nonce),
+	})
+}
+
+func TestListCeloDenominatedTx(t *testing.T) {
+	cusd := common.HexToAddress("0002")
+	rates := common.ExchangeRates{cusd: big.NewRat(2, 1)} // 1 native = 2 cUSD
+
+	// The cost of a CIP-66 tx is bounded by its maxFeeInFeeCurrency
+	list := newList(false)
+	list.Add(txD(0, 100, 100, 21000, &cusd, 5_000_000), DefaultConfig.PriceBump, nil, rates)
+	assert.Equal(t, uint64(5_000_000), list.TotalCostFor(&cusd).Uint64())
+	assert.Equal(t, uint64(0), list.TotalCostFor(nil).Uint64())
+
+	// CIP-66 fees are denominated in the native token, so replacing it with a
+	// CIP-64 tx paying in the same currency still requires the converted bump.
+	tx := txC(0, 219, 220, 21000, &cusd)
+	if inserted, _ := list.Add(tx, DefaultConfig.PriceBump, nil, rates); inserted {
+		t.Fatal("replacement with too low fee cap accepted")
+	}
+	assert.ErrorContains(t, list.replacementError(tx, DefaultConfig.PriceBump, rates), "gas fee cap 219 in "+cusd.Hex()+", need native equivalent of at least 110 in native currency")
+
+	tx = txC(0, 220, 220, 21000, &cusd)
+	if inserted, old := list.Add(tx, DefaultConfig.PriceBump, nil, rates); !inserted || old == nil {
+		t.Fatal("valid replacement rejected")
+	}
+	assert.Equal(t, uint64(220*21000), list.TotalCostFor(&cusd).Uint64())
+
+	// And the other way around
+	tx = txD(0, 120, 120, 21000, &cusd, 6_000_000)
+	if inserted, _ := list.Add(tx, DefaultConfig.PriceBump, nil, rates); inserted {
+		t.Fatal("replacement with too low fee cap accepted")
+	}
+	tx = txD(0, 121, 121, 21000, &cusd, 6_000_000)
+	if inserted, _ := list.Add(tx, DefaultConfig.PriceBump, nil, rates); !inserted {
+		t.Fatal("valid replacement rejected")
+	}
+	assert.Equal(t, uint64(6_000_000), list.TotalCostFor(&cusd).Uint64())
+}
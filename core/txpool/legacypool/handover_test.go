@@ -0,0 +1,140 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func setupHandoverPool(t *testing.T) (*txpool.TxPool, *LegacyPool) {
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	chain := newTestBlockChain(params.TestChainConfig, 10000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.NoLocals = false
+	legacy := New(config, chain)
+	pool, err := txpool.New(config.PriceLimit, chain, []txpool.SubPool{legacy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pool.Close() })
+	return pool, legacy
+}
+
+// Tests that a pool can be exported and imported into another one, retaining
+// the arrival time, local flag and executability of the transactions.
+func TestTxPoolHandover(t *testing.T) {
+	t.Parallel()
+
+	src, srcLegacy := setupHandoverPool(t)
+	dst, dstLegacy := setupHandoverPool(t)
+
+	localKey, _ := crypto.GenerateKey()
+	remoteKey, _ := crypto.GenerateKey()
+	brokeKey, _ := crypto.GenerateKey()
+	for _, key := range []*ecdsa.PrivateKey{localKey, remoteKey, brokeKey} {
+		testAddBalance(srcLegacy, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+	}
+	for _, key := range []*ecdsa.PrivateKey{localKey, remoteKey} {
+		testAddBalance(dstLegacy, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+	}
+	// Add pending and queued transactions with distinct arrival times
+	var (
+		base = time.Unix(1700000000, 0)
+		txs  = []*types.Transaction{
+			pricedTransaction(0, 100000, big.NewInt(1), remoteKey),
+			pricedTransaction(0, 100000, big.NewInt(1), localKey),
+			pricedTransaction(2, 100000, big.NewInt(1), localKey), // queued
+			pricedTransaction(0, 100000, big.NewInt(1), brokeKey), // unpayable at the destination
+			pricedTransaction(1, 100000, big.NewInt(1), remoteKey),
+		}
+	)
+	for i, tx := range txs {
+		tx.SetTime(base.Add(time.Duration(i) * time.Second))
+		local := tx.Hash() == txs[1].Hash() || tx.Hash() == txs[2].Hash()
+		if err := src.Add([]*types.Transaction{tx}, local, true)[0]; err != nil {
+			t.Fatalf("tx %d: failed to add: %v", i, err)
+		}
+	}
+	var buf bytes.Buffer
+	if n, err := src.Export(&buf); err != nil || n != len(txs) {
+		t.Fatalf("export failed: n %d, err %v", n, err)
+	}
+	// The last transaction is already known at the destination
+	if err := dst.Add([]*types.Transaction{txs[4]}, false, true)[0]; err != nil {
+		t.Fatalf("failed to add: %v", err)
+	}
+	var (
+		imported []*txpool.HandoverTx
+		errs     []error
+	)
+	n, err := dst.Import(bytes.NewReader(buf.Bytes()), func(tx *txpool.HandoverTx, err error) {
+		imported = append(imported, tx)
+		errs = append(errs, err)
+	})
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if n != len(txs) || len(imported) != len(txs) {
+		t.Fatalf("imported %d txs, want %d", len(imported), len(txs))
+	}
+	for i, tx := range imported {
+		if tx.Tx.Hash() != txs[i].Hash() {
+			t.Fatalf("tx %d: arrival order not retained", i)
+		}
+		switch {
+		case i == 3:
+			if errs[i] == nil {
+				t.Errorf("tx %d: unpayable tx accepted", i)
+			}
+		case i == 4:
+			if !errors.Is(errs[i], txpool.ErrAlreadyKnown) {
+				t.Errorf("tx %d: unexpected error: have %v, want %v", i, errs[i], txpool.ErrAlreadyKnown)
+			}
+		case errs[i] != nil:
+			t.Errorf("tx %d: failed to import: %v", i, errs[i])
+		}
+		if queued := i == 2; tx.Pending == queued {
+			t.Errorf("tx %d: pending flag mismatch: have %v, want %v", i, tx.Pending, !queued)
+		}
+	}
+	// Arrival times and local senders are restored
+	for _, i := range []int{0, 1, 2} {
+		if tx := dst.Get(txs[i].Hash()); tx == nil || !tx.Time().Equal(txs[i].Time()) {
+			t.Errorf("tx %d: arrival time not retained", i)
+		}
+	}
+	if locals := dst.Locals(); len(locals) != 1 || locals[0] != crypto.PubkeyToAddress(localKey.PublicKey) {
+		t.Errorf("local accounts mismatch: %v", locals)
+	}
+	if pending, queued := dst.Stats(); pending != 3 || queued != 1 {
+		t.Errorf("pool stats mismatch: pending %d, queued %d", pending, queued)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"slices"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -113,6 +114,7 @@ var (
 		types.DynamicFeeTxType:       metrics.NewRegisteredMeter("txpool/txtype/dynamicfee", nil),
 		types.BlobTxType:             metrics.NewRegisteredMeter("txpool/txtype/blob", nil),
 		types.CeloDynamicFeeTxV2Type: metrics.NewRegisteredMeter("txpool/txtype/cip64", nil),
+		types.CeloDenominatedTxType:  metrics.NewRegisteredMeter("txpool/txtype/cip66", nil),
 	}
 	validTxMeterByFeeCurrency = map[common.Address]metrics.Meter{}
 )
@@ -155,6 +157,13 @@ type Config struct {
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
 
 	EffectiveGasCeil uint64 // if non-zero, a gas ceiling to enforce independent of the header's gaslimit value
+
+	LifecycleSlots uint64 // Number of transactions whose lifecycle events are retained (0 = disabled)
+
+	Admission string // Admission policy file of the pool, reloaded when modified
+
+	// Celo: fee currencies with stale or erratic exchange rates are not admitted
+	ExchangeRateGuard contracts.ExchangeRateGuardConfig
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
@@ -171,6 +180,8 @@ var DefaultConfig = Config{
 	GlobalQueue:  1024,
 
 	Lifetime: 3 * time.Hour,
+
+	LifecycleSlots: 16384,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -255,8 +266,15 @@ type LegacyPool struct {
 	l1CostFn txpool.L1CostFunc // To apply L1 costs as rollup, optional field, may be nil.
 
 	// Celo specific
-	celoBackend        *contracts.CeloBackend    // For fee currency balances & exchange rate calculation
-	feeCurrencyContext common.FeeCurrencyContext // context for fee currencies
+	celoBackend           *contracts.CeloBackend       // For fee currency balances & exchange rate calculation
+	feeCurrencyContext    common.FeeCurrencyContext    // context for fee currencies
+	exchangeRateGuard     *contracts.ExchangeRateGuard // Local policy excluding untrusted exchange rates, may be nil
+	excludedFeeCurrencies common.AddressSet            // Fee currencies currently not admitted due to their exchange rate
+	admittedExchangeRates common.ExchangeRates         // Exchange rates of the registered fee currencies not excluded
+
+	lifecycle *txpool.TxLifecycle // Lifecycle events of recently seen transactions, may be nil
+
+	admission atomic.Pointer[txpool.Admission] // Admission policies pooled transactions are screened with
 }
 
 type txpoolResetRequest struct {
@@ -285,6 +303,9 @@ func New(config Config, chain BlockChain) *LegacyPool {
 		reorgDoneCh:     make(chan chan struct{}),
 		reorgShutdownCh: make(chan struct{}),
 		initDoneCh:      make(chan struct{}),
+
+		exchangeRateGuard: contracts.NewExchangeRateGuard(config.ExchangeRateGuard),
+		lifecycle:         txpool.NewTxLifecycle(int(config.LifecycleSlots)),
 	}
 	pool.locals = newAccountSet(pool.signer)
 	for _, addr := range config.Locals {
@@ -303,7 +324,7 @@ func New(config Config, chain BlockChain) *LegacyPool {
 // pool, specifically, whether it is a Legacy, AccessList or Dynamic transaction.
 func (pool *LegacyPool) Filter(tx *types.Transaction) bool {
 	switch tx.Type() {
-	case types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType, types.CeloDynamicFeeTxV2Type:
+	case types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType, types.CeloDynamicFeeTxV2Type, types.CeloDenominatedTxType:
 		return true
 	default:
 		return false
@@ -347,7 +368,7 @@ func (pool *LegacyPool) Init(gasTip uint64, head *types.Header, reserve txpool.A
 		if pool.config.JournalRemote {
 			add = pool.addRemotesSync // Use sync version to match pool.AddLocals
 		}
-		if err := pool.journal.load(add); err != nil {
+		if err := pool.journal.load(pool.screenJournal(add)); err != nil {
 			log.Warn("Failed to load transaction journal", "err", err)
 		}
 		if err := pool.journal.rotate(pool.toJournal()); err != nil {
@@ -412,6 +433,7 @@ func (pool *LegacyPool) loop() {
 						pool.removeTx(tx.Hash(), true, true)
 					}
 					queuedEvictionMeter.Mark(int64(len(list)))
+					pool.trackDropped(list, txpool.TxDropExpired)
 				}
 			}
 			pool.mu.Unlock()
@@ -438,6 +460,7 @@ func (pool *LegacyPool) Close() error {
 	if pool.journal != nil {
 		pool.journal.close()
 	}
+	pool.lifecycle.Close()
 	log.Info("Transaction pool stopped")
 	return nil
 }
@@ -478,6 +501,7 @@ func (pool *LegacyPool) SetGasTip(tip *big.Int) {
 			pool.removeTx(tx.Hash(), false, true)
 		}
 		pool.priced.Removed(len(drop))
+		pool.trackDropped(drop, txpool.TxDropUnderpriced)
 	}
 	log.Info("Legacy pool tip threshold updated", "tip", newTip)
 }
@@ -576,8 +600,8 @@ func (pool *LegacyPool) Pending(filter txpool.PendingFilter) map[common.Address]
 		// If the miner requests tip enforcement, cap the lists now
 		if minTipBig != nil && !pool.locals.contains(addr) {
 			for i, tx := range txs {
-				minTipInFeeCurrency, err := exchange.ConvertCeloToCurrency(pool.feeCurrencyContext.ExchangeRates, tx.FeeCurrency(), minTipBig)
-				if err != nil || tx.EffectiveGasTipIntCmp(minTipInFeeCurrency, pool.priced.urgent.GetBaseFeeIn(tx.FeeCurrency())) < 0 {
+				minTipInFeeCurrency, err := exchange.ConvertCeloToCurrency(pool.feeCurrencyContext.ExchangeRates, tx.DenominationCurrency(), minTipBig)
+				if err != nil || tx.EffectiveGasTipIntCmp(minTipInFeeCurrency, pool.priced.urgent.GetBaseFeeIn(tx.DenominationCurrency())) < 0 {
 					txs = txs[:i]
 					break
 				}
@@ -597,7 +621,8 @@ func (pool *LegacyPool) Pending(filter txpool.PendingFilter) map[common.Address]
 					BlobGas:   txs[i].BlobGas(),
 
 					// Celo specific
-					FeeCurrency: txs[i].FeeCurrency(),
+					FeeCurrency:       txs[i].FeeCurrency(),
+					NativeDenominated: txs[i].FeeCurrency() != nil && !txs[i].IsFeeCurrencyDenominated(),
 				}
 			}
 			pending[addr] = lazies
@@ -615,18 +640,29 @@ func (pool *LegacyPool) Locals() []common.Address {
 }
 
 // toJournal retrieves all transactions that should be included in the journal,
-// grouped by origin account and sorted by nonce.
+// grouped by origin account and sorted by nonce. Conditional transactions are
+// left out, as their conditional is not journaled.
 // The returned transaction set is a copy and can be freely modified by calling code.
 func (pool *LegacyPool) toJournal() map[common.Address]types.Transactions {
+	var txs map[common.Address]types.Transactions
 	if !pool.config.JournalRemote {
-		return pool.local()
-	}
-	txs := make(map[common.Address]types.Transactions)
-	for addr, pending := range pool.pending {
-		txs[addr] = append(txs[addr], pending.Flatten()...)
+		txs = pool.local()
+	} else {
+		txs = make(map[common.Address]types.Transactions)
+		for addr, pending := range pool.pending {
+			txs[addr] = append(txs[addr], pending.Flatten()...)
+		}
+		for addr, queued := range pool.queue {
+			txs[addr] = append(txs[addr], queued.Flatten()...)
+		}
 	}
-	for addr, queued := range pool.queue {
-		txs[addr] = append(txs[addr], queued.Flatten()...)
+	for addr, list := range txs {
+		list = slices.DeleteFunc(list, func(tx *types.Transaction) bool { return tx.Conditional() != nil })
+		if len(list) == 0 {
+			delete(txs, addr)
+		} else {
+			txs[addr] = list
+		}
 	}
 	return txs
 }
@@ -658,7 +694,8 @@ func (pool *LegacyPool) validateTxBasics(tx *types.Transaction, local bool) erro
 			types.LegacyTxType,
 			types.AccessListTxType,
 			types.DynamicFeeTxType,
-			types.CeloDynamicFeeTxV2Type),
+			types.CeloDynamicFeeTxV2Type,
+			types.CeloDenominatedTxType),
 		MaxSize:          txMaxSize,
 		MinTip:           pool.gasTip.Load().ToBig(),
 		EffectiveGasCeil: pool.config.EffectiveGasCeil,
@@ -728,6 +765,9 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
 		return err
 	}
 	if tx.FeeCurrency() != nil {
+		if _, ok := pool.excludedFeeCurrencies[*tx.FeeCurrency()]; ok {
+			return txpool.ErrExchangeRateExcluded
+		}
 		from, err := pool.signer.Sender(tx) // already validated (and cached), but cleaner to check
 		if err != nil {
 			log.Error("Transaction sender recovery failed", "err", err)
@@ -864,6 +904,7 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 
 			pool.changesSinceReorg += dropped
 		}
+		pool.trackDropped(drop, txpool.TxDropUnderpriced)
 	}
 
 	// Try to replace an existing transaction in the pending pool
@@ -872,17 +913,20 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 		inserted, old := list.Add(tx, pool.config.PriceBump, pool.l1CostFn, pool.feeCurrencyContext.ExchangeRates)
 		if !inserted {
 			pendingDiscardMeter.Mark(1)
-			return false, txpool.ErrReplaceUnderpriced
+			return false, list.replacementError(tx, pool.config.PriceBump, pool.feeCurrencyContext.ExchangeRates)
 		}
 		// New transaction is better, replace old one
 		if old != nil {
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed(1)
 			pendingReplaceMeter.Mark(1)
+			pool.trackReplaced(old, hash)
 		}
 		pool.all.Add(tx, isLocal)
 		pool.priced.Put(tx, isLocal)
 		pool.journalTx(from, tx)
+		pool.trackEvent(hash, txpool.TxEventAdded)
+		pool.trackEvent(hash, txpool.TxEventPromoted)
 		pool.queueTxEvent(tx)
 		log.Trace("Pooled new executable transaction", "hash", hash, "from", from, "to", tx.To())
 
@@ -905,6 +949,7 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 		localGauge.Inc(1)
 	}
 	pool.journalTx(from, tx)
+	pool.trackEvent(hash, txpool.TxEventAdded)
 
 	log.Trace("Pooled new future transaction", "hash", hash, "from", from, "to", tx.To())
 	return replaced, nil
@@ -947,13 +992,14 @@ func (pool *LegacyPool) enqueueTx(hash common.Hash, tx *types.Transaction, local
 	if !inserted {
 		// An older transaction was better, discard this
 		queuedDiscardMeter.Mark(1)
-		return false, txpool.ErrReplaceUnderpriced
+		return false, pool.queue[from].replacementError(tx, pool.config.PriceBump, pool.feeCurrencyContext.ExchangeRates)
 	}
 	// Discard any previous transaction and mark this
 	if old != nil {
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		queuedReplaceMeter.Mark(1)
+		pool.trackReplaced(old, hash)
 	} else {
 		// Nothing was replaced, bump the queued counter
 		queuedGauge.Inc(1)
@@ -966,6 +1012,8 @@ func (pool *LegacyPool) enqueueTx(hash common.Hash, tx *types.Transaction, local
 	if addAll {
 		pool.all.Add(tx, local)
 		pool.priced.Put(tx, local)
+	} else {
+		pool.trackEvent(hash, txpool.TxEventDemoted)
 	}
 	// If we never record the heartbeat, do it right now.
 	if _, exist := pool.beats[from]; !exist {
@@ -981,11 +1029,44 @@ func (pool *LegacyPool) journalTx(from common.Address, tx *types.Transaction) {
 	if pool.journal == nil || (!pool.config.JournalRemote && !pool.locals.contains(from)) {
 		return
 	}
+	// Conditionals are not journaled, so skip the transactions depending on them
+	if tx.Conditional() != nil {
+		return
+	}
 	if err := pool.journal.insert(tx); err != nil {
 		log.Warn("Failed to journal local transaction", "err", err)
 	}
 }
 
+// SetAdmission implements txpool.AdmissionScreener, setting the admission
+// policies journaled transactions and pooled ones on reset are screened with.
+func (pool *LegacyPool) SetAdmission(admission *txpool.Admission) {
+	pool.admission.Store(admission)
+}
+
+// screenJournal wraps the add function of the journal loader, rejecting the
+// transactions denied by the admission policies.
+func (pool *LegacyPool) screenJournal(add func([]*types.Transaction) []error) func([]*types.Transaction) []error {
+	return func(txs []*types.Transaction) []error {
+		var (
+			errs     = make([]error, len(txs))
+			admitted = make([]*types.Transaction, 0, len(txs))
+			indices  = make([]int, 0, len(txs))
+		)
+		admission := pool.admission.Load()
+		for i, tx := range txs {
+			if errs[i] = admission.Screen(tx); errs[i] == nil {
+				admitted = append(admitted, tx)
+				indices = append(indices, i)
+			}
+		}
+		for i, err := range add(admitted) {
+			errs[indices[i]] = err
+		}
+		return errs
+	}
+}
+
 // promoteTx adds a transaction to the pending (processable) list of transactions
 // and returns whether it was inserted or an older was better.
 //
@@ -1003,6 +1084,7 @@ func (pool *LegacyPool) promoteTx(addr common.Address, hash common.Hash, tx *typ
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
 		pendingDiscardMeter.Mark(1)
+		pool.trackDropped(types.Transactions{tx}, txpool.TxDropUnderpriced)
 		return false
 	}
 	// Otherwise discard any previous transaction and mark this
@@ -1010,12 +1092,14 @@ func (pool *LegacyPool) promoteTx(addr common.Address, hash common.Hash, tx *typ
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		pendingReplaceMeter.Mark(1)
+		pool.trackReplaced(old, hash)
 	} else {
 		// Nothing was replaced, bump the pending counter
 		pendingGauge.Inc(1)
 	}
 	// Set the potentially new pending nonce and notify any subsystems of the new tx
 	pool.pendingNonces.set(addr, tx.Nonce()+1)
+	pool.trackEvent(hash, txpool.TxEventPromoted)
 
 	// Successful promotion, bump the heartbeat
 	pool.beats[addr] = time.Now()
@@ -1447,6 +1531,9 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 // reset retrieves the current state of the blockchain and ensures the content
 // of the transaction pool is valid with regard to the chain state.
 func (pool *LegacyPool) reset(oldHead, newHead *types.Header) {
+	// Record the inclusion of known transactions before they are evicted as stale
+	pool.trackIncluded(oldHead, newHead)
+
 	// If we're reorging an old state, reinject all dropped transactions
 	var reinject types.Transactions
 
@@ -1571,6 +1658,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 			pool.all.Remove(hash)
 		}
 		log.Trace("Removed old queued transactions", "count", len(forwards))
+		pool.trackStale(forwards)
 
 		// Drop all transactions that are too costly (low balance or out of gas)
 		drops, _ := pool.filter(list, addr, gasLimit)
@@ -1602,6 +1690,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 				log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 			}
 			queuedRateLimitMeter.Mark(int64(len(caps)))
+			pool.trackDropped(caps, txpool.TxDropAccountLimit)
 		}
 		// Mark all the items dropped as removed
 		pool.priced.Removed(len(forwards) + len(drops) + len(caps))
@@ -1670,6 +1759,7 @@ func (pool *LegacyPool) truncatePending() {
 						log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 					}
 					pool.priced.Removed(len(caps))
+					pool.trackDropped(caps, txpool.TxDropPoolFull)
 					pendingGauge.Dec(int64(len(caps)))
 					if pool.locals.contains(offenders[i]) {
 						localGauge.Dec(int64(len(caps)))
@@ -1697,6 +1787,7 @@ func (pool *LegacyPool) truncatePending() {
 					log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 				}
 				pool.priced.Removed(len(caps))
+				pool.trackDropped(caps, txpool.TxDropPoolFull)
 				pendingGauge.Dec(int64(len(caps)))
 				if pool.locals.contains(addr) {
 					localGauge.Dec(int64(len(caps)))
@@ -1736,9 +1827,11 @@ func (pool *LegacyPool) truncateQueue() {
 
 		// Drop all transactions if they are less than the overflow
 		if size := uint64(list.Len()); size <= drop {
-			for _, tx := range list.Flatten() {
+			txs := list.Flatten()
+			for _, tx := range txs {
 				pool.removeTx(tx.Hash(), true, true)
 			}
+			pool.trackDropped(txs, txpool.TxDropPoolFull)
 			drop -= size
 			queuedRateLimitMeter.Mark(int64(size))
 			continue
@@ -1747,6 +1840,7 @@ func (pool *LegacyPool) truncateQueue() {
 		txs := list.Flatten()
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
 			pool.removeTx(txs[i].Hash(), true, true)
+			pool.trackDropped(txs[i:i+1], txpool.TxDropPoolFull)
 			drop--
 			queuedRateLimitMeter.Mark(1)
 		}
@@ -1773,6 +1867,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 			pool.all.Remove(hash)
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
+		pool.trackStale(olds)
 
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
 		drops, invalids := pool.filter(list, addr, gasLimit)
@@ -24,6 +24,8 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -2183,7 +2185,7 @@ func TestReplacement(t *testing.T) {
 	if err := pool.addRemoteSync(pricedTransaction(0, 100000, big.NewInt(1), key)); err != nil {
 		t.Fatalf("failed to add original cheap pending transaction: %v", err)
 	}
-	if err := pool.addRemote(pricedTransaction(0, 100001, big.NewInt(1), key)); err != txpool.ErrReplaceUnderpriced {
+	if err := pool.addRemote(pricedTransaction(0, 100001, big.NewInt(1), key)); !errors.Is(err, txpool.ErrReplaceUnderpriced) {
 		t.Fatalf("original cheap pending transaction replacement error mismatch: have %v, want %v", err, txpool.ErrReplaceUnderpriced)
 	}
 	if err := pool.addRemote(pricedTransaction(0, 100000, big.NewInt(2), key)); err != nil {
@@ -2196,7 +2198,7 @@ func TestReplacement(t *testing.T) {
 	if err := pool.addRemoteSync(pricedTransaction(0, 100000, big.NewInt(price), key)); err != nil {
 		t.Fatalf("failed to add original proper pending transaction: %v", err)
 	}
-	if err := pool.addRemote(pricedTransaction(0, 100001, big.NewInt(threshold-1), key)); err != txpool.ErrReplaceUnderpriced {
+	if err := pool.addRemote(pricedTransaction(0, 100001, big.NewInt(threshold-1), key)); !errors.Is(err, txpool.ErrReplaceUnderpriced) {
 		t.Fatalf("original proper pending transaction replacement error mismatch: have %v, want %v", err, txpool.ErrReplaceUnderpriced)
 	}
 	if err := pool.addRemote(pricedTransaction(0, 100000, big.NewInt(threshold), key)); err != nil {
@@ -2210,7 +2212,7 @@ func TestReplacement(t *testing.T) {
 	if err := pool.addRemote(pricedTransaction(2, 100000, big.NewInt(1), key)); err != nil {
 		t.Fatalf("failed to add original cheap queued transaction: %v", err)
 	}
-	if err := pool.addRemote(pricedTransaction(2, 100001, big.NewInt(1), key)); err != txpool.ErrReplaceUnderpriced {
+	if err := pool.addRemote(pricedTransaction(2, 100001, big.NewInt(1), key)); !errors.Is(err, txpool.ErrReplaceUnderpriced) {
 		t.Fatalf("original cheap queued transaction replacement error mismatch: have %v, want %v", err, txpool.ErrReplaceUnderpriced)
 	}
 	if err := pool.addRemote(pricedTransaction(2, 100000, big.NewInt(2), key)); err != nil {
@@ -2220,7 +2222,7 @@ func TestReplacement(t *testing.T) {
 	if err := pool.addRemote(pricedTransaction(2, 100000, big.NewInt(price), key)); err != nil {
 		t.Fatalf("failed to add original proper queued transaction: %v", err)
 	}
-	if err := pool.addRemote(pricedTransaction(2, 100001, big.NewInt(threshold-1), key)); err != txpool.ErrReplaceUnderpriced {
+	if err := pool.addRemote(pricedTransaction(2, 100001, big.NewInt(threshold-1), key)); !errors.Is(err, txpool.ErrReplaceUnderpriced) {
 		t.Fatalf("original proper queued transaction replacement error mismatch: have %v, want %v", err, txpool.ErrReplaceUnderpriced)
 	}
 	if err := pool.addRemote(pricedTransaction(2, 100000, big.NewInt(threshold), key)); err != nil {
@@ -2264,7 +2266,7 @@ func TestCeloReplacement(t *testing.T) {
 	if err := pool.addRemoteSync(pricedTransaction(0, 50000, big.NewInt(price), key)); err != nil {
 		t.Fatalf("failed to add original cheap pending transaction: %v", err)
 	}
-	if err := pool.addRemoteSync(pricedTransaction(0, 50000, big.NewInt(priceBumped-1), key)); err != txpool.ErrReplaceUnderpriced {
+	if err := pool.addRemoteSync(pricedTransaction(0, 50000, big.NewInt(priceBumped-1), key)); !errors.Is(err, txpool.ErrReplaceUnderpriced) {
 		t.Fatalf("original cheap queued transaction replacement error mismatch: have %v, want %v", err, txpool.ErrReplaceUnderpriced)
 	}
 	if err := pool.addRemoteSync(pricedTransaction(0, 50000, big.NewInt(priceBumped), key)); err != nil {
@@ -2328,7 +2330,7 @@ func TestReplacementDynamicFee(t *testing.T) {
 		}
 		// 2.  Don't bump tip or feecap => discard
 		tx = dynamicFeeTx(nonce, 100001, big.NewInt(2), big.NewInt(1), key)
-		if err := pool.addRemote(tx); err != txpool.ErrReplaceUnderpriced {
+		if err := pool.addRemote(tx); !errors.Is(err, txpool.ErrReplaceUnderpriced) {
 			t.Fatalf("original cheap %s transaction replacement error mismatch: have %v, want %v", stage, err, txpool.ErrReplaceUnderpriced)
 		}
 		// 3.  Bump both more than min => accept
@@ -2351,22 +2353,22 @@ func TestReplacementDynamicFee(t *testing.T) {
 		}
 		// 6.  Bump tip max allowed so it's still underpriced => discard
 		tx = dynamicFeeTx(nonce, 100000, big.NewInt(gasFeeCap), big.NewInt(tipThreshold-1), key)
-		if err := pool.addRemote(tx); err != txpool.ErrReplaceUnderpriced {
+		if err := pool.addRemote(tx); !errors.Is(err, txpool.ErrReplaceUnderpriced) {
 			t.Fatalf("original proper %s transaction replacement error mismatch: have %v, want %v", stage, err, txpool.ErrReplaceUnderpriced)
 		}
 		// 7.  Bump fee cap max allowed so it's still underpriced => discard
 		tx = dynamicFeeTx(nonce, 100000, big.NewInt(feeCapThreshold-1), big.NewInt(gasTipCap), key)
-		if err := pool.addRemote(tx); err != txpool.ErrReplaceUnderpriced {
+		if err := pool.addRemote(tx); !errors.Is(err, txpool.ErrReplaceUnderpriced) {
 			t.Fatalf("original proper %s transaction replacement error mismatch: have %v, want %v", stage, err, txpool.ErrReplaceUnderpriced)
 		}
 		// 8.  Bump tip min for acceptance => accept
 		tx = dynamicFeeTx(nonce, 100000, big.NewInt(gasFeeCap), big.NewInt(tipThreshold), key)
-		if err := pool.addRemote(tx); err != txpool.ErrReplaceUnderpriced {
+		if err := pool.addRemote(tx); !errors.Is(err, txpool.ErrReplaceUnderpriced) {
 			t.Fatalf("original proper %s transaction replacement error mismatch: have %v, want %v", stage, err, txpool.ErrReplaceUnderpriced)
 		}
 		// 9.  Bump fee cap min for acceptance => accept
 		tx = dynamicFeeTx(nonce, 100000, big.NewInt(feeCapThreshold), big.NewInt(gasTipCap), key)
-		if err := pool.addRemote(tx); err != txpool.ErrReplaceUnderpriced {
+		if err := pool.addRemote(tx); !errors.Is(err, txpool.ErrReplaceUnderpriced) {
 			t.Fatalf("original proper %s transaction replacement error mismatch: have %v, want %v", stage, err, txpool.ErrReplaceUnderpriced)
 		}
 		// 10. Check events match expected (3 new executable txs during pending, 0 during queue)
@@ -2519,6 +2521,186 @@ func testJournaling(t *testing.T, nolocals bool, journalRemotes bool) {
 	pool.Close()
 }
 
+// Tests that conditional transactions are not journaled, as their conditional
+// would be lost on a restart.
+func TestJournalingConditional(t *testing.T) {
+	t.Parallel()
+
+	journal := filepath.Join(t.TempDir(), "transactions.rlp")
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.Journal = journal
+	config.Rejournal = time.Second
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+
+	plain, _ := crypto.GenerateKey()
+	conditional, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(plain.PublicKey), big.NewInt(1000000000))
+	testAddBalance(pool, crypto.PubkeyToAddress(conditional.PublicKey), big.NewInt(1000000000))
+
+	if err := pool.addLocal(pricedTransaction(0, 100000, big.NewInt(1), plain)); err != nil {
+		t.Fatalf("failed to add local transaction: %v", err)
+	}
+	tx := pricedTransaction(0, 100000, big.NewInt(1), conditional)
+	tx.SetConditional(&types.TransactionConditional{BlockNumberMax: big.NewInt(100)})
+	if err := pool.addLocal(tx); err != nil {
+		t.Fatalf("failed to add conditional transaction: %v", err)
+	}
+	// Restart the pool after the journal was rotated, only the plain transaction survives
+	time.Sleep(2 * config.Rejournal)
+	pool.Close()
+
+	pool = New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	if pending, queued := pool.Stats(); pending != 1 || queued != 0 {
+		t.Fatalf("pool stats mismatch: have %d pending, %d queued, want 1 pending", pending, queued)
+	}
+	if pool.Has(tx.Hash()) {
+		t.Fatal("conditional transaction restored from journal")
+	}
+}
+
+// Tests that journaled and pooled transactions denied by the admission policies
+// are evicted from the pool.
+func TestAdmissionScreening(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir     = t.TempDir()
+		file    = filepath.Join(dir, "admission.toml")
+		journal = filepath.Join(dir, "transactions.rlp")
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.Journal = journal
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000000))
+		if err := pool.addLocal(pricedTransaction(0, 100000, big.NewInt(1), keys[i])); err != nil {
+			t.Fatalf("failed to add local transaction: %v", err)
+		}
+	}
+	pool.Close()
+
+	// Journaled transactions of denied senders are not loaded
+	deny := func(keys ...*ecdsa.PrivateKey) {
+		t.Helper()
+		var config string
+		for _, key := range keys {
+			config += fmt.Sprintf("%q,", crypto.PubkeyToAddress(key.PublicKey))
+		}
+		if err := os.WriteFile(file, []byte("DeniedSenders = ["+strings.TrimSuffix(config, ",")+"]"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deny(keys[0])
+	admission, err := txpool.NewAdmission(file, types.LatestSigner(params.TestChainConfig))
+	if err != nil {
+		t.Fatalf("failed to load admission policies: %v", err)
+	}
+	defer admission.Close()
+
+	pool = New(config, blockchain)
+	pool.SetAdmission(admission)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	if pending, _ := pool.Stats(); pending != 2 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 2)
+	}
+	// Pooled transactions of senders denied later are evicted on reset
+	deny(keys[0], keys[1])
+	if err := admission.Reload(); err != nil {
+		t.Fatalf("failed to reload admission policies: %v", err)
+	}
+	<-pool.requestReset(nil, nil)
+
+	if pending, _ := pool.Stats(); pending != 1 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 1)
+	}
+	if pool.Get(pricedTransaction(0, 100000, big.NewInt(1), keys[2]).Hash()) == nil {
+		t.Fatal("admitted transaction evicted")
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// baseFeeBlockChain is a test chain whose head has a base fee, so the pool can
+// be reset onto it.
+type baseFeeBlockChain struct {
+	*testBlockChain
+}
+
+func (bc *baseFeeBlockChain) CurrentBlock() *types.Header {
+	head := bc.testBlockChain.CurrentBlock()
+	head.BaseFee = big.NewInt(1)
+	return head
+}
+
+// Tests that the sender rate limit of the admission policies applies within a
+// batch of transactions, and that transactions rejected by the pool are refunded.
+func TestAdmissionRateLimitBatch(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "admission.toml")
+	if err := os.WriteFile(file, []byte("SenderRateLimit = 0.001\nSenderRateBurst = 2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	admission, err := txpool.NewAdmission(file, types.LatestSigner(params.TestChainConfig))
+	if err != nil {
+		t.Fatalf("failed to load admission policies: %v", err)
+	}
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	chain := &baseFeeBlockChain{newTestBlockChain(params.TestChainConfig, 10000000, statedb, new(event.Feed))}
+	legacy := New(testTxPoolConfig, chain)
+	pool, err := txpool.New(testTxPoolConfig.PriceLimit, chain, []txpool.SubPool{legacy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	pool.SetAdmission(admission)
+
+	key, _ := crypto.GenerateKey()
+	testAddBalance(legacy, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+	var batch []*types.Transaction
+	for i := 0; i < 4; i++ {
+		batch = append(batch, pricedTransaction(uint64(i), 100000, big.NewInt(1), key))
+	}
+	errs := pool.Add(batch, false, true)
+	for i, err := range errs {
+		if denied := errors.Is(err, txpool.ErrAdmissionDenied); denied != (i >= 2) {
+			t.Errorf("tx %d: admission mismatch: %v", i, err)
+		}
+	}
+	// Transactions rejected by the pool don't use up the budget
+	broke, _ := crypto.GenerateKey()
+	for i := 0; i < 3; i++ {
+		err := pool.Add([]*types.Transaction{pricedTransaction(0, 100000, big.NewInt(1), broke)}, false, true)[0]
+		if !errors.Is(err, core.ErrInsufficientFunds) {
+			t.Fatalf("unfunded tx %d: unexpected error: %v", i, err)
+		}
+	}
+	testAddBalance(legacy, crypto.PubkeyToAddress(broke.PublicKey), big.NewInt(1000000000))
+	if err := pool.Add([]*types.Transaction{pricedTransaction(0, 100000, big.NewInt(1), broke)}, false, true)[0]; err != nil {
+		t.Fatalf("refunded sender rate limited: %v", err)
+	}
+}
+
 // TestStatusCheck tests that the pool can correctly retrieve the
 // pending status of individual transactions.
 func TestStatusCheck(t *testing.T) {
@@ -0,0 +1,96 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// maxInclusionTrackingDepth is the maximum number of blocks scanned for
+// included transactions on a head change.
+const maxInclusionTrackingDepth = 64
+
+// TxHistory implements txpool.LifecycleTracker, returning the recorded
+// lifecycle events of a transaction.
+func (pool *LegacyPool) TxHistory(hash common.Hash) []txpool.TxLifecycleEvent {
+	return pool.lifecycle.History(hash)
+}
+
+// SubscribeTxLifecycle implements txpool.LifecycleTracker, subscribing to the
+// lifecycle events of all transactions.
+func (pool *LegacyPool) SubscribeTxLifecycle(ch chan<- txpool.TxLifecycleEvent) event.Subscription {
+	return pool.lifecycle.Subscribe(ch)
+}
+
+// trackEvent records a lifecycle event without any details.
+func (pool *LegacyPool) trackEvent(hash common.Hash, typ txpool.TxEventType) {
+	pool.lifecycle.Record(txpool.TxLifecycleEvent{Hash: hash, Type: typ})
+}
+
+// trackReplaced records that old was replaced by a transaction with the same nonce.
+func (pool *LegacyPool) trackReplaced(old *types.Transaction, by common.Hash) {
+	pool.lifecycle.Record(txpool.TxLifecycleEvent{Hash: old.Hash(), Type: txpool.TxEventReplaced, ReplacedBy: by})
+}
+
+// trackDropped records that the transactions were evicted for the given reason.
+func (pool *LegacyPool) trackDropped(txs types.Transactions, reason txpool.TxDropReason) {
+	for _, tx := range txs {
+		pool.lifecycle.Record(txpool.TxLifecycleEvent{Hash: tx.Hash(), Type: txpool.TxEventDropped, Reason: reason})
+	}
+}
+
+// trackStale records the eviction of transactions whose nonce was used, unless
+// they were included in the chain themselves.
+func (pool *LegacyPool) trackStale(txs types.Transactions) {
+	for _, tx := range txs {
+		if pool.lifecycle.Last(tx.Hash()) != txpool.TxEventIncluded {
+			pool.lifecycle.Record(txpool.TxLifecycleEvent{Hash: tx.Hash(), Type: txpool.TxEventDropped, Reason: txpool.TxDropNonceTooLow})
+		}
+	}
+}
+
+// trackIncluded records the inclusion of known transactions in the blocks
+// between oldHead (exclusive) and newHead.
+func (pool *LegacyPool) trackIncluded(oldHead, newHead *types.Header) {
+	if pool.lifecycle == nil || oldHead == nil || newHead == nil {
+		return
+	}
+	var (
+		number = newHead.Number.Uint64()
+		hash   = newHead.Hash()
+	)
+	for depth := 0; depth < maxInclusionTrackingDepth && number > oldHead.Number.Uint64(); depth++ {
+		block := pool.chain.GetBlock(hash, number)
+		if block == nil {
+			return
+		}
+		for _, tx := range block.Transactions() {
+			if pool.lifecycle.Known(tx.Hash()) {
+				pool.lifecycle.Record(txpool.TxLifecycleEvent{
+					Hash:        tx.Hash(),
+					Type:        txpool.TxEventIncluded,
+					BlockHash:   block.Hash(),
+					BlockNumber: block.NumberU64(),
+				})
+			}
+		}
+		hash, number = block.ParentHash(), number-1
+	}
+}
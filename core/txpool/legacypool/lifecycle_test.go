@@ -0,0 +1,95 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func checkTxHistory(t *testing.T, pool *LegacyPool, hash common.Hash, want ...txpool.TxLifecycleEvent) {
+	t.Helper()
+
+	have := pool.TxHistory(hash)
+	if len(have) != len(want) {
+		t.Fatalf("tx %x: history length mismatch: have %d, want %d: %v", hash, len(have), len(want), have)
+	}
+	for i := range want {
+		if have[i].Hash != hash || have[i].Type != want[i].Type || have[i].Reason != want[i].Reason || have[i].ReplacedBy != want[i].ReplacedBy {
+			t.Errorf("tx %x: event %d mismatch: have %+v, want %+v", hash, i, have[i], want[i])
+		}
+	}
+}
+
+// Tests that the lifecycle of transactions is recorded when they are added,
+// replaced and dropped from the pool.
+func TestTxLifecycle(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	events := make(chan txpool.TxLifecycleEvent, 16)
+	sub := pool.SubscribeTxLifecycle(events)
+	defer sub.Unsubscribe()
+
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, addr, big.NewInt(1000000000))
+
+	tx0 := pricedTransaction(0, 100000, big.NewInt(1), key)
+	tx0b := pricedTransaction(0, 100000, big.NewInt(2), key)
+	tx1 := pricedTransaction(1, 100000, big.NewInt(1), key)
+	for _, tx := range []*types.Transaction{tx0, tx0b, tx1} {
+		if err := pool.addRemoteSync(tx); err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+	var (
+		added    = txpool.TxLifecycleEvent{Type: txpool.TxEventAdded}
+		promoted = txpool.TxLifecycleEvent{Type: txpool.TxEventPromoted}
+	)
+	checkTxHistory(t, pool, tx0.Hash(), added, promoted, txpool.TxLifecycleEvent{Type: txpool.TxEventReplaced, ReplacedBy: tx0b.Hash()})
+	checkTxHistory(t, pool, tx0b.Hash(), added, promoted)
+
+	// The replacement is made stale by the account nonce, the other one is no
+	// longer payable
+	testSetNonce(pool, addr, 1)
+	testAddBalance(pool, addr, big.NewInt(-1000000000))
+	<-pool.requestReset(nil, nil)
+
+	checkTxHistory(t, pool, tx0b.Hash(), added, promoted, txpool.TxLifecycleEvent{Type: txpool.TxEventDropped, Reason: txpool.TxDropNonceTooLow})
+	checkTxHistory(t, pool, tx1.Hash(), added, promoted, txpool.TxLifecycleEvent{Type: txpool.TxEventDropped, Reason: txpool.TxDropBalance})
+
+	// All events are delivered to subscribers
+	timeout := time.After(time.Second)
+	for i := 0; i < 9; i++ {
+		select {
+		case <-events:
+		case <-timeout:
+			t.Fatalf("missing lifecycle event %d", i)
+		}
+	}
+	if history := pool.TxHistory(common.Hash{}); history != nil {
+		t.Fatalf("unknown tx has history: %v", history)
+	}
+}
@@ -153,6 +153,24 @@ func (m *sortedMap) filter(filter func(*types.Transaction) bool) types.Transacti
 	return removed
 }
 
+// FilterAdmission removes all transactions denied by the admission policies.
+// Strict-mode invalidated transactions are also returned.
+func (l *list) FilterAdmission(admission *txpool.Admission) (types.Transactions, types.Transactions) {
+	if admission == nil {
+		return nil, nil
+	}
+	removed := l.txs.Filter(func(tx *types.Transaction) bool {
+		return admission.Screen(tx) != nil
+	})
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	invalids := l.dropInvalidsAfterRemovalAndReheap(removed)
+	l.subTotalCost(removed)
+	l.subTotalCost(invalids)
+	return removed, invalids
+}
+
 // Cap places a hard limit on the number of items, returning all transactions
 // exceeding that limit.
 func (m *sortedMap) Cap(threshold int) types.Transactions {
@@ -314,30 +332,9 @@ func (l *list) Add(tx *types.Transaction, priceBump uint64, _ txpool.L1CostFunc,
 	// If there's an older better transaction, abort
 	old := l.txs.Get(tx.Nonce())
 	if old != nil {
-		// Short circuit when it's clear that the new tx is worse
-		if common.AreSameAddress(old.FeeCurrency(), tx.FeeCurrency()) && (old.GasFeeCapCmp(tx) >= 0 || old.GasTipCapCmp(tx) >= 0) {
-			return false, nil
-		}
-		// thresholdFeeCap = oldFC  * (100 + priceBump) / 100
-		a := big.NewInt(100 + int64(priceBump))
-		aFeeCap := new(big.Int).Mul(a, old.GasFeeCap())
-		aTip := a.Mul(a, old.GasTipCap())
-
-		// thresholdTip    = oldTip * (100 + priceBump) / 100
-		b := big.NewInt(100)
-		thresholdFeeCap := aFeeCap.Div(aFeeCap, b)
-		thresholdTip := aTip.Div(aTip, b)
-
-		var thresholdFeeCapInCurrency = thresholdFeeCap
-		var thresholdTipInCurrency = thresholdTip
-		if tx.FeeCurrency() != old.FeeCurrency() {
-			thresholdFeeCapInCurrency = exchange.ConvertCurrency(rates, thresholdFeeCap, old.FeeCurrency(), tx.FeeCurrency())
-			thresholdTipInCurrency = exchange.ConvertCurrency(rates, thresholdTip, old.FeeCurrency(), tx.FeeCurrency())
-		}
-		// We have to ensure that both the new fee cap and tip are higher than the
-		// old ones as well as checking the percentage threshold to ensure that
-		// this is accurate for low (Wei-level) gas price replacements.
-		if tx.GasFeeCapIntCmp(thresholdFeeCapInCurrency) < 0 || tx.GasTipCapIntCmp(thresholdTipInCurrency) < 0 {
+		// CELO: the fee cap and tip are compared by their native equivalent,
+		// see checkReplacement.
+		if checkReplacement(old, tx, priceBump, rates) != nil {
 			return false, nil
 		}
 		// Old is being replaced, subtract old cost
@@ -411,6 +408,26 @@ func (l *list) Filter(costLimits map[common.Address]*uint256.Int, gasLimit uint6
 	return removed, invalids
 }
 
+// FilterConditional removes all transactions which were rejected by the miner
+// for failing their conditions, or whose conditions expired with the given
+// head. Strict-mode invalidated transactions are also returned.
+func (l *list) FilterConditional(head *types.Header) (types.Transactions, types.Transactions) {
+	removed := l.txs.Filter(func(tx *types.Transaction) bool {
+		if tx.Rejected() {
+			return true
+		}
+		cond := tx.Conditional()
+		return cond != nil && cond.Expired(head)
+	})
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	invalids := l.dropInvalidsAfterRemovalAndReheap(removed)
+	l.subTotalCost(removed)
+	l.subTotalCost(invalids)
+	return removed, invalids
+}
+
 // Cap places a hard limit on the number of items, returning all transactions
 // exceeding that limit.
 func (l *list) Cap(threshold int) types.Transactions {
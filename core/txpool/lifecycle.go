@@ -0,0 +1,241 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// maxTxLifecycleEvents is the maximum number of events retained per transaction.
+// When exceeded, the oldest events after the first one are discarded, so the
+// admission of the transaction is always retained.
+const maxTxLifecycleEvents = 32
+
+// maxTxLifecycleQueue is the maximum number of events waiting to be delivered
+// to subscribers. When a slow subscriber lets the queue fill up, new events are
+// still recorded in the history, but not delivered.
+const maxTxLifecycleQueue = 4096
+
+// lifecycleOverflowMeter counts the events not delivered to subscribers because
+// the queue was full.
+var lifecycleOverflowMeter = metrics.NewRegisteredMeter("txpool/lifecycle/overflow", nil)
+
+// TxEventType is the kind of a transaction lifecycle event.
+type TxEventType string
+
+const (
+	TxEventAdded    TxEventType = "added"    // Transaction admitted into the pool
+	TxEventPromoted TxEventType = "promoted" // Transaction became executable
+	TxEventDemoted  TxEventType = "demoted"  // Transaction no longer executable
+	TxEventReplaced TxEventType = "replaced" // Transaction replaced by one with the same nonce
+	TxEventDropped  TxEventType = "dropped"  // Transaction evicted from the pool
+	TxEventIncluded TxEventType = "included" // Transaction included in the chain
+)
+
+// TxDropReason is the reason a transaction was evicted from the pool.
+type TxDropReason string
+
+const (
+	TxDropUnderpriced  TxDropReason = "underpriced"             // Outbid by other transactions or below the minimum tip
+	TxDropFeeCurrency  TxDropReason = "fee-currency-disallowed" // Fee currency no longer allowed
+	TxDropBalance      TxDropReason = "balance-too-low"         // Sender can no longer pay for the transaction
+	TxDropGasLimit     TxDropReason = "gas-limit-exceeded"      // Transaction exceeds the block gas limit
+	TxDropNonceTooLow  TxDropReason = "nonce-too-low"           // Nonce used by another transaction
+	TxDropPoolFull     TxDropReason = "pool-full"               // Global pool limits exceeded
+	TxDropAccountLimit TxDropReason = "account-limit"           // Per account queue limit exceeded
+	TxDropExpired      TxDropReason = "expired"                 // Queued for longer than the pool lifetime
+	TxDropConditional  TxDropReason = "conditional-failed"      // Inclusion conditions failed or expired
+	TxDropAdmission    TxDropReason = "admission-denied"        // Denied by the admission policies
+)
+
+// TxLifecycleEvent is a single state change of a transaction in the pool.
+type TxLifecycleEvent struct {
+	Hash   common.Hash
+	Type   TxEventType
+	Time   time.Time
+	Reason TxDropReason // Set for dropped transactions
+
+	ReplacedBy common.Hash // Set for replaced transactions
+
+	BlockHash   common.Hash // Set for included transactions
+	BlockNumber uint64      // Set for included transactions
+}
+
+// LifecycleTracker is implemented by subpools which record the lifecycle of
+// their transactions.
+type LifecycleTracker interface {
+	// TxHistory returns the recorded lifecycle events of a transaction, oldest
+	// first, or nil if the transaction is unknown.
+	TxHistory(hash common.Hash) []TxLifecycleEvent
+
+	// SubscribeTxLifecycle subscribes to lifecycle events of all transactions.
+	SubscribeTxLifecycle(ch chan<- TxLifecycleEvent) event.Subscription
+}
+
+// TxLifecycle is a bounded log of lifecycle events per transaction hash. Events
+// are delivered to subscribers asynchronously, so events may be recorded while
+// holding pool locks.
+//
+// A nil TxLifecycle is valid and records nothing.
+type TxLifecycle struct {
+	history lru.BasicLRU[common.Hash, []TxLifecycleEvent]
+	queue   []TxLifecycleEvent // Events not yet delivered to subscribers
+	lock    sync.Mutex
+
+	feed  event.Feed
+	scope event.SubscriptionScope
+	wake  chan struct{}
+	quit  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewTxLifecycle creates a lifecycle log retaining the events of up to limit
+// transactions. It returns nil if limit is zero.
+func NewTxLifecycle(limit int) *TxLifecycle {
+	if limit <= 0 {
+		return nil
+	}
+	l := &TxLifecycle{
+		history: lru.NewBasicLRU[common.Hash, []TxLifecycleEvent](limit),
+		wake:    make(chan struct{}, 1),
+		quit:    make(chan struct{}),
+	}
+	l.wg.Add(1)
+	go l.loop()
+	return l
+}
+
+// Close stops delivering events and terminates all subscriptions.
+func (l *TxLifecycle) Close() {
+	if l == nil {
+		return
+	}
+	close(l.quit)
+	l.scope.Close() // Unblocks any pending delivery
+	l.wg.Wait()
+}
+
+// Record appends an event to the history of its transaction.
+func (l *TxLifecycle) Record(ev TxLifecycleEvent) {
+	if l == nil {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	l.lock.Lock()
+	events, _ := l.history.Get(ev.Hash)
+	if len(events) >= maxTxLifecycleEvents {
+		events = append(events[:1], events[2:]...)
+	}
+	l.history.Add(ev.Hash, append(events, ev))
+	if len(l.queue) < maxTxLifecycleQueue {
+		l.queue = append(l.queue, ev)
+	} else {
+		lifecycleOverflowMeter.Mark(1)
+	}
+	l.lock.Unlock()
+
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Known returns whether any events were recorded for the transaction.
+func (l *TxLifecycle) Known(hash common.Hash) bool {
+	if l == nil {
+		return false
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.history.Contains(hash)
+}
+
+// Last returns the type of the latest event recorded for the transaction, or
+// an empty type if the transaction is unknown.
+func (l *TxLifecycle) Last(hash common.Hash) TxEventType {
+	if l == nil {
+		return ""
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	events, ok := l.history.Peek(hash)
+	if !ok || len(events) == 0 {
+		return ""
+	}
+	return events[len(events)-1].Type
+}
+
+// History returns a copy of the events recorded for the transaction.
+func (l *TxLifecycle) History(hash common.Hash) []TxLifecycleEvent {
+	if l == nil {
+		return nil
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	events, ok := l.history.Peek(hash)
+	if !ok {
+		return nil
+	}
+	return append([]TxLifecycleEvent(nil), events...)
+}
+
+// Subscribe registers a subscription for all recorded events.
+func (l *TxLifecycle) Subscribe(ch chan<- TxLifecycleEvent) event.Subscription {
+	if l == nil {
+		return event.NewSubscription(func(quit <-chan struct{}) error {
+			<-quit
+			return nil
+		})
+	}
+	return l.scope.Track(l.feed.Subscribe(ch))
+}
+
+// loop delivers recorded events to subscribers in the order they were recorded.
+func (l *TxLifecycle) loop() {
+	defer l.wg.Done()
+
+	for {
+		select {
+		case <-l.wake:
+			l.lock.Lock()
+			queue := l.queue
+			l.queue = nil
+			l.lock.Unlock()
+
+			for _, ev := range queue {
+				select {
+				case <-l.quit:
+					return
+				default:
+					l.feed.Send(ev)
+				}
+			}
+		case <-l.quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tests that events are still recorded, but not queued without bounds, while a
+// subscriber doesn't read its events.
+func TestTxLifecycleSlowSubscriber(t *testing.T) {
+	lifecycle := NewTxLifecycle(3 * maxTxLifecycleQueue)
+	defer lifecycle.Close()
+
+	sub := lifecycle.Subscribe(make(chan TxLifecycleEvent))
+	defer sub.Unsubscribe()
+
+	for i := 0; i < 3*maxTxLifecycleQueue; i++ {
+		lifecycle.Record(TxLifecycleEvent{Hash: common.Hash{byte(i >> 8), byte(i)}, Type: TxEventAdded})
+	}
+	lifecycle.lock.Lock()
+	queued := len(lifecycle.queue)
+	lifecycle.lock.Unlock()
+	if queued > maxTxLifecycleQueue {
+		t.Fatalf("queue not bounded: %d events", queued)
+	}
+	last := 3*maxTxLifecycleQueue - 1
+	if have := lifecycle.Last(common.Hash{byte(last >> 8), byte(last)}); have != TxEventAdded {
+		t.Fatalf("event not recorded while queue is full: %q", have)
+	}
+}
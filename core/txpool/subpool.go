@@ -43,7 +43,17 @@ type LazyTransaction struct {
 	BlobGas uint64 // Amount of blob gas required by the transaction
 
 	// Celo
-	FeeCurrency *common.Address
+	FeeCurrency       *common.Address // Currency the fees are paid in, nil for the native token
+	NativeDenominated bool            // Whether GasFeeCap and GasTipCap are in the native token despite a FeeCurrency (CIP-66)
+}
+
+// DenominationCurrency returns the currency GasFeeCap and GasTipCap are
+// denominated in, nil meaning the native token.
+func (ltx *LazyTransaction) DenominationCurrency() *common.Address {
+	if ltx.NativeDenominated {
+		return nil
+	}
+	return ltx.FeeCurrency
 }
 
 // Resolve retrieves the full transaction belonging to a lazy handle if it is still
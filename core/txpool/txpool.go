@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math/big"
 	"sync"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
@@ -72,6 +73,11 @@ type TxPool struct {
 	reservations map[common.Address]SubPool // Map with the account to pool reservations
 	reserveLock  sync.Mutex                 // Lock protecting the account reservations
 
+	admission     atomic.Pointer[Admission] // Policies transactions must satisfy to be added
+	admissionSub  event.Subscription        // Subscription to reloads of the admission policies
+	admissionLock sync.Mutex                // Lock protecting the admission subscription
+	rescreen      chan struct{}             // Channel requesting the pooled transactions to be screened
+
 	subs event.SubscriptionScope // Subscription scope to unsubscribe all on shutdown
 	quit chan chan error         // Quit channel to tear down the head updater
 	term chan struct{}           // Termination channel to detect a closed pool
@@ -93,6 +99,7 @@ func New(gasTip uint64, chain BlockChain, subpools []SubPool) (*TxPool, error) {
 		quit:         make(chan chan error),
 		term:         make(chan struct{}),
 		sync:         make(chan chan error),
+		rescreen:     make(chan struct{}),
 	}
 	for i, subpool := range subpools {
 		if err := subpool.Init(gasTip, head, pool.reserver(i, subpool)); err != nil {
@@ -106,6 +113,35 @@ func New(gasTip uint64, chain BlockChain, subpools []SubPool) (*TxPool, error) {
 	return pool, nil
 }
 
+// SetAdmission sets the policies transactions must satisfy to be added to the
+// pool. The pool takes ownership of the policies, closing them on shutdown.
+// Pooled transactions denied by the policies are evicted, and again whenever
+// the policies are reloaded.
+func (p *TxPool) SetAdmission(admission *Admission) {
+	p.admissionLock.Lock()
+	defer p.admissionLock.Unlock()
+
+	if p.admissionSub != nil {
+		p.admissionSub.Unsubscribe()
+		p.admissionSub = nil
+	}
+	if old := p.admission.Swap(admission); old != nil && old != admission {
+		old.Close()
+	}
+	for _, subpool := range p.subpools {
+		if screener, ok := subpool.(AdmissionScreener); ok {
+			screener.SetAdmission(admission)
+		}
+	}
+	if admission != nil {
+		p.admissionSub = admission.SubscribeReloads(p.rescreen)
+	}
+	select {
+	case p.rescreen <- struct{}{}:
+	case <-p.term:
+	}
+}
+
 // reserver is a method to create an address reservation callback to exclusively
 // assign/deassign addresses to/from subpools. This can ensure that at any point
 // in time, only a single subpool is able to manage an account, avoiding cross
@@ -156,12 +192,21 @@ func (p *TxPool) reserver(id int, subpool SubPool) AddressReserver {
 func (p *TxPool) Close() error {
 	var errs []error
 
+	// Stop reloading the admission policies
+	p.admissionLock.Lock()
+	if p.admissionSub != nil {
+		p.admissionSub.Unsubscribe()
+	}
+	p.admissionLock.Unlock()
+	p.admission.Load().Close()
+
 	// Terminate the reset loop and wait for it to finish
 	errc := make(chan error)
 	p.quit <- errc
 	if err := <-errc; err != nil {
 		errs = append(errs, err)
 	}
+
 	// Terminate each subpool
 	for _, subpool := range p.subpools {
 		if err := subpool.Close(); err != nil {
@@ -272,6 +317,11 @@ func (p *TxPool) loop(head *types.Header, chain BlockChain) {
 			// queue waiting for a reset.
 			resetForced = true
 			resetWaiter = syncc
+
+		case <-p.rescreen:
+			// The admission policies changed, run a reset to let the subpools
+			// evict the pooled transactions they deny
+			resetForced = true
 		}
 	}
 	// Notify the closer of termination (no error possible for now)
@@ -319,11 +369,17 @@ func (p *TxPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
 	// so we can piece back the returned errors into the original order.
 	txsets := make([][]*types.Transaction, len(p.subpools))
 	splits := make([]int, len(txs))
+	errs := make([]error, len(txs))
 
+	admission := p.admission.Load()
 	for i, tx := range txs {
 		// Mark this transaction belonging to no-subpool
 		splits[i] = -1
 
+		// Reject the transaction if denied by the admission policies
+		if errs[i] = admission.Admit(tx); errs[i] != nil {
+			continue
+		}
 		// Try to find a subpool that accepts the transaction
 		for j, subpool := range p.subpools {
 			if subpool.Filter(tx) {
@@ -339,16 +395,23 @@ func (p *TxPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
 	for i := 0; i < len(p.subpools); i++ {
 		errsets[i] = p.subpools[i].Add(txsets[i], local, sync)
 	}
-	errs := make([]error, len(txs))
 	for i, split := range splits {
+		// If the transaction was denied admission, retain the reason
+		if errs[i] != nil {
+			continue
+		}
 		// If the transaction was rejected by all subpools, mark it unsupported
 		if split == -1 {
 			errs[i] = core.ErrTxTypeNotSupported
+			admission.Record(txs[i], false)
 			continue
 		}
 		// Find which subpool handled it and pull in the corresponding error
 		errs[i] = errsets[split][0]
 		errsets[split] = errsets[split][1:]
+
+		// Refund the admission policies for transactions not added after all
+		admission.Record(txs[i], errs[i] == nil)
 	}
 	return errs
 }
@@ -466,6 +529,31 @@ func (p *TxPool) Status(hash common.Hash) TxStatus {
 	return TxStatusUnknown
 }
 
+// TxHistory returns the recorded lifecycle events of a transaction, oldest
+// first, or nil if no subpool has seen it.
+func (p *TxPool) TxHistory(hash common.Hash) []TxLifecycleEvent {
+	for _, subpool := range p.subpools {
+		if tracker, ok := subpool.(LifecycleTracker); ok {
+			if events := tracker.TxHistory(hash); len(events) > 0 {
+				return events
+			}
+		}
+	}
+	return nil
+}
+
+// SubscribeTxLifecycle registers a subscription for the lifecycle events of
+// all transactions.
+func (p *TxPool) SubscribeTxLifecycle(ch chan<- TxLifecycleEvent) event.Subscription {
+	var subs []event.Subscription
+	for _, subpool := range p.subpools {
+		if tracker, ok := subpool.(LifecycleTracker); ok {
+			subs = append(subs, tracker.SubscribeTxLifecycle(ch))
+		}
+	}
+	return p.subs.Track(event.JoinSubscriptions(subs...))
+}
+
 // Sync is a helper method for unit tests or simulator runs where the chain events
 // are arriving in quick succession, without any time in between them to run the
 // internal background reset operations. This method will run an explicit reset
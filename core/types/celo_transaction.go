@@ -49,6 +49,23 @@ func (tx *Transaction) FeeCurrency() *common.Address {
 	return feeCurrency
 }
 
+// IsFeeCurrencyDenominated returns whether the gas price related fields of
+// the transaction are denominated in its fee currency. CeloDenominatedTx
+// (CIP-66) transactions pay their fees in a fee currency, but their gas price
+// fields are denominated in the native token.
+func (tx *Transaction) IsFeeCurrencyDenominated() bool {
+	return tx.FeeCurrency() != nil && tx.Type() != CeloDenominatedTxType
+}
+
+// DenominationCurrency returns the currency in which the gas price related
+// fields of the transaction are denominated, nil meaning the native token.
+func (tx *Transaction) DenominationCurrency() *common.Address {
+	if !tx.IsFeeCurrencyDenominated() {
+		return nil
+	}
+	return tx.FeeCurrency()
+}
+
 // GatewayFee returns the gateway fee of the transaction if there is one.
 // Note: this is here to support serving legacy transactions over the RPC, it should not be used in new code.
 func (tx *Transaction) GatewayFee() *big.Int {
@@ -98,16 +115,16 @@ func CompareWithRates(a, b *Transaction, ratesAndFees *exchange.RatesAndFees) in
 	}
 	rates := ratesAndFees.Rates
 	if ratesAndFees.HasBaseFee() {
-		tipA := a.EffectiveGasTipValue(ratesAndFees.GetBaseFeeIn(a.FeeCurrency()))
-		tipB := b.EffectiveGasTipValue(ratesAndFees.GetBaseFeeIn(b.FeeCurrency()))
-		c, _ := exchange.CompareValue(rates, tipA, a.FeeCurrency(), tipB, b.FeeCurrency())
+		tipA := a.EffectiveGasTipValue(ratesAndFees.GetBaseFeeIn(a.DenominationCurrency()))
+		tipB := b.EffectiveGasTipValue(ratesAndFees.GetBaseFeeIn(b.DenominationCurrency()))
+		c, _ := exchange.CompareValue(rates, tipA, a.DenominationCurrency(), tipB, b.DenominationCurrency())
 		return c
 	}
 
 	// Compare fee caps if baseFee is not specified or effective tips are equal
 	feeA := a.inner.gasFeeCap()
 	feeB := b.inner.gasFeeCap()
-	c, _ := exchange.CompareValue(rates, feeA, a.FeeCurrency(), feeB, b.FeeCurrency())
+	c, _ := exchange.CompareValue(rates, feeA, a.DenominationCurrency(), feeB, b.DenominationCurrency())
 	if c != 0 {
 		return c
 	}
@@ -115,7 +132,7 @@ func CompareWithRates(a, b *Transaction, ratesAndFees *exchange.RatesAndFees) in
 	// Compare tips if effective tips and fee caps are equal
 	tipCapA := a.inner.gasTipCap()
 	tipCapB := b.inner.gasTipCap()
-	c, _ = exchange.CompareValue(rates, tipCapA, a.FeeCurrency(), tipCapB, b.FeeCurrency())
+	c, _ = exchange.CompareValue(rates, tipCapA, a.DenominationCurrency(), tipCapB, b.DenominationCurrency())
 	return c
 }
 
@@ -7,7 +7,7 @@ var (
 	// celoSigner. This list is ordered with more recent forks appearing
 	// earlier. It is assumed that if a more recent fork is active then all
 	// previous forks are also active.
-	celoForks = forks{&cel2{}, &celoLegacy{}}
+	celoForks = forks{&cip66{}, &cel2{}, &celoLegacy{}}
 )
 
 type forks []fork
@@ -46,6 +46,27 @@ type fork interface {
 	txFuncs(tx *Transaction) *txFuncs
 }
 
+// cip66 is the fork enabling CeloDenominatedTxType transactions, whose fee
+// fields are denominated in the native token while the fee is paid in a fee
+// currency.
+type cip66 struct{}
+
+func (c *cip66) active(blockTime uint64, config *params.ChainConfig) bool {
+	return config.IsCip66(blockTime)
+}
+
+func (c *cip66) equal(other fork) bool {
+	_, ok := other.(*cip66)
+	return ok
+}
+
+func (c *cip66) txFuncs(tx *Transaction) *txFuncs {
+	if tx.Type() == CeloDenominatedTxType {
+		return celoDenominatedTxFuncs
+	}
+	return nil
+}
+
 // Cel2 is the fork marking the transition point from an L1 to an L2.
 // It deprecates CeloDynamicFeeTxType and LegacyTxTypes with CeloLegacy set to true.
 type cel2 struct{}
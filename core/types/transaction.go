@@ -64,6 +64,10 @@ type Transaction struct {
 
 	// cache of details to compute the data availability fee
 	rollupCostData atomic.Value
+
+	// conditions for inclusion, only kept locally
+	conditional atomic.Pointer[TransactionConditional]
+	rejected    atomic.Bool // set if the conditions failed when building a block
 }
 
 // NewTx creates a new transaction.
@@ -369,7 +373,11 @@ func (tx *Transaction) IsSystemTx() bool {
 // - cost in feeCurrency: (gas * gasPrice) + (blobGas * blobGasPrice)
 // - native token cost: value sent to target contract
 // For non-feeCurrency transactions, the first value is zero and the second is the total cost.
+// For CIP-66 transactions the fee currency cost is bounded by maxFeeInFeeCurrency.
 func (tx *Transaction) Cost() (*big.Int, *big.Int) {
+	if maxFee := tx.MaxFeeInFeeCurrency(); maxFee != nil && tx.FeeCurrency() != nil {
+		return new(big.Int).Set(maxFee), tx.Value()
+	}
 	total := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
 	if tx.Type() == BlobTxType {
 		total.Add(total, new(big.Int).Mul(tx.BlobGasFeeCap(), new(big.Int).SetUint64(tx.BlobGas())))
@@ -378,7 +386,6 @@ func (tx *Transaction) Cost() (*big.Int, *big.Int) {
 		nativeCost := total.Add(total, tx.Value())
 		return new(big.Int), nativeCost
 	} else {
-		// Will need to be updated for CIP-66
 		nativeCost := tx.Value()
 		return total, nativeCost
 	}
@@ -582,6 +589,28 @@ func (tx *Transaction) Time() time.Time {
 	return tx.time
 }
 
+// Conditional returns the conditions for including the transaction, or nil if
+// it's unconditional. Conditionals are not part of the transaction encoding.
+func (tx *Transaction) Conditional() *TransactionConditional {
+	return tx.conditional.Load()
+}
+
+// SetConditional sets the conditions for including the transaction.
+func (tx *Transaction) SetConditional(cond *TransactionConditional) {
+	tx.conditional.Store(cond)
+}
+
+// Rejected returns whether the conditions of the transaction failed while
+// building a block, so it should be evicted from the pool.
+func (tx *Transaction) Rejected() bool {
+	return tx.rejected.Load()
+}
+
+// SetRejected marks the transaction as failing its conditions.
+func (tx *Transaction) SetRejected() {
+	tx.rejected.Store(true)
+}
+
 // Hash returns the transaction hash.
 func (tx *Transaction) Hash() common.Hash {
 	if hash := tx.hash.Load(); hash != nil {
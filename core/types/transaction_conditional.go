@@ -0,0 +1,184 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// TransactionConditionalMaxCost is the maximum cost of a transaction
+// conditional, as returned by Cost.
+const TransactionConditionalMaxCost = 1000
+
+var (
+	ErrConditionalCost      = fmt.Errorf("conditional cost exceeds maximum of %d", TransactionConditionalMaxCost)
+	ErrConditionalBlockMin  = errors.New("block number below conditional minimum")
+	ErrConditionalBlockMax  = errors.New("block number above conditional maximum")
+	ErrConditionalTimeMin   = errors.New("timestamp below conditional minimum")
+	ErrConditionalTimeMax   = errors.New("timestamp above conditional maximum")
+	errConditionalBlockSpan = errors.New("conditional block number minimum above maximum")
+	errConditionalTimeSpan  = errors.New("conditional timestamp minimum above maximum")
+)
+
+// KnownAccount is the expected storage of an account: either its storage root,
+// or the values of some of its storage slots.
+type KnownAccount struct {
+	StorageRoot  *common.Hash
+	StorageSlots map[common.Hash]common.Hash
+}
+
+// MarshalJSON encodes the known account as either the storage root, or the
+// object of the slot values.
+func (ka KnownAccount) MarshalJSON() ([]byte, error) {
+	if ka.StorageRoot != nil {
+		return json.Marshal(ka.StorageRoot)
+	}
+	return json.Marshal(ka.StorageSlots)
+}
+
+// UnmarshalJSON decodes the known account from either a storage root, or an
+// object of slot values.
+func (ka *KnownAccount) UnmarshalJSON(input []byte) error {
+	var root common.Hash
+	if err := json.Unmarshal(input, &root); err == nil {
+		ka.StorageRoot, ka.StorageSlots = &root, nil
+		return nil
+	}
+	var slots map[common.Hash]common.Hash
+	if err := json.Unmarshal(input, &slots); err != nil {
+		return errors.New("known account must be a storage root or an object of storage slots")
+	}
+	ka.StorageRoot, ka.StorageSlots = nil, slots
+	return nil
+}
+
+// KnownAccounts are the expected storage of accounts.
+type KnownAccounts map[common.Address]KnownAccount
+
+// TransactionConditional are the conditions which must hold for a transaction
+// to be included in a block. All fields are optional.
+type TransactionConditional struct {
+	KnownAccounts  KnownAccounts `json:"knownAccounts"`
+	BlockNumberMin *big.Int      `json:"blockNumberMin,omitempty"`
+	BlockNumberMax *big.Int      `json:"blockNumberMax,omitempty"`
+	TimestampMin   *uint64       `json:"timestampMin,omitempty"`
+	TimestampMax   *uint64       `json:"timestampMax,omitempty"`
+}
+
+// transactionConditionalJSON is the JSON representation of the conditional.
+type transactionConditionalJSON struct {
+	KnownAccounts  KnownAccounts   `json:"knownAccounts"`
+	BlockNumberMin *hexutil.Big    `json:"blockNumberMin,omitempty"`
+	BlockNumberMax *hexutil.Big    `json:"blockNumberMax,omitempty"`
+	TimestampMin   *hexutil.Uint64 `json:"timestampMin,omitempty"`
+	TimestampMax   *hexutil.Uint64 `json:"timestampMax,omitempty"`
+}
+
+// MarshalJSON encodes the conditional with hex numbers.
+func (c TransactionConditional) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&transactionConditionalJSON{
+		KnownAccounts:  c.KnownAccounts,
+		BlockNumberMin: (*hexutil.Big)(c.BlockNumberMin),
+		BlockNumberMax: (*hexutil.Big)(c.BlockNumberMax),
+		TimestampMin:   (*hexutil.Uint64)(c.TimestampMin),
+		TimestampMax:   (*hexutil.Uint64)(c.TimestampMax),
+	})
+}
+
+// UnmarshalJSON decodes the conditional from hex numbers.
+func (c *TransactionConditional) UnmarshalJSON(input []byte) error {
+	var dec transactionConditionalJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	c.KnownAccounts = dec.KnownAccounts
+	c.BlockNumberMin = (*big.Int)(dec.BlockNumberMin)
+	c.BlockNumberMax = (*big.Int)(dec.BlockNumberMax)
+	c.TimestampMin = (*uint64)(dec.TimestampMin)
+	c.TimestampMax = (*uint64)(dec.TimestampMax)
+	return nil
+}
+
+// Cost returns the number of state lookups needed to check the conditional.
+func (c *TransactionConditional) Cost() int {
+	cost := 0
+	for _, account := range c.KnownAccounts {
+		if account.StorageRoot != nil {
+			cost++
+		} else {
+			cost += len(account.StorageSlots)
+		}
+	}
+	if c.BlockNumberMin != nil || c.BlockNumberMax != nil {
+		cost++
+	}
+	if c.TimestampMin != nil || c.TimestampMax != nil {
+		cost++
+	}
+	return cost
+}
+
+// Validate checks that the conditional is well formed and not too costly.
+func (c *TransactionConditional) Validate() error {
+	if c.Cost() > TransactionConditionalMaxCost {
+		return ErrConditionalCost
+	}
+	if c.BlockNumberMin != nil && c.BlockNumberMax != nil && c.BlockNumberMin.Cmp(c.BlockNumberMax) > 0 {
+		return errConditionalBlockSpan
+	}
+	if c.TimestampMin != nil && c.TimestampMax != nil && *c.TimestampMin > *c.TimestampMax {
+		return errConditionalTimeSpan
+	}
+	return nil
+}
+
+// CheckBlockNumber checks the block number range of the conditional.
+func (c *TransactionConditional) CheckBlockNumber(number *big.Int) error {
+	if c.BlockNumberMin != nil && number.Cmp(c.BlockNumberMin) < 0 {
+		return fmt.Errorf("%w: have %v, want %v", ErrConditionalBlockMin, number, c.BlockNumberMin)
+	}
+	if c.BlockNumberMax != nil && number.Cmp(c.BlockNumberMax) > 0 {
+		return fmt.Errorf("%w: have %v, want %v", ErrConditionalBlockMax, number, c.BlockNumberMax)
+	}
+	return nil
+}
+
+// CheckTimestamp checks the timestamp range of the conditional.
+func (c *TransactionConditional) CheckTimestamp(time uint64) error {
+	if c.TimestampMin != nil && time < *c.TimestampMin {
+		return fmt.Errorf("%w: have %d, want %d", ErrConditionalTimeMin, time, *c.TimestampMin)
+	}
+	if c.TimestampMax != nil && time > *c.TimestampMax {
+		return fmt.Errorf("%w: have %d, want %d", ErrConditionalTimeMax, time, *c.TimestampMax)
+	}
+	return nil
+}
+
+// Expired returns whether the conditional can no longer hold in any block
+// following the given head.
+func (c *TransactionConditional) Expired(head *Header) bool {
+	if c.BlockNumberMax != nil && head.Number.Cmp(c.BlockNumberMax) >= 0 {
+		return true
+	}
+	return c.TimestampMax != nil && head.Time >= *c.TimestampMax
+}
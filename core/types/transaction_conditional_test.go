@@ -0,0 +1,130 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestTransactionConditionalJSON(t *testing.T) {
+	input := `{
+		"knownAccounts": {
+			"0x000000000000000000000000000000000000aaaa": "0x0000000000000000000000000000000000000000000000000000000000000001",
+			"0x000000000000000000000000000000000000bbbb": {
+				"0x0000000000000000000000000000000000000000000000000000000000000002": "0x0000000000000000000000000000000000000000000000000000000000000003"
+			}
+		},
+		"blockNumberMin": "0x10",
+		"timestampMax": "0x20"
+	}`
+	var cond TransactionConditional
+	if err := json.Unmarshal([]byte(input), &cond); err != nil {
+		t.Fatalf("failed to decode conditional: %v", err)
+	}
+	root := common.HexToHash("0x01")
+	want := TransactionConditional{
+		KnownAccounts: KnownAccounts{
+			common.HexToAddress("0xaaaa"): {StorageRoot: &root},
+			common.HexToAddress("0xbbbb"): {StorageSlots: map[common.Hash]common.Hash{
+				common.HexToHash("0x02"): common.HexToHash("0x03"),
+			}},
+		},
+		BlockNumberMin: big.NewInt(16),
+		TimestampMax:   new(uint64),
+	}
+	*want.TimestampMax = 32
+	if !reflect.DeepEqual(cond, want) {
+		t.Fatalf("decoded conditional mismatch: have %+v, want %+v", cond, want)
+	}
+	enc, err := json.Marshal(cond)
+	if err != nil {
+		t.Fatalf("failed to encode conditional: %v", err)
+	}
+	var dec TransactionConditional
+	if err := json.Unmarshal(enc, &dec); err != nil {
+		t.Fatalf("failed to decode encoded conditional: %v", err)
+	}
+	if !reflect.DeepEqual(dec, want) {
+		t.Fatalf("round trip mismatch: have %+v, want %+v", dec, want)
+	}
+	if err := json.Unmarshal([]byte(`{"knownAccounts":{"0x000000000000000000000000000000000000aaaa":1}}`), &dec); err == nil {
+		t.Fatal("decoded invalid known account")
+	}
+}
+
+func TestTransactionConditionalChecks(t *testing.T) {
+	min, max := uint64(100), uint64(200)
+	cond := &TransactionConditional{
+		KnownAccounts: KnownAccounts{
+			common.HexToAddress("0xaaaa"): {StorageRoot: &common.Hash{}},
+			common.HexToAddress("0xbbbb"): {StorageSlots: map[common.Hash]common.Hash{{1}: {}, {2}: {}}},
+		},
+		BlockNumberMin: big.NewInt(10),
+		BlockNumberMax: big.NewInt(20),
+		TimestampMin:   &min,
+		TimestampMax:   &max,
+	}
+	if cost := cond.Cost(); cost != 5 {
+		t.Errorf("cost mismatch: have %d, want 5", cost)
+	}
+	if err := cond.Validate(); err != nil {
+		t.Errorf("valid conditional rejected: %v", err)
+	}
+	for number, want := range map[int64]error{9: ErrConditionalBlockMin, 10: nil, 20: nil, 21: ErrConditionalBlockMax} {
+		if err := cond.CheckBlockNumber(big.NewInt(number)); !errors.Is(err, want) {
+			t.Errorf("block %d: error mismatch: have %v, want %v", number, err, want)
+		}
+	}
+	for time, want := range map[uint64]error{99: ErrConditionalTimeMin, 100: nil, 200: nil, 201: ErrConditionalTimeMax} {
+		if err := cond.CheckTimestamp(time); !errors.Is(err, want) {
+			t.Errorf("timestamp %d: error mismatch: have %v, want %v", time, err, want)
+		}
+	}
+	tests := []struct {
+		number, time uint64
+		expired      bool
+	}{
+		{19, 199, false},
+		{20, 100, true},
+		{10, 200, true},
+	}
+	for _, tt := range tests {
+		head := &Header{Number: new(big.Int).SetUint64(tt.number), Time: tt.time}
+		if expired := cond.Expired(head); expired != tt.expired {
+			t.Errorf("head %d/%d: expired mismatch: have %v, want %v", tt.number, tt.time, expired, tt.expired)
+		}
+	}
+	// Malformed conditionals
+	cond.BlockNumberMin = big.NewInt(21)
+	if err := cond.Validate(); err == nil {
+		t.Error("conditional with empty block range accepted")
+	}
+	slots := make(map[common.Hash]common.Hash)
+	for i := 0; i <= TransactionConditionalMaxCost; i++ {
+		slots[common.BigToHash(big.NewInt(int64(i)))] = common.Hash{}
+	}
+	costly := &TransactionConditional{KnownAccounts: KnownAccounts{{}: {StorageSlots: slots}}}
+	if err := costly.Validate(); !errors.Is(err, ErrConditionalCost) {
+		t.Errorf("costly conditional error mismatch: have %v, want %v", err, ErrConditionalCost)
+	}
+}
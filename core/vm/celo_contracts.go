@@ -16,6 +16,15 @@ type CeloPrecompiledContract interface {
 	Run(input []byte, ctx *celoPrecompileContext) ([]byte, error) // Run runs the precompiled contract
 }
 
+// CeloDynamicGasPrecompiledContract is implemented by Celo precompiles whose
+// gas cost depends on the state (e.g. the access list or account existence)
+// and not only on the input. If implemented, DynamicGas takes precedence over
+// RequiredGas.
+type CeloDynamicGasPrecompiledContract interface {
+	CeloPrecompiledContract
+	DynamicGas(input []byte, ctx *celoPrecompileContext) uint64 // DynamicGas calculates the contract gas use, warming accessed accounts
+}
+
 type wrap struct {
 	PrecompiledContract
 }
@@ -46,6 +55,19 @@ func celoPrecompileAddress(index byte) common.Address {
 	return common.BytesToAddress(append([]byte{0}, (celoPrecompiledContractsAddressOffset - index)))
 }
 
+// accessAccountGas returns the EIP-2929 account access cost for addr and adds
+// addr to the access list if it was cold, mirroring the CALL opcode.
+func (ctx *celoPrecompileContext) accessAccountGas(addr common.Address) uint64 {
+	if !ctx.IsEIP2929 {
+		return 0
+	}
+	if ctx.evm.StateDB.AddressInAccessList(addr) {
+		return params.WarmStorageReadCostEIP2929
+	}
+	ctx.evm.StateDB.AddAddressToAccessList(addr)
+	return params.ColdAccountAccessCostEIP2929
+}
+
 func (ctx *celoPrecompileContext) IsCallerCeloToken() (bool, error) {
 	tokenAddress := addresses.GetAddresses(ctx.evm.ChainConfig().ChainID).CeloToken
 
@@ -59,6 +81,27 @@ func (c *transfer) RequiredGas(input []byte) uint64 {
 	return params.CallValueTransferGas
 }
 
+// DynamicGas charges the same as a CALL transferring value from `from` to `to`
+// once the CeloTransferGas fork is active: account access costs for both
+// addresses, the value transfer cost and, if needed, the new account cost.
+func (c *transfer) DynamicGas(input []byte, ctx *celoPrecompileContext) uint64 {
+	if !ctx.IsCeloTransferGas || len(input) != 96 {
+		return c.RequiredGas(input)
+	}
+	from := common.BytesToAddress(input[0:32])
+	to := common.BytesToAddress(input[32:64])
+	value := new(uint256.Int).SetBytes(input[64:96])
+
+	gas := ctx.accessAccountGas(from) + ctx.accessAccountGas(to)
+	if !value.IsZero() {
+		gas += params.CallValueTransferGas
+		if ctx.evm.StateDB.Empty(to) {
+			gas += params.CallNewAccountGas
+		}
+	}
+	return gas
+}
+
 func (c *transfer) Run(input []byte, ctx *celoPrecompileContext) ([]byte, error) {
 	if isCeloToken, err := ctx.IsCallerCeloToken(); err != nil {
 		return nil, err
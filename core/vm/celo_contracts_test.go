@@ -6,6 +6,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/contracts/addresses"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/holiman/uint256"
@@ -118,3 +121,84 @@ func TestPrecompileTransfer(t *testing.T) {
 		})
 	}
 }
+
+func transferInput(from, to common.Address, value uint64) []byte {
+	input := make([]byte, 0, 96)
+	input = append(input, common.LeftPadBytes(from.Bytes(), 32)...)
+	input = append(input, common.LeftPadBytes(to.Bytes(), 32)...)
+	return append(input, common.LeftPadBytes(new(big.Int).SetUint64(value).Bytes(), 32)...)
+}
+
+func TestPrecompileTransferGas(t *testing.T) {
+	var (
+		forkTime = uint64(100)
+		from     = common.HexToAddress("0xf000")
+		existing = common.HexToAddress("0xe000")
+		fresh    = common.HexToAddress("0xa000")
+		coldCost = params.ColdAccountAccessCostEIP2929
+		warmCost = params.WarmStorageReadCostEIP2929
+	)
+	config := *params.TestChainConfig
+	config.CeloTransferGasTime = &forkTime
+
+	tests := []struct {
+		name  string
+		time  uint64
+		to    common.Address
+		value uint64
+		warm  []common.Address
+		gas   uint64
+	}{
+		{"pre-fork flat cost", forkTime - 1, fresh, 1, nil, params.CallValueTransferGas},
+		{"cold accounts", forkTime, existing, 1, nil, 2*coldCost + params.CallValueTransferGas},
+		{"warm sender", forkTime, existing, 1, []common.Address{from}, warmCost + coldCost + params.CallValueTransferGas},
+		{"warm accounts", forkTime, existing, 1, []common.Address{from, existing}, 2*warmCost + params.CallValueTransferGas},
+		{"new account", forkTime, fresh, 1, []common.Address{from, fresh}, 2*warmCost + params.CallValueTransferGas + params.CallNewAccountGas},
+		{"zero value to new account", forkTime, fresh, 0, []common.Address{from, fresh}, 2 * warmCost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+			statedb.SetBalance(from, uint256.NewInt(100), tracing.BalanceChangeUnspecified)
+			statedb.SetBalance(existing, uint256.NewInt(100), tracing.BalanceChangeUnspecified)
+			for _, addr := range tt.warm {
+				statedb.AddAddressToAccessList(addr)
+			}
+			blockCtx := vmBlockCtx
+			blockCtx.Time = tt.time
+			evm := NewEVM(blockCtx, vmTxCtx, statedb, &config, Config{})
+
+			ctx := NewContext(addresses.MainnetAddresses.CeloToken, evm)
+			input := transferInput(from, tt.to, tt.value)
+			if have := (&transfer{}).DynamicGas(input, ctx); have != tt.gas {
+				t.Errorf("wrong gas: have %d, want %d", have, tt.gas)
+			}
+			if tt.time >= forkTime {
+				if !statedb.AddressInAccessList(from) || !statedb.AddressInAccessList(tt.to) {
+					t.Error("expected from and to to be warm after gas calculation")
+				}
+			}
+		})
+	}
+}
+
+func TestPrecompileTransferGasOOG(t *testing.T) {
+	var (
+		forkTime = uint64(0)
+		from     = common.HexToAddress("0xf000")
+		to       = common.HexToAddress("0xa000")
+	)
+	config := *params.TestChainConfig
+	config.CeloTransferGasTime = &forkTime
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetBalance(from, uint256.NewInt(100), tracing.BalanceChangeUnspecified)
+	evm := NewEVM(vmBlockCtx, vmTxCtx, statedb, &config, Config{})
+
+	// The pre-fork flat cost is no longer enough to create a new account
+	ctx := NewContext(addresses.MainnetAddresses.CeloToken, evm)
+	_, _, err := RunPrecompiledContract(&transfer{}, transferInput(from, to, 1), params.CallValueTransferGas, nil, ctx)
+	if err != ErrOutOfGas {
+		t.Fatalf("expected %v, got %v", ErrOutOfGas, err)
+	}
+}
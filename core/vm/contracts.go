@@ -271,6 +271,9 @@ func ActivePrecompiles(rules params.Rules) []common.Address {
 // - any error that occurred
 func RunPrecompiledContract(p CeloPrecompiledContract, input []byte, suppliedGas uint64, logger *tracing.Hooks, ctx *celoPrecompileContext) (ret []byte, remainingGas uint64, err error) {
 	gasCost := p.RequiredGas(input)
+	if dp, ok := p.(CeloDynamicGasPrecompiledContract); ok && ctx != nil {
+		gasCost = dp.DynamicGas(input, ctx)
+	}
 	if suppliedGas < gasCost {
 		return nil, 0, ErrOutOfGas
 	}
@@ -43,6 +43,11 @@ type Config struct {
 	PrecompileOverrides PrecompileOverrides             // Precompiles can be swapped / changed / wrapped as needed
 	NoMaxCodeSize       bool                            // Ignore Max code size and max init code size limits
 	CallerOverride      func(v ContractRef) ContractRef // Swap the caller as needed, for VM prank functionality.
+
+	// Celo: called with the gas used for debiting and crediting fees in a fee
+	// currency. Only set when importing blocks, so fee currency gas statistics
+	// are not affected by calls, traces and payload building.
+	OnFeeCurrencyGas func(feeCurrency common.Address, gasUsedDebit, gasUsedCredit, intrinsicGas uint64)
 }
 
 // ScopeContext contains the things that are per-call, such as stack and memory,
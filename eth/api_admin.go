@@ -24,8 +24,11 @@ import (
 	"os"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -141,3 +144,115 @@ func (api *AdminAPI) ImportChain(file string) (bool, error) {
 	}
 	return true, nil
 }
+
+// TxPoolImportResult summarises the outcome of importing an exported pool.
+type TxPoolImportResult struct {
+	Imported int                    `json:"imported"`
+	Known    int                    `json:"known"`
+	Rejected []TxPoolImportRejected `json:"rejected"`
+
+	// Demoted lists the imported transactions which were executable at the
+	// exporting node, but are only queued after the import, e.g. because an
+	// earlier transaction of the sender was rejected.
+	Demoted []common.Hash `json:"demoted"`
+}
+
+// TxPoolImportRejected is an imported transaction the pool did not accept.
+type TxPoolImportRejected struct {
+	Hash        common.Hash     `json:"hash"`
+	Pending     bool            `json:"pending"`
+	FeeCurrency *common.Address `json:"feeCurrency,omitempty"`
+	Error       string          `json:"error"`
+}
+
+// ExportTxPool exports all pending and queued transactions of the pool, along
+// with their arrival time and local flag, into a local file in the order they
+// arrived. The file can be passed to ImportTxPool of another node to hand over
+// the pool.
+func (api *AdminAPI) ExportTxPool(file string) (bool, error) {
+	if _, err := os.Stat(file); err == nil {
+		// File already exists. Allowing overwrite could be a DoS vector,
+		// since the 'file' may point to arbitrary paths on the drive.
+		return false, errors.New("location would overwrite an existing file")
+	}
+	out, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return false, err
+	}
+	var (
+		writer io.Writer = out
+		zipper *gzip.Writer
+	)
+	if strings.HasSuffix(file, ".gz") {
+		zipper = gzip.NewWriter(writer)
+		writer = zipper
+	}
+	// The export is only complete once the compressed stream and the file are
+	// flushed, so their close errors fail the export as well.
+	n, err := api.eth.TxPool().Export(writer)
+	if err == nil && zipper != nil {
+		err = zipper.Close()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return false, err
+	}
+	log.Info("Exported transaction pool", "count", n, "file", file)
+	return true, nil
+}
+
+// ImportTxPool imports transactions exported by ExportTxPool from a local file,
+// validating them against the current head of this node. If the import fails
+// partway, the result so far is returned along with the error.
+func (api *AdminAPI) ImportTxPool(file string) (*TxPoolImportResult, error) {
+	in, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	var reader io.Reader = in
+	if strings.HasSuffix(file, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return nil, err
+		}
+	}
+	var (
+		pool    = api.eth.TxPool()
+		result  = &TxPoolImportResult{Rejected: []TxPoolImportRejected{}, Demoted: []common.Hash{}}
+		pending []common.Hash
+	)
+	_, err = pool.Import(reader, func(tx *txpool.HandoverTx, err error) {
+		switch {
+		case err == nil:
+			result.Imported++
+			if tx.Pending {
+				pending = append(pending, tx.Tx.Hash())
+			}
+		case errors.Is(err, txpool.ErrAlreadyKnown):
+			result.Known++
+		default:
+			result.Rejected = append(result.Rejected, TxPoolImportRejected{
+				Hash:        tx.Tx.Hash(),
+				Pending:     tx.Pending,
+				FeeCurrency: tx.Tx.FeeCurrency(),
+				Error:       err.Error(),
+			})
+		}
+	})
+	for _, hash := range pending {
+		if pool.Status(hash) == txpool.TxStatusQueued {
+			result.Demoted = append(result.Demoted, hash)
+		}
+	}
+	// A failure partway leaves the transactions read so far in the pool, so
+	// report them along with the error.
+	if err != nil {
+		log.Warn("Imported transaction pool partially", "imported", result.Imported, "known", result.Known, "rejected", len(result.Rejected), "demoted", len(result.Demoted), "err", err)
+		return result, err
+	}
+	log.Info("Imported transaction pool", "imported", result.Imported, "known", result.Known, "rejected", len(result.Rejected), "demoted", len(result.Demoted))
+	return result, nil
+}
@@ -26,7 +26,6 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core"
@@ -40,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/telemetry"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -120,6 +120,9 @@ func (b *EthAPIBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*ty
 }
 
 func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	_, span := telemetry.Start(ctx, "db.blockByNumber", telemetry.Int64("number", number.Int64()))
+	defer span.End()
+
 	// Pending block is only known by the miner
 	if number == rpc.PendingBlockNumber {
 		block, _, _ := b.eth.miner.Pending()
@@ -151,6 +154,9 @@ func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumbe
 }
 
 func (b *EthAPIBackend) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	_, span := telemetry.Start(ctx, "db.blockByHash", telemetry.String("hash", hash.Hex()))
+	defer span.End()
+
 	return b.eth.blockchain.GetBlockByHash(hash), nil
 }
 
@@ -191,6 +197,9 @@ func (b *EthAPIBackend) Pending() (*types.Block, types.Receipts, *state.StateDB)
 }
 
 func (b *EthAPIBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	ctx, span := telemetry.Start(ctx, "state.byNumber", telemetry.Int64("number", number.Int64()))
+	defer span.End()
+
 	// Pending state is only known by the miner
 	if number == rpc.PendingBlockNumber {
 		block, _, state := b.eth.miner.Pending()
@@ -216,6 +225,9 @@ func (b *EthAPIBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.B
 }
 
 func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	ctx, span := telemetry.Start(ctx, "state.byNumberOrHash", telemetry.String("block", blockNrOrHash.String()))
+	defer span.End()
+
 	if blockNr, ok := blockNrOrHash.Number(); ok {
 		return b.StateAndHeaderByNumber(ctx, blockNr)
 	}
@@ -240,10 +252,16 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 }
 
 func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	_, span := telemetry.Start(ctx, "db.receipts", telemetry.String("hash", hash.Hex()))
+	defer span.End()
+
 	return b.eth.blockchain.GetReceiptsByHash(hash), nil
 }
 
 func (b *EthAPIBackend) GetLogs(ctx context.Context, hash common.Hash, number uint64) ([][]*types.Log, error) {
+	_, span := telemetry.Start(ctx, "db.logs", telemetry.Uint64("number", number))
+	defer span.End()
+
 	return rawdb.ReadLogs(b.eth.chainDb, hash, number), nil
 }
 
@@ -293,12 +311,8 @@ func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	if b.ChainConfig().IsOptimism() && signedTx.Type() == types.BlobTxType {
 		return types.ErrTxTypeNotSupported
 	}
-	if b.eth.seqRPCService != nil {
-		data, err := signedTx.MarshalBinary()
-		if err != nil {
-			return err
-		}
-		if err := b.eth.seqRPCService.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(data)); err != nil {
+	if b.eth.seqForwarder != nil {
+		if err := b.eth.seqForwarder.Forward(ctx, signedTx); err != nil {
 			return err
 		}
 		if b.disableTxPool {
@@ -344,6 +358,9 @@ func (b *EthAPIBackend) GetPoolTransaction(hash common.Hash) *types.Transaction
 // indexing is already finished. The transaction is not existent from the perspective
 // of node.
 func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash) (bool, *types.Transaction, common.Hash, uint64, uint64, error) {
+	_, span := telemetry.Start(ctx, "db.transaction", telemetry.String("hash", txHash.Hex()))
+	defer span.End()
+
 	lookup, tx, err := b.eth.blockchain.GetTransactionLookup(txHash)
 	if err != nil {
 		return false, nil, common.Hash{}, 0, 0, err
@@ -378,6 +395,14 @@ func (b *EthAPIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.S
 	return b.eth.txPool.SubscribeTransactions(ch, true)
 }
 
+func (b *EthAPIBackend) TxPoolHistory(hash common.Hash) []txpool.TxLifecycleEvent {
+	return b.eth.txPool.TxHistory(hash)
+}
+
+func (b *EthAPIBackend) SubscribeTxLifecycleEvent(ch chan<- txpool.TxLifecycleEvent) event.Subscription {
+	return b.eth.txPool.SubscribeTxLifecycle(ch)
+}
+
 func (b *EthAPIBackend) SyncProgress() ethereum.SyncProgress {
 	prog := b.eth.Downloader().Progress()
 	if txProg, err := b.eth.blockchain.TxIndexProgress(); err == nil {
@@ -439,6 +464,14 @@ func (b *EthAPIBackend) BloomStatus() (uint64, uint64) {
 	return params.BloomBitsBlocks, sections
 }
 
+func (b *EthAPIBackend) LogIndexStatus() (uint64, uint64) {
+	if b.eth.logIndexer == nil {
+		return params.BloomBitsBlocks, 0
+	}
+	sections, _, _ := b.eth.logIndexer.Sections()
+	return params.BloomBitsBlocks, sections
+}
+
 func (b *EthAPIBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
 	for i := 0; i < bloomFilterThreads; i++ {
 		go session.Multiplex(bloomRetrievalBatch, bloomRetrievalWait, b.eth.bloomRequests)
@@ -454,11 +487,21 @@ func (b *EthAPIBackend) CurrentHeader() *types.Header {
 }
 
 func (b *EthAPIBackend) StateAtBlock(ctx context.Context, block *types.Block, reexec uint64, base *state.StateDB, readOnly bool, preferDisk bool) (*state.StateDB, tracers.StateReleaseFunc, error) {
-	return b.eth.stateAtBlock(ctx, block, reexec, base, readOnly, preferDisk)
+	ctx, span := telemetry.Start(ctx, "state.atBlock", telemetry.Uint64("number", block.NumberU64()))
+	defer span.End()
+
+	statedb, release, err := b.eth.stateAtBlock(ctx, block, reexec, base, readOnly, preferDisk)
+	span.SetError(err)
+	return statedb, release, err
 }
 
 func (b *EthAPIBackend) StateAtTransaction(ctx context.Context, block *types.Block, txIndex int, reexec uint64) (*types.Transaction, vm.BlockContext, *state.StateDB, tracers.StateReleaseFunc, error) {
-	return b.eth.stateAtTransaction(ctx, block, txIndex, reexec)
+	ctx, span := telemetry.Start(ctx, "state.atTransaction", telemetry.Uint64("number", block.NumberU64()), telemetry.Int64("index", int64(txIndex)))
+	defer span.End()
+
+	tx, blockCtx, statedb, release, err := b.eth.stateAtTransaction(ctx, block, txIndex, reexec)
+	span.SetError(err)
+	return tx, blockCtx, statedb, release, err
 }
 
 func (b *EthAPIBackend) HistoricalRPCService() *rpc.Client {
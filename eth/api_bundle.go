@@ -0,0 +1,167 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BundleAPI provides an API to submit transaction bundles to the block builder.
+type BundleAPI struct {
+	e *Ethereum
+}
+
+// NewBundleAPI creates a new BundleAPI instance.
+func NewBundleAPI(e *Ethereum) *BundleAPI {
+	return &BundleAPI{e}
+}
+
+// SendBundleArgs are the arguments of eth_sendBundle.
+type SendBundleArgs struct {
+	Txs               []hexutil.Bytes `json:"txs"`
+	BlockNumber       *hexutil.Uint64 `json:"blockNumber"`
+	MinTimestamp      *hexutil.Uint64 `json:"minTimestamp"`
+	MaxTimestamp      *hexutil.Uint64 `json:"maxTimestamp"`
+	RevertingTxHashes []common.Hash   `json:"revertingTxHashes"`
+}
+
+// CallBundleArgs are the arguments of eth_callBundle.
+type CallBundleArgs struct {
+	Txs               []hexutil.Bytes       `json:"txs"`
+	StateBlockNumber  rpc.BlockNumberOrHash `json:"stateBlockNumber"`
+	Timestamp         *hexutil.Uint64       `json:"timestamp"`
+	RevertingTxHashes []common.Hash         `json:"revertingTxHashes"`
+}
+
+// CallBundleResult is the outcome of simulating a bundle.
+type CallBundleResult struct {
+	BundleHash  common.Hash           `json:"bundleHash"`
+	BlockNumber hexutil.Uint64        `json:"blockNumber"`
+	Timestamp   hexutil.Uint64        `json:"timestamp"`
+	GasUsed     hexutil.Uint64        `json:"gasUsed"`
+	Error       string                `json:"error,omitempty"`
+	Results     []*CallBundleTxResult `json:"results"`
+}
+
+// CallBundleTxResult is the outcome of simulating a bundle transaction.
+type CallBundleTxResult struct {
+	TxHash   common.Hash    `json:"txHash"`
+	GasUsed  hexutil.Uint64 `json:"gasUsed"`
+	Reverted bool           `json:"reverted"`
+	Error    string         `json:"error,omitempty"`
+	Logs     []*types.Log   `json:"logs"`
+}
+
+// decodeBundleTxs decodes the raw transactions of a bundle.
+func decodeBundleTxs(raw []hexutil.Bytes) (types.Transactions, error) {
+	txs := make(types.Transactions, 0, len(raw))
+	for i, input := range raw {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(input); err != nil {
+			return nil, fmt.Errorf("tx %d: %w", i, err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// SendBundle submits a bundle of transactions, to be included atomically and
+// contiguously in the target block, or not at all. If no target block is given,
+// the bundle targets the next block. It returns the bundle hash.
+func (api *BundleAPI) SendBundle(args SendBundleArgs) (common.Hash, error) {
+	txs, err := decodeBundleTxs(args.Txs)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	bundle := &miner.Bundle{
+		Txs:               txs,
+		RevertingTxHashes: args.RevertingTxHashes,
+	}
+	if args.BlockNumber != nil {
+		bundle.BlockNumber = uint64(*args.BlockNumber)
+	} else {
+		bundle.BlockNumber = api.e.BlockChain().CurrentBlock().Number.Uint64() + 1
+	}
+	if args.MinTimestamp != nil {
+		bundle.MinTimestamp = uint64(*args.MinTimestamp)
+	}
+	if args.MaxTimestamp != nil {
+		bundle.MaxTimestamp = uint64(*args.MaxTimestamp)
+	}
+	return api.e.Miner().AddBundle(bundle)
+}
+
+// CallBundle simulates a bundle on top of the given state, as the first
+// transactions of the following block. If no timestamp is given, the block is
+// timestamped one second after the state block.
+func (api *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (*CallBundleResult, error) {
+	txs, err := decodeBundleTxs(args.Txs)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := api.e.APIBackend.HeaderByNumberOrHash(ctx, args.StateBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, fmt.Errorf("state block %s not found", args.StateBlockNumber.String())
+	}
+	timestamp := parent.Time + 1
+	if args.Timestamp != nil {
+		timestamp = uint64(*args.Timestamp)
+	}
+	bundle := &miner.Bundle{Txs: txs, RevertingTxHashes: args.RevertingTxHashes}
+	results, header, err := api.e.Miner().SimulateBundle(bundle, parent.Hash(), timestamp)
+	if header == nil {
+		return nil, err
+	}
+	result := &CallBundleResult{
+		BundleHash:  bundle.Hash(),
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		Timestamp:   hexutil.Uint64(header.Time),
+		Results:     make([]*CallBundleTxResult, 0, len(results)),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	for _, res := range results {
+		txResult := &CallBundleTxResult{
+			TxHash:  res.Hash,
+			GasUsed: hexutil.Uint64(res.GasUsed),
+			Logs:    []*types.Log{},
+		}
+		if res.Err != nil {
+			txResult.Error = res.Err.Error()
+		}
+		if res.Receipt != nil {
+			txResult.Reverted = res.Receipt.Status == types.ReceiptStatusFailed
+			if res.Receipt.Logs != nil {
+				txResult.Logs = res.Receipt.Logs
+			}
+		}
+		result.GasUsed += txResult.GasUsed
+		result.Results = append(result.Results, txResult)
+	}
+	return result, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/time/rate"
+)
+
+const (
+	conditionalRejectedCode      = -32003 // Conditional can't hold or the tx was rejected
+	conditionalLimitExceededCode = -32005 // Conditional cost rate limit exceeded
+)
+
+var (
+	errConditionalExpired   = errors.New("conditional can no longer be met")
+	errConditionalForwarded = errors.New("conditional transactions are only accepted by the sequencer")
+
+	txConditionalCostMeter = metrics.NewRegisteredMeter("eth/transactionConditional/cost", nil)
+)
+
+// conditionalTxError is an error of eth_sendRawTransactionConditional with a
+// JSON-RPC error code.
+type conditionalTxError struct {
+	code int
+	err  error
+}
+
+func (e *conditionalTxError) Error() string  { return e.err.Error() }
+func (e *conditionalTxError) ErrorCode() int { return e.code }
+func (e *conditionalTxError) Unwrap() error  { return e.err }
+
+// ConditionalTxAPI provides an API to submit transactions which may only be
+// included in a block if the given conditions hold.
+type ConditionalTxAPI struct {
+	e       *Ethereum
+	limiter *rate.Limiter
+}
+
+// NewConditionalTxAPI creates a new ConditionalTxAPI instance, accepting
+// conditionals up to the given total cost per second, or any if zero.
+func NewConditionalTxAPI(e *Ethereum, costRateLimit int) *ConditionalTxAPI {
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	if costRateLimit > 0 {
+		limiter = rate.NewLimiter(rate.Limit(costRateLimit), costRateLimit)
+	}
+	return &ConditionalTxAPI{e: e, limiter: limiter}
+}
+
+// SendRawTransactionConditional adds the signed transaction to the pool, to be
+// included only in a block for which the conditional holds. The conditional is
+// checked against the current head on admission, and again against the block
+// being built before inclusion. Transactions whose conditional can no longer
+// hold are evicted. Conditional transactions are not journaled, so they don't
+// survive a restart.
+func (api *ConditionalTxAPI) SendRawTransactionConditional(ctx context.Context, input hexutil.Bytes, cond types.TransactionConditional) (common.Hash, error) {
+	if api.e.seqForwarder != nil {
+		return common.Hash{}, &conditionalTxError{conditionalRejectedCode, errConditionalForwarded}
+	}
+	if err := cond.Validate(); err != nil {
+		return common.Hash{}, &conditionalTxError{conditionalRejectedCode, err}
+	}
+	cost := cond.Cost()
+	if !api.limiter.AllowN(time.Now(), cost) {
+		return common.Hash{}, &conditionalTxError{conditionalLimitExceededCode, fmt.Errorf("conditional cost %d exceeds rate limit", cost)}
+	}
+	txConditionalCostMeter.Mark(int64(cost))
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	// Reject conditionals which can't hold on top of the current head
+	state, head, err := api.e.APIBackend.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if cond.Expired(head) {
+		return common.Hash{}, &conditionalTxError{conditionalRejectedCode, errConditionalExpired}
+	}
+	if err := state.CheckTransactionConditional(&cond); err != nil {
+		return common.Hash{}, &conditionalTxError{conditionalRejectedCode, err}
+	}
+	tx.SetConditional(&cond)
+	if err := api.e.APIBackend.SendTx(ctx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
@@ -47,6 +47,11 @@ func NewDebugAPI(eth *Ethereum) *DebugAPI {
 }
 
 // DumpBlock retrieves the entire state of the database at a given block.
+//
+// Unlike the block tracing methods, the dump is not streamed: it is a single JSON
+// object rather than an array, and it is capped at AccountRangeMaxResults accounts.
+// The storage of those accounts is included in full, so callers of large contracts
+// should page through AccountRange and StorageRangeAt instead.
 func (api *DebugAPI) DumpBlock(blockNr rpc.BlockNumber) (state.Dump, error) {
 	opts := &state.DumpConfig{
 		OnlyWithAddresses: true,
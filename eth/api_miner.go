@@ -17,9 +17,12 @@
 package eth
 
 import (
+	"fmt"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/miner"
 )
 
 // MinerAPI provides an API to control the miner.
@@ -56,3 +59,14 @@ func (api *MinerAPI) SetGasLimit(gasLimit hexutil.Uint64) bool {
 	api.e.Miner().SetGasCeil(uint64(gasLimit))
 	return true
 }
+
+// BuildReport returns how the payload with the given id was built: the
+// transactions considered, included and skipped along with the reason, the gas
+// used per fee currency and the duration of each build phase.
+func (api *MinerAPI) BuildReport(id engine.PayloadID) (*miner.BuildReport, error) {
+	report := api.e.Miner().BuildReport(id)
+	if report == nil {
+		return nil, fmt.Errorf("no build report for payload %s", id)
+	}
+	return report, nil
+}
@@ -77,7 +77,7 @@ type Ethereum struct {
 	ethDialCandidates  enode.Iterator
 	snapDialCandidates enode.Iterator
 
-	seqRPCService        *rpc.Client
+	seqForwarder         *sequencerForwarder
 	historicalRPCService *rpc.Client
 
 	// DB interfaces
@@ -90,6 +90,7 @@ type Ethereum struct {
 	bloomRequests     chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer      *core.ChainIndexer             // Bloom indexer operating during block imports
 	closeBloomHandler chan struct{}
+	logIndexer        *core.ChainIndexer // Log indexer operating during block imports, nil if disabled
 
 	APIBackend *EthAPIBackend
 
@@ -263,6 +264,10 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	log.Info("Initialising Ethereum protocol", "network", config.NetworkId, "dbversion", dbVer)
 
 	eth.bloomIndexer.Start(eth.blockchain)
+	if config.LogIndex {
+		eth.logIndexer = core.NewLogIndexer(chainDb, params.BloomBitsBlocks, params.BloomConfirms)
+		eth.logIndexer.Start(eth.blockchain)
+	}
 
 	if config.BlobPool.Datadir != "" {
 		config.BlobPool.Datadir = stack.ResolvePath(config.BlobPool.Datadir)
@@ -273,6 +278,15 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 	legacyPool := legacypool.New(config.TxPool, eth.blockchain)
 
+	// Load the admission policies before the pool, to screen journaled transactions
+	var admission *txpool.Admission
+	if config.TxPool.Admission != "" {
+		admission, err = txpool.NewAdmission(stack.ResolvePath(config.TxPool.Admission), types.LatestSigner(eth.blockchain.Config()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load txpool admission policies: %w", err)
+		}
+		legacyPool.SetAdmission(admission)
+	}
 	txPools := []txpool.SubPool{legacyPool}
 	if !eth.BlockChain().Config().IsOptimism() {
 		blobPool := blobpool.New(config.BlobPool, eth.blockchain)
@@ -280,8 +294,12 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 	eth.txPool, err = txpool.New(config.TxPool.PriceLimit, eth.blockchain, txPools)
 	if err != nil {
+		admission.Close()
 		return nil, err
 	}
+	if admission != nil {
+		eth.txPool.SetAdmission(admission)
+	}
 	// Permit the downloader to use the trie cache allowance during fast sync
 	cacheLimit := cacheConfig.TrieCleanLimit + cacheConfig.TrieDirtyLimit + cacheConfig.SnapshotLimit
 	if eth.handler, err = newHandler(&handlerConfig{
@@ -321,12 +339,12 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 
 	if config.RollupSequencerHTTP != "" {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		client, err := rpc.DialContext(ctx, config.RollupSequencerHTTP)
+		clients, err := dialSequencers(ctx, config.RollupSequencerHTTP)
 		cancel()
 		if err != nil {
 			return nil, err
 		}
-		eth.seqRPCService = client
+		eth.seqForwarder = newSequencerForwarder(clients, config.RollupSequencerRetries, config.RollupSequencerOutboxTTL)
 	}
 
 	if config.RollupHistoricalRPC != "" {
@@ -342,6 +360,13 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	// Start the RPC service
 	eth.netRPCService = ethapi.NewNetAPI(eth.p2pServer, networkID)
 
+	// Only cache RPC results of canonical blocks at or below the finalized one
+	if cache := stack.ResponseCache(); cache != nil {
+		cache.SetFinalityFunc(func(hash common.Hash, number uint64) bool {
+			final := eth.blockchain.CurrentFinalBlock()
+			return final != nil && number <= final.Number.Uint64() && eth.blockchain.GetCanonicalHash(number) == hash
+		})
+	}
 	// Register the backend on the node
 	stack.RegisterAPIs(eth.APIs())
 	stack.RegisterProtocols(eth.Protocols())
@@ -379,6 +404,20 @@ func (s *Ethereum) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
+	// Conditional transactions are only accepted when enabled on the sequencer
+	if s.config.RollupSequencerTxConditionalEnabled {
+		apis = append(apis, rpc.API{
+			Namespace: "eth",
+			Service:   NewConditionalTxAPI(s, s.config.RollupSequencerTxConditionalCostRateLimit),
+		})
+	}
+	// Bundles are only accepted when enabled on the sequencer
+	if s.config.RollupSequencerBundlesEnabled {
+		apis = append(apis, rpc.API{
+			Namespace: "eth",
+			Service:   NewBundleAPI(s),
+		})
+	}
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -403,6 +442,9 @@ func (s *Ethereum) APIs() []rpc.API {
 		{
 			Namespace: "eth",
 			Service:   celoapi.NewCeloAPI(s, celoBackend),
+		}, {
+			Namespace: "celo",
+			Service:   celoapi.NewFeeCurrencyAPI(s),
 		},
 	}...)
 }
@@ -470,12 +512,15 @@ func (s *Ethereum) Stop() error {
 
 	// Then stop everything else.
 	s.bloomIndexer.Close()
+	if s.logIndexer != nil {
+		s.logIndexer.Close()
+	}
 	close(s.closeBloomHandler)
 	s.txPool.Close()
 	s.blockchain.Stop()
 	s.engine.Close()
-	if s.seqRPCService != nil {
-		s.seqRPCService.Close()
+	if s.seqForwarder != nil {
+		s.seqForwarder.Close()
 	}
 	if s.historicalRPCService != nil {
 		s.historicalRPCService.Close()
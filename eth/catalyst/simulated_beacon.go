@@ -20,6 +20,7 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"errors"
+	"fmt"
 	"math/big"
 	"sync"
 	"time"
@@ -38,6 +39,10 @@ import (
 
 const devEpochLength = 32
 
+// maxPendingDeposits is the maximum number of deposit transactions queued for
+// inclusion in the next block.
+const maxPendingDeposits = 64
+
 // withdrawalQueue implements a FIFO queue which holds withdrawals that are
 // pending inclusion.
 type withdrawalQueue struct {
@@ -71,11 +76,59 @@ func (w *withdrawalQueue) gatherPending(maxCount int) []*types.Withdrawal {
 	}
 }
 
+// depositQueue implements a FIFO queue which holds deposit transactions that
+// are pending inclusion at the start of the next block.
+type depositQueue struct {
+	txs   types.Transactions
+	lock  sync.Mutex
+	added chan struct{} // Notification of newly queued deposits
+}
+
+// add queues a deposit transaction for inclusion in the next block.
+func (q *depositQueue) add(tx *types.Transaction) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if len(q.txs) >= maxPendingDeposits {
+		return errors.New("deposit queue full")
+	}
+	q.txs = append(q.txs, tx)
+
+	select {
+	case q.added <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// gatherPending returns all queued deposit transactions, emptying the queue.
+func (q *depositQueue) gatherPending() types.Transactions {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	txs := q.txs
+	q.txs = nil
+	return txs
+}
+
+// requeue returns deposit transactions which could not be sealed to the front
+// of the queue.
+func (q *depositQueue) requeue(txs types.Transactions) {
+	if len(txs) == 0 {
+		return
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.txs = append(txs[:len(txs):len(txs)], q.txs...)
+}
+
 type SimulatedBeacon struct {
 	shutdownCh  chan struct{}
 	eth         *eth.Ethereum
 	period      uint64
 	withdrawals withdrawalQueue
+	deposits    depositQueue
 
 	feeRecipient     common.Address
 	feeRecipientLock sync.Mutex // lock gates concurrent access to the feeRecipient
@@ -83,6 +136,18 @@ type SimulatedBeacon struct {
 	engineAPI          *ConsensusAPI
 	curForkchoiceState engine.ForkchoiceStateV1
 	lastBlockTime      uint64
+
+	nextBlockTime uint64          // Exact timestamp of the next block, if non-zero
+	snapshots     []chainSnapshot // Chain snapshots which can be reverted to, oldest first
+	nextSnapshot  uint64          // Id of the next chain snapshot
+	lock          sync.Mutex      // Lock serializing block sealing and chain snapshots
+}
+
+// chainSnapshot is a chain head which can be reverted to.
+type chainSnapshot struct {
+	id     uint64
+	hash   common.Hash
+	number uint64
 }
 
 // NewSimulatedBeacon constructs a new simulated beacon chain.
@@ -113,6 +178,7 @@ func NewSimulatedBeacon(period uint64, eth *eth.Ethereum) (*SimulatedBeacon, err
 		lastBlockTime:      block.Time,
 		curForkchoiceState: current,
 		withdrawals:        withdrawalQueue{make(chan *types.Withdrawal, 20)},
+		deposits:           depositQueue{added: make(chan struct{}, 1)},
 	}, nil
 }
 
@@ -143,6 +209,30 @@ func (c *SimulatedBeacon) Stop() error {
 // sealBlock initiates payload building for a new block and creates a new block
 // with the completed payload.
 func (c *SimulatedBeacon) sealBlock(withdrawals []*types.Withdrawal, timestamp uint64) error {
+	return c.sealBlockWithTxs(withdrawals, timestamp, nil, false)
+}
+
+// sealBlockWithTxs seals a block starting with the queued deposits followed by
+// the given transactions, and only those if noTxPool is set. If the block can't
+// be sealed, the deposits are queued again.
+func (c *SimulatedBeacon) sealBlockWithTxs(withdrawals []*types.Withdrawal, timestamp uint64, txs types.Transactions, noTxPool bool) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	deposits := c.deposits.gatherPending()
+	if len(deposits) > 0 {
+		txs = append(deposits, txs...)
+	}
+	var inserted bool
+	defer func() {
+		if !inserted {
+			c.deposits.requeue(deposits)
+		}
+	}()
+
+	if c.nextBlockTime != 0 {
+		timestamp, c.nextBlockTime = c.nextBlockTime, 0
+	}
 	if timestamp <= c.lastBlockTime {
 		timestamp = c.lastBlockTime + 1
 	}
@@ -158,12 +248,22 @@ func (c *SimulatedBeacon) sealBlock(withdrawals []*types.Withdrawal, timestamp u
 
 	var random [32]byte
 	rand.Read(random[:])
+	rawTxs := make([][]byte, len(txs))
+	for i, tx := range txs {
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		rawTxs[i] = raw
+	}
 	fcResponse, err := c.engineAPI.forkchoiceUpdated(c.curForkchoiceState, &engine.PayloadAttributes{
 		Timestamp:             timestamp,
 		SuggestedFeeRecipient: feeRecipient,
 		Withdrawals:           withdrawals,
 		Random:                random,
 		BeaconRoot:            &common.Hash{},
+		Transactions:          rawTxs,
+		NoTxPool:              noTxPool,
 	}, engine.PayloadV3, true)
 	if err != nil {
 		return err
@@ -209,6 +309,7 @@ func (c *SimulatedBeacon) sealBlock(withdrawals []*types.Withdrawal, timestamp u
 	if _, err = c.engineAPI.NewPayloadV3(*payload, blobHashes, &common.Hash{}); err != nil {
 		return err
 	}
+	inserted = true
 	c.setCurrentState(payload.BlockHash, finalizedHash)
 
 	// Mark the block containing the payload as canonical
@@ -309,6 +410,101 @@ func (c *SimulatedBeacon) AdjustTime(adjustment time.Duration) error {
 	return c.sealBlock(withdrawals, parent.Time+uint64(adjustment/time.Second))
 }
 
+// AddDeposit queues a deposit transaction for inclusion at the start of the
+// next sealed block.
+func (c *SimulatedBeacon) AddDeposit(tx *types.Transaction) error {
+	if !tx.IsDepositTx() {
+		return errors.New("not a deposit transaction")
+	}
+	return c.deposits.add(tx)
+}
+
+// CommitEmpty seals the given number of blocks without any transactions from
+// the pool. Queued deposits are still included in the first block.
+func (c *SimulatedBeacon) CommitEmpty(blocks uint64) error {
+	for i := uint64(0); i < blocks; i++ {
+		withdrawals := c.withdrawals.gatherPending(10)
+		if err := c.sealBlockWithTxs(withdrawals, uint64(time.Now().Unix()), nil, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CommitTransactions seals a block including exactly the given transactions in
+// the given order after any queued deposits, ignoring the transaction pool. If
+// any transaction can't be included, no block is sealed.
+func (c *SimulatedBeacon) CommitTransactions(txs types.Transactions) (common.Hash, error) {
+	withdrawals := c.withdrawals.gatherPending(10)
+	if err := c.sealBlockWithTxs(withdrawals, uint64(time.Now().Unix()), txs, true); err != nil {
+		return common.Hash{}, err
+	}
+	return c.eth.BlockChain().CurrentBlock().Hash(), nil
+}
+
+// SetNextBlockTime sets the exact timestamp of the next sealed block, which must
+// be after the current head.
+func (c *SimulatedBeacon) SetNextBlockTime(timestamp uint64) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if head := c.eth.BlockChain().CurrentBlock(); timestamp <= head.Time {
+		return fmt.Errorf("timestamp %d not after head timestamp %d", timestamp, head.Time)
+	}
+	c.nextBlockTime = timestamp
+	return nil
+}
+
+// Snapshot records the current chain head, returning an id to revert to it.
+func (c *SimulatedBeacon) Snapshot() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	head := c.eth.BlockChain().CurrentBlock()
+	id := c.nextSnapshot
+	c.nextSnapshot++
+	c.snapshots = append(c.snapshots, chainSnapshot{id: id, hash: head.Hash(), number: head.Number.Uint64()})
+	return id
+}
+
+// Revert rewinds the chain to the head recorded by the snapshot with the given
+// id and drops all pending transactions. The snapshot and all later ones are
+// discarded.
+func (c *SimulatedBeacon) Revert(id uint64) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	index := -1
+	for i, snap := range c.snapshots {
+		if snap.id == id {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("unknown snapshot %d", id)
+	}
+	snap := c.snapshots[index]
+	c.snapshots = c.snapshots[:index]
+
+	chain := c.eth.BlockChain()
+	if chain.GetCanonicalHash(snap.number) != snap.hash {
+		return fmt.Errorf("snapshot %d no longer canonical", id)
+	}
+	if err := chain.SetHead(snap.number); err != nil {
+		return err
+	}
+	head := chain.CurrentBlock()
+	c.setCurrentState(head.Hash(), *c.finalizedBlockHash(head.Number.Uint64()))
+	c.lastBlockTime = head.Time
+	c.nextBlockTime = 0
+
+	// Drop the pending transactions, including those of the reverted blocks
+	c.eth.TxPool().Sync()
+	c.Rollback()
+	return nil
+}
+
 func RegisterSimulatedBeaconAPIs(stack *node.Node, sim *SimulatedBeacon) {
 	api := &api{sim}
 	if sim.period == 0 {
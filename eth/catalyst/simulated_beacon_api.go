@@ -18,14 +18,59 @@ package catalyst
 
 import (
 	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 )
 
+// defaultDepositGas is the gas limit of deposits which don't specify one.
+const defaultDepositGas = 1_000_000
+
+// DepositArgs are the arguments of a deposit transaction to be included in the
+// next block, as if it was derived from L1.
+type DepositArgs struct {
+	SourceHash *common.Hash    `json:"sourceHash"` // Random if unset
+	From       common.Address  `json:"from"`
+	To         *common.Address `json:"to"` // Contract creation if unset
+	Mint       *hexutil.Big    `json:"mint"`
+	Value      *hexutil.Big    `json:"value"`
+	Gas        *hexutil.Uint64 `json:"gas"`
+	IsSystemTx bool            `json:"isSystemTx"`
+	Input      hexutil.Bytes   `json:"input"`
+}
+
+// ToTransaction converts the arguments to a deposit transaction.
+func (args *DepositArgs) ToTransaction() *types.Transaction {
+	deposit := &types.DepositTx{
+		From:                args.From,
+		To:                  args.To,
+		Mint:                (*big.Int)(args.Mint),
+		Value:               new(big.Int),
+		Gas:                 defaultDepositGas,
+		IsSystemTransaction: args.IsSystemTx,
+		Data:                args.Input,
+	}
+	if args.SourceHash != nil {
+		deposit.SourceHash = *args.SourceHash
+	} else {
+		rand.Read(deposit.SourceHash[:])
+	}
+	if args.Value != nil {
+		deposit.Value = (*big.Int)(args.Value)
+	}
+	if args.Gas != nil {
+		deposit.Gas = uint64(*args.Gas)
+	}
+	return types.NewTx(deposit)
+}
+
 type api struct {
 	sim *SimulatedBeacon
 }
@@ -48,6 +93,8 @@ func (a *api) loop() {
 			}
 		case <-newTxs:
 			a.sim.Commit()
+		case <-a.sim.deposits.added:
+			a.sim.Commit()
 		}
 	}
 }
@@ -56,6 +103,53 @@ func (a *api) AddWithdrawal(ctx context.Context, withdrawal *types.Withdrawal) e
 	return a.sim.withdrawals.add(withdrawal)
 }
 
+// AddDeposit queues a deposit transaction for inclusion at the start of the
+// next block, returning its hash.
+func (a *api) AddDeposit(ctx context.Context, args DepositArgs) (common.Hash, error) {
+	tx := args.ToTransaction()
+	if err := a.sim.AddDeposit(tx); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
 func (a *api) SetFeeRecipient(ctx context.Context, feeRecipient common.Address) {
 	a.sim.setFeeRecipient(feeRecipient)
 }
+
+// CommitEmpty seals the given number of blocks without any transactions,
+// returning the hash of the new head.
+func (a *api) CommitEmpty(ctx context.Context, blocks hexutil.Uint64) (common.Hash, error) {
+	if err := a.sim.CommitEmpty(uint64(blocks)); err != nil {
+		return common.Hash{}, err
+	}
+	return a.sim.eth.BlockChain().CurrentBlock().Hash(), nil
+}
+
+// CommitTransactions seals a block including exactly the given signed
+// transactions in the given order, ignoring the transaction pool.
+func (a *api) CommitTransactions(ctx context.Context, rawTxs []hexutil.Bytes) (common.Hash, error) {
+	txs := make(types.Transactions, len(rawTxs))
+	for i, raw := range rawTxs {
+		txs[i] = new(types.Transaction)
+		if err := txs[i].UnmarshalBinary(raw); err != nil {
+			return common.Hash{}, fmt.Errorf("transaction %d is not valid: %v", i, err)
+		}
+	}
+	return a.sim.CommitTransactions(txs)
+}
+
+// SetNextBlockTimestamp sets the exact timestamp of the next sealed block.
+func (a *api) SetNextBlockTimestamp(ctx context.Context, timestamp hexutil.Uint64) error {
+	return a.sim.SetNextBlockTime(uint64(timestamp))
+}
+
+// Snapshot records the current chain head, returning an id to revert to it.
+func (a *api) Snapshot(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(a.sim.Snapshot())
+}
+
+// Revert rewinds the chain to the snapshot with the given id.
+func (a *api) Revert(ctx context.Context, id hexutil.Uint64) error {
+	return a.sim.Revert(uint64(id))
+}
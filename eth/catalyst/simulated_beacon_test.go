@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -35,7 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 )
 
-func startSimulatedBeaconEthService(t *testing.T, genesis *core.Genesis) (*node.Node, *eth.Ethereum, *SimulatedBeacon) {
+func startSimulatedBeaconEthService(t *testing.T, genesis *core.Genesis, period uint64) (*node.Node, *eth.Ethereum, *SimulatedBeacon) {
 	t.Helper()
 
 	n, err := node.New(&node.Config{
@@ -55,7 +56,7 @@ func startSimulatedBeaconEthService(t *testing.T, genesis *core.Genesis) (*node.
 		t.Fatal("can't create eth service:", err)
 	}
 
-	simBeacon, err := NewSimulatedBeacon(1, ethservice)
+	simBeacon, err := NewSimulatedBeacon(period, ethservice)
 	if err != nil {
 		t.Fatal("can't create simulated beacon:", err)
 	}
@@ -87,7 +88,7 @@ func TestSimulatedBeaconSendWithdrawals(t *testing.T) {
 	// short period (1 second) for testing purposes
 	var gasLimit uint64 = 10_000_000
 	genesis := core.DeveloperGenesisBlock(gasLimit, &testAddr)
-	node, ethService, mock := startSimulatedBeaconEthService(t, genesis)
+	node, ethService, mock := startSimulatedBeaconEthService(t, genesis, 1)
 	_ = mock
 	defer node.Close()
 
@@ -140,3 +141,113 @@ func TestSimulatedBeaconSendWithdrawals(t *testing.T) {
 		}
 	}
 }
+
+// Tests that queued deposits are included at the start of the next block.
+func TestSimulatedBeaconDeposits(t *testing.T) {
+	var (
+		testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		testAddr   = crypto.PubkeyToAddress(testKey.PublicKey)
+		depositor  = common.HexToAddress("0xdeadbeef")
+		recipient  = common.HexToAddress("0xcafe")
+	)
+	genesis := core.DeveloperGenesisBlock(10_000_000, &testAddr)
+	node, ethService, mock := startSimulatedBeaconEthService(t, genesis, 1)
+	defer node.Close()
+
+	chainHeadCh := make(chan core.ChainHeadEvent, 10)
+	subscription := ethService.BlockChain().SubscribeChainHeadEvent(chainHeadCh)
+	defer subscription.Unsubscribe()
+
+	api := &api{mock}
+	hash, err := api.AddDeposit(context.Background(), DepositArgs{
+		From:  depositor,
+		To:    &recipient,
+		Mint:  (*hexutil.Big)(big.NewInt(params.Ether)),
+		Value: (*hexutil.Big)(big.NewInt(params.GWei)),
+	})
+	if err != nil {
+		t.Fatal("AddDeposit failed", err)
+	}
+	// Send a regular transaction which may only follow the deposit
+	signer := types.LatestSigner(ethService.BlockChain().Config())
+	tx := types.MustSignNewTx(testKey, signer, &types.DynamicFeeTx{
+		ChainID:   ethService.BlockChain().Config().ChainID,
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: big.NewInt(10 * params.GWei),
+		Gas:       params.TxGas,
+		To:        &recipient,
+	})
+	if err := ethService.APIBackend.SendTx(context.Background(), tx); err != nil {
+		t.Fatal("SendTx failed", err)
+	}
+	if err := mock.AddDeposit(tx); err == nil {
+		t.Fatal("regular transaction queued as deposit")
+	}
+	timer := time.NewTimer(12 * time.Second)
+	for {
+		select {
+		case evt := <-chainHeadCh:
+			txs := evt.Block.Transactions()
+			if len(txs) == 0 {
+				continue
+			}
+			if txs[0].Hash() != hash || !txs[0].IsDepositTx() {
+				t.Fatalf("deposit not first in block %d", evt.Block.NumberU64())
+			}
+			state, err := ethService.BlockChain().StateAt(evt.Block.Root())
+			if err != nil {
+				t.Fatal("state unavailable", err)
+			}
+			if have, want := state.GetBalance(depositor).ToBig(), big.NewInt(params.Ether-params.GWei); have.Cmp(want) != 0 {
+				t.Errorf("depositor balance mismatch: have %v, want %v", have, want)
+			}
+			if len(txs) > 1 && txs[1].Hash() != tx.Hash() {
+				t.Errorf("unexpected transaction after deposit: %v", txs[1].Hash())
+			}
+			return
+		case <-timer.C:
+			t.Fatal("timed out without including the deposit")
+		}
+	}
+}
+
+// Tests that queued deposits are retained if the block including them could
+// not be sealed.
+func TestSimulatedBeaconDepositsRequeued(t *testing.T) {
+	var (
+		testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		testAddr   = crypto.PubkeyToAddress(testKey.PublicKey)
+		recipient  = common.HexToAddress("0xcafe")
+	)
+	genesis := core.DeveloperGenesisBlock(10_000_000, &testAddr)
+	node, ethService, mock := startSimulatedBeaconEthService(t, genesis, 0)
+	defer node.Close()
+
+	api := &api{mock}
+	hash, err := api.AddDeposit(context.Background(), DepositArgs{
+		From: common.HexToAddress("0xdeadbeef"),
+		To:   &recipient,
+		Mint: (*hexutil.Big)(big.NewInt(params.Ether)),
+	})
+	if err != nil {
+		t.Fatal("AddDeposit failed", err)
+	}
+	// A transaction with a nonce gap fails sealing the block
+	signer := types.LatestSigner(ethService.BlockChain().Config())
+	gapped := types.MustSignNewTx(testKey, signer, &types.DynamicFeeTx{
+		ChainID:   ethService.BlockChain().Config().ChainID,
+		Nonce:     1,
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: big.NewInt(10 * params.GWei),
+		Gas:       params.TxGas,
+		To:        &recipient,
+	})
+	if _, err := mock.CommitTransactions(types.Transactions{gapped}); err == nil {
+		t.Fatal("block with nonce gap sealed")
+	}
+	// The deposit is included in the next sealed block instead
+	block := ethService.BlockChain().GetBlockByHash(mock.Commit())
+	if txs := block.Transactions(); len(txs) == 0 || txs[0].Hash() != hash {
+		t.Fatalf("deposit not included in block %d", block.NumberU64())
+	}
+}
@@ -68,6 +68,9 @@ var Defaults = Config{
 	RPCEVMTimeout:      5 * time.Second,
 	GPO:                FullNodeGPO,
 	RPCTxFeeCap:        1, // 1 ether
+
+	RollupSequencerRetries:                    2,
+	RollupSequencerTxConditionalCostRateLimit: 5000,
 }
 
 //go:generate go run github.com/fjl/gencodec -type Config -formats toml -out gen_config.go
@@ -129,6 +132,14 @@ type Config struct {
 	// This is the number of blocks for which logs will be cached in the filter system.
 	FilterLogCacheSize int
 
+	// PersistentFilters stores log and block filters in the database, so that they
+	// survive restarts of the node.
+	PersistentFilters bool `toml:",omitempty"`
+
+	// LogIndex maintains an index of logs by address and topic, speeding up log
+	// queries over long block ranges.
+	LogIndex bool `toml:",omitempty"`
+
 	// Mining options
 	Miner miner.Config
 
@@ -184,12 +195,17 @@ type Config struct {
 	// ApplySuperchainUpgrades requests the node to load chain-configuration from the superchain-registry.
 	ApplySuperchainUpgrades bool `toml:",omitempty"`
 
-	RollupSequencerHTTP                     string
-	RollupHistoricalRPC                     string
-	RollupHistoricalRPCTimeout              time.Duration
-	RollupDisableTxPoolGossip               bool
-	RollupDisableTxPoolAdmission            bool
-	RollupHaltOnIncompatibleProtocolVersion string
+	RollupSequencerHTTP                       string        // Comma separated sequencer endpoints, in order of preference
+	RollupSequencerRetries                    int           // Retries after transient sequencer errors
+	RollupSequencerOutboxTTL                  time.Duration // Lifetime of txs queued while the sequencer is unavailable, 0 disables queueing
+	RollupSequencerTxConditionalEnabled       bool          // Whether eth_sendRawTransactionConditional is served
+	RollupSequencerTxConditionalCostRateLimit int           // Conditional cost allowed per second across all conditional txs
+	RollupSequencerBundlesEnabled             bool          // Whether eth_sendBundle and eth_callBundle are served
+	RollupHistoricalRPC                       string
+	RollupHistoricalRPCTimeout                time.Duration
+	RollupDisableTxPoolGossip                 bool
+	RollupDisableTxPoolAdmission              bool
+	RollupHaltOnIncompatibleProtocolVersion   string
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain config.
@@ -18,6 +18,7 @@ package filters
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -28,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -39,6 +41,9 @@ var (
 	errInvalidBlockRange      = errors.New("invalid block range params")
 	errPendingLogsUnsupported = errors.New("pending logs are not supported")
 	errExceedMaxTopics        = errors.New("exceed max topics")
+	errInvalidPageSize        = fmt.Errorf("page size must be between 1 and %d", maxPageSize)
+	errInvalidCursor          = errors.New("invalid log cursor")
+	errCursorReorged          = errors.New("log cursor block is no longer canonical")
 )
 
 // The maximum number of topic criteria allowed, vm.LOG4 - vm.LOG0
@@ -47,6 +52,9 @@ const maxTopics = 4
 // The maximum number of allowed topics within a topic criteria
 const maxSubTopics = 1000
 
+// The maximum number of logs returned by a single eth_getLogsPage call
+const maxPageSize = 10000
+
 // filter is a helper struct that holds meta information over the filter type
 // and associated subscription in the event system.
 type filter struct {
@@ -63,26 +71,59 @@ type filter struct {
 // FilterAPI offers support to create and manage filters. This will allow external clients to retrieve various
 // information related to the Ethereum protocol such as blocks, transactions and logs.
 type FilterAPI struct {
-	sys       *FilterSystem
-	events    *EventSystem
-	filtersMu sync.Mutex
-	filters   map[rpc.ID]*filter
-	timeout   time.Duration
+	sys        *FilterSystem
+	events     *EventSystem
+	filtersMu  sync.Mutex
+	filters    map[rpc.ID]*filter
+	persistent map[rpc.ID]*persistentFilter
+	timeout    time.Duration
+	started    time.Time // startup time, from which persistent filters time out at the earliest
 }
 
 // NewFilterAPI returns a new FilterAPI instance.
 func NewFilterAPI(system *FilterSystem) *FilterAPI {
 	api := &FilterAPI{
-		sys:     system,
-		events:  NewEventSystem(system),
-		filters: make(map[rpc.ID]*filter),
-		timeout: system.cfg.Timeout,
+		sys:        system,
+		events:     NewEventSystem(system),
+		filters:    make(map[rpc.ID]*filter),
+		persistent: make(map[rpc.ID]*persistentFilter),
+		timeout:    system.cfg.Timeout,
+		started:    time.Now(),
+	}
+	if system.cfg.Persistent {
+		api.loadPersistentFilters()
 	}
 	go api.timeoutLoop(system.cfg.Timeout)
 
 	return api
 }
 
+// StreamingFilterAPI is the FilterAPI as served over RPC. It shadows GetLogs to
+// stream the matching logs to the caller as they are found, instead of collecting
+// them in memory first. Go callers keep using the typed methods of FilterAPI.
+type StreamingFilterAPI struct {
+	*FilterAPI
+}
+
+// NewStreamingFilterAPI returns a new StreamingFilterAPI instance.
+func NewStreamingFilterAPI(system *FilterSystem) *StreamingFilterAPI {
+	return &StreamingFilterAPI{NewFilterAPI(system)}
+}
+
+// GetLogs is the streamed version of FilterAPI.GetLogs.
+func (api *StreamingFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) (*rpc.Stream, error) {
+	filter, err := api.logsFilter(ctx, crit)
+	if err != nil {
+		return nil, err
+	}
+	// Run the filter while the logs are written
+	return rpc.NewStream(func(yield func(interface{}) error) error {
+		return filter.StreamLogs(ctx, func(log *types.Log) error {
+			return yield(log)
+		})
+	}), nil
+}
+
 // timeoutLoop runs at the interval set by 'timeout' and deletes filters
 // that have not been recently used. It is started when the API is created.
 func (api *FilterAPI) timeoutLoop(timeout time.Duration) {
@@ -101,6 +142,12 @@ func (api *FilterAPI) timeoutLoop(timeout time.Duration) {
 				continue
 			}
 		}
+		for id, f := range api.persistent {
+			if api.persistentFilterExpired(f, time.Now()) {
+				delete(api.persistent, id)
+				rawdb.DeleteFilter(api.sys.backend.ChainDb(), string(id))
+			}
+		}
 		api.filtersMu.Unlock()
 
 		// Unsubscribes are processed outside the lock to avoid the following scenario:
@@ -192,7 +239,16 @@ func (api *FilterAPI) NewPendingTransactions(ctx context.Context, fullTx *bool)
 
 // NewBlockFilter creates a filter that fetches blocks that are imported into the chain.
 // It is part of the filter package since polling goes with eth_getFilterChanges.
+//
+// If persistent filters are enabled, the filter is stored in the database and
+// survives restarts of the node, unless the maximum number of persistent filters
+// is reached.
 func (api *FilterAPI) NewBlockFilter() rpc.ID {
+	if api.sys.cfg.Persistent {
+		if id, ok := api.newPersistentFilter(&persistentFilter{Blocks: true}); ok {
+			return id
+		}
+	}
 	var (
 		headers   = make(chan *types.Header)
 		headerSub = api.events.SubscribeNewHeads(headers)
@@ -300,7 +356,20 @@ type FilterCriteria ethereum.FilterQuery
 // again but with the removed property set to true.
 //
 // In case "fromBlock" > "toBlock" an error is returned.
+//
+// If persistent filters are enabled, the filter is stored in the database and
+// survives restarts of the node, unless the maximum number of persistent filters
+// is reached.
 func (api *FilterAPI) NewFilter(crit FilterCriteria) (rpc.ID, error) {
+	if api.sys.cfg.Persistent && crit.BlockHash == nil {
+		if err := checkLogsQuery(ethereum.FilterQuery(crit)); err != nil {
+			return "", err
+		}
+		f := &persistentFilter{Addresses: crit.Addresses, Topics: crit.Topics, FromBlock: crit.FromBlock, ToBlock: crit.ToBlock}
+		if id, ok := api.newPersistentFilter(f); ok {
+			return id, nil
+		}
+	}
 	logs := make(chan []*types.Log)
 	logsSub, err := api.events.SubscribeLogs(ethereum.FilterQuery(crit), logs)
 	if err != nil {
@@ -334,12 +403,135 @@ func (api *FilterAPI) NewFilter(crit FilterCriteria) (rpc.ID, error) {
 
 // GetLogs returns logs matching the given argument that are stored within the state.
 func (api *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*types.Log, error) {
+	filter, err := api.logsFilter(ctx, crit)
+	if err != nil {
+		return nil, err
+	}
+	// Run the filter and return all the logs
+	logs, err := filter.Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return returnLogs(logs), err
+}
+
+// LogsPage is a page of logs returned by GetLogsPage.
+type LogsPage struct {
+	Logs   []*types.Log  `json:"logs"`
+	Cursor hexutil.Bytes `json:"cursor,omitempty"` // position of the next page, empty if done
+}
+
+// GetLogsPage returns at most limit logs matching the given argument, together with
+// a cursor to retrieve the following logs. The cursor is passed back with the same
+// criteria to resume the search after the last returned log. An error is returned if
+// the block the cursor points into is not part of the canonical chain anymore.
+func (api *FilterAPI) GetLogsPage(ctx context.Context, crit FilterCriteria, limit hexutil.Uint, cursor *hexutil.Bytes) (*LogsPage, error) {
+	if limit == 0 || limit > maxPageSize {
+		return nil, errInvalidPageSize
+	}
+	var pos logCursor
+	if cursor != nil {
+		if err := pos.decode(*cursor); err != nil {
+			return nil, err
+		}
+		if err := api.resumeAt(ctx, &crit, pos); err != nil {
+			return nil, err
+		}
+	}
+	filter, err := api.logsFilter(ctx, crit)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		errPageFull = errors.New("page full")
+		page        = &LogsPage{Logs: []*types.Log{}}
+	)
+	err = filter.StreamLogs(ctx, func(log *types.Log) error {
+		if cursor != nil && log.BlockNumber == pos.number && log.Index < pos.index {
+			return nil // returned by the previous page
+		}
+		if len(page.Logs) == int(limit) {
+			next := logCursor{number: log.BlockNumber, hash: log.BlockHash, index: log.Index}
+			page.Cursor = next.encode()
+			return errPageFull
+		}
+		page.Logs = append(page.Logs, log)
+		return nil
+	})
+	if err != nil && err != errPageFull {
+		return nil, err
+	}
+	return page, nil
+}
+
+// resumeAt moves the start of the criteria to the position of the cursor, checking
+// that it lies within the requested range and on the canonical chain.
+func (api *FilterAPI) resumeAt(ctx context.Context, crit *FilterCriteria, pos logCursor) error {
+	if crit.BlockHash != nil {
+		if *crit.BlockHash != pos.hash {
+			return errInvalidCursor
+		}
+		return nil
+	}
+	if crit.FromBlock != nil && crit.FromBlock.Sign() >= 0 && pos.number < crit.FromBlock.Uint64() {
+		return errInvalidCursor
+	}
+	if crit.ToBlock != nil && crit.ToBlock.Sign() >= 0 && pos.number > crit.ToBlock.Uint64() {
+		return errInvalidCursor
+	}
+	header, err := api.sys.backend.HeaderByNumber(ctx, rpc.BlockNumber(pos.number))
+	if err != nil {
+		return err
+	}
+	if header == nil || header.Hash() != pos.hash {
+		return errCursorReorged
+	}
+	crit.FromBlock = new(big.Int).SetUint64(pos.number)
+	return nil
+}
+
+// logCursor is the position of a log within the chain.
+type logCursor struct {
+	number uint64
+	hash   common.Hash
+	index  uint
+}
+
+// encode returns the opaque representation of the cursor handed to clients.
+func (c logCursor) encode() hexutil.Bytes {
+	enc := make([]byte, 8+common.HashLength+4)
+	binary.BigEndian.PutUint64(enc, c.number)
+	copy(enc[8:], c.hash[:])
+	binary.BigEndian.PutUint32(enc[8+common.HashLength:], uint32(c.index))
+	return enc
+}
+
+// decode parses a cursor created by encode.
+func (c *logCursor) decode(enc []byte) error {
+	if len(enc) != 8+common.HashLength+4 {
+		return errInvalidCursor
+	}
+	c.number = binary.BigEndian.Uint64(enc)
+	c.hash = common.BytesToHash(enc[8 : 8+common.HashLength])
+	c.index = uint(binary.BigEndian.Uint32(enc[8+common.HashLength:]))
+	return nil
+}
+
+// logsFilter creates the filter for a log query.
+func (api *FilterAPI) logsFilter(ctx context.Context, crit FilterCriteria) (*Filter, error) {
 	if len(crit.Topics) > maxTopics {
 		return nil, errExceedMaxTopics
 	}
 	var filter *Filter
 	if crit.BlockHash != nil {
 		// Block filter requested, construct a single-shot filter
+		header, err := api.sys.backend.HeaderByHash(ctx, *crit.BlockHash)
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			return nil, errors.New("unknown block")
+		}
 		filter = api.sys.NewBlockFilter(*crit.BlockHash, crit.Addresses, crit.Topics)
 	} else {
 		// Convert the RPC block numbers into internal representations
@@ -357,12 +549,7 @@ func (api *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*type
 		// Construct the range filter
 		filter = api.sys.NewRangeFilter(begin, end, crit.Addresses, crit.Topics)
 	}
-	// Run the filter and return all the logs
-	logs, err := filter.Logs(ctx)
-	if err != nil {
-		return nil, err
-	}
-	return returnLogs(logs), err
+	return filter, nil
 }
 
 // UninstallFilter removes the filter with the given filter id.
@@ -372,12 +559,17 @@ func (api *FilterAPI) UninstallFilter(id rpc.ID) bool {
 	if found {
 		delete(api.filters, id)
 	}
+	_, persistent := api.persistent[id]
+	if persistent {
+		delete(api.persistent, id)
+		rawdb.DeleteFilter(api.sys.backend.ChainDb(), string(id))
+	}
 	api.filtersMu.Unlock()
 	if found {
 		f.s.Unsubscribe()
 	}
 
-	return found
+	return found || persistent
 }
 
 // GetFilterLogs returns the logs for the filter with the given id.
@@ -385,28 +577,35 @@ func (api *FilterAPI) UninstallFilter(id rpc.ID) bool {
 func (api *FilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*types.Log, error) {
 	api.filtersMu.Lock()
 	f, found := api.filters[id]
+	pf := api.persistent[id]
 	api.filtersMu.Unlock()
 
-	if !found || f.typ != LogsSubscription {
+	var crit FilterCriteria
+	switch {
+	case found && f.typ == LogsSubscription:
+		crit = f.crit
+	case pf != nil && !pf.Blocks:
+		crit = pf.criteria()
+	default:
 		return nil, errFilterNotFound
 	}
 
 	var filter *Filter
-	if f.crit.BlockHash != nil {
+	if crit.BlockHash != nil {
 		// Block filter requested, construct a single-shot filter
-		filter = api.sys.NewBlockFilter(*f.crit.BlockHash, f.crit.Addresses, f.crit.Topics)
+		filter = api.sys.NewBlockFilter(*crit.BlockHash, crit.Addresses, crit.Topics)
 	} else {
 		// Convert the RPC block numbers into internal representations
 		begin := rpc.LatestBlockNumber.Int64()
-		if f.crit.FromBlock != nil {
-			begin = f.crit.FromBlock.Int64()
+		if crit.FromBlock != nil {
+			begin = crit.FromBlock.Int64()
 		}
 		end := rpc.LatestBlockNumber.Int64()
-		if f.crit.ToBlock != nil {
-			end = f.crit.ToBlock.Int64()
+		if crit.ToBlock != nil {
+			end = crit.ToBlock.Int64()
 		}
 		// Construct the range filter
-		filter = api.sys.NewRangeFilter(begin, end, f.crit.Addresses, f.crit.Topics)
+		filter = api.sys.NewRangeFilter(begin, end, crit.Addresses, crit.Topics)
 	}
 	// Run the filter and return all the logs
 	logs, err := filter.Logs(ctx)
@@ -421,8 +620,12 @@ func (api *FilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*types.Lo
 //
 // For pending transaction and block filters the result is []common.Hash.
 // (pending)Log filters return []Log.
-func (api *FilterAPI) GetFilterChanges(id rpc.ID) (interface{}, error) {
+func (api *FilterAPI) GetFilterChanges(ctx context.Context, id rpc.ID) (interface{}, error) {
 	api.filtersMu.Lock()
+	if pf, found := api.persistent[id]; found {
+		api.filtersMu.Unlock()
+		return api.persistentFilterChanges(ctx, id, pf)
+	}
 	defer api.filtersMu.Unlock()
 
 	chainConfig := api.sys.backend.ChainConfig()
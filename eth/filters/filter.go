@@ -19,15 +19,22 @@ package filters
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 	"slices"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
+// maxLogIndexLookups is the maximum number of address and topic combinations looked
+// up in the log index per section. Filters with more use the bloom filters.
+const maxLogIndexLookups = 256
+
 // Filter can be used to retrieve and filter logs.
 type Filter struct {
 	sys *FilterSystem
@@ -96,21 +103,42 @@ func newFilter(sys *FilterSystem, addresses []common.Address, topics [][]common.
 // Logs searches the blockchain for matching log entries, returning all from the
 // first block that contains matches, updating the start of the filter accordingly.
 func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
+	var logs []*types.Log
+	err := f.StreamLogs(ctx, func(log *types.Log) error {
+		logs = append(logs, log)
+		return nil
+	})
+	return logs, err
+}
+
+// StreamLogs searches the blockchain for matching log entries like Logs, but passes
+// them to yield as they are found instead of collecting them. The search stops when
+// yield returns an error.
+func (f *Filter) StreamLogs(ctx context.Context, yield func(*types.Log) error) error {
 	// If we're doing singleton block filtering, execute and return
 	if f.block != nil {
 		header, err := f.sys.backend.HeaderByHash(ctx, *f.block)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		if header == nil {
-			return nil, errors.New("unknown block")
+			return errors.New("unknown block")
+		}
+		logs, err := f.blockLogs(ctx, header)
+		if err != nil {
+			return err
+		}
+		for _, log := range logs {
+			if err := yield(log); err != nil {
+				return err
+			}
 		}
-		return f.blockLogs(ctx, header)
+		return nil
 	}
 
 	// Disallow pending logs.
 	if f.begin == rpc.PendingBlockNumber.Int64() || f.end == rpc.PendingBlockNumber.Int64() {
-		return nil, errPendingLogsUnsupported
+		return errPendingLogsUnsupported
 	}
 
 	resolveSpecial := func(number int64) (int64, error) {
@@ -142,20 +170,32 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	var err error
 	// range query need to resolve the special begin/end block number
 	if f.begin, err = resolveSpecial(f.begin); err != nil {
-		return nil, err
+		return err
 	}
 	if f.end, err = resolveSpecial(f.end); err != nil {
-		return nil, err
+		return err
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	logChan, errChan := f.rangeLogsAsync(ctx)
-	var logs []*types.Log
 	for {
 		select {
 		case log := <-logChan:
-			logs = append(logs, log)
+			if err := yield(log); err != nil {
+				// Stop the search and wait for it to end.
+				cancel()
+				for {
+					select {
+					case <-logChan:
+					case <-errChan:
+						return err
+					}
+				}
+			}
 		case err := <-errChan:
-			return logs, err
+			return err
 		}
 	}
 }
@@ -180,6 +220,15 @@ func (f *Filter) rangeLogsAsync(ctx context.Context) (chan *types.Log, chan erro
 			size, sections = f.sys.backend.BloomStatus()
 			err            error
 		)
+		if indexed := f.logIndexed(); indexed > uint64(f.begin) {
+			if indexed > end {
+				indexed = end + 1
+			}
+			if err = f.logIndexedLogs(ctx, indexed-1, logChan); err != nil {
+				errChan <- err
+				return
+			}
+		}
 		if indexed := sections * size; indexed > uint64(f.begin) {
 			if indexed > end {
 				indexed = end + 1
@@ -247,6 +296,123 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64, logChan chan *type
 	}
 }
 
+// logIndexed returns the number of blocks covered by the log index, or zero if the
+// index can't serve the filter criteria.
+func (f *Filter) logIndexed() uint64 {
+	lookups := len(f.addresses)
+	if len(f.topics) > 0 && len(f.topics[0]) > 0 {
+		lookups *= len(f.topics[0])
+	}
+	if lookups == 0 || lookups > maxLogIndexLookups {
+		return 0
+	}
+	size, sections := f.sys.backend.LogIndexStatus()
+	return sections * size
+}
+
+// logIndexedLogs returns the logs matching the filter criteria based on the log
+// index. It stops early at sections which are not indexed for the canonical chain,
+// leaving them to the bloom filters.
+func (f *Filter) logIndexedLogs(ctx context.Context, end uint64, logChan chan *types.Log) error {
+	var (
+		db      = f.sys.backend.ChainDb()
+		size, _ = f.sys.backend.LogIndexStatus()
+	)
+	for f.begin <= int64(end) {
+		section := uint64(f.begin) / size
+		head := rawdb.ReadCanonicalHash(db, (section+1)*size-1)
+		if !rawdb.HasLogIndexSection(db, section, head) {
+			return nil
+		}
+		numbers, indices, err := f.logIndexMatches(db, section, head)
+		if err != nil {
+			return err
+		}
+		last := min((section+1)*size-1, end)
+		for _, number := range numbers {
+			if number < uint64(f.begin) || number > last {
+				continue
+			}
+			header, err := f.sys.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+			if header == nil || err != nil {
+				return err
+			}
+			found, err := f.logsAt(ctx, header, indices[number])
+			if err != nil {
+				return err
+			}
+			for _, log := range found {
+				select {
+				case logChan <- log:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+		f.begin = int64(last) + 1
+	}
+	return nil
+}
+
+// logIndexMatches returns the numbers of the blocks within the section which
+// contain logs of the filtered addresses and first topics in ascending order, and
+// the indices of these logs within each block, also in ascending order.
+func (f *Filter) logIndexMatches(db ethdb.KeyValueReader, section uint64, head common.Hash) ([]uint64, map[uint64][]uint, error) {
+	topics := []*common.Hash{nil}
+	if len(f.topics) > 0 && len(f.topics[0]) > 0 {
+		topics = topics[:0]
+		for i := range f.topics[0] {
+			topics = append(topics, &f.topics[0][i])
+		}
+	}
+	var (
+		indices = make(map[uint64][]uint)
+		numbers []uint64
+	)
+	for _, address := range f.addresses {
+		for _, topic := range topics {
+			positions, err := rawdb.ReadLogIndex(db, address, topic, section, head)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, pos := range positions {
+				if _, ok := indices[pos.Number]; !ok {
+					numbers = append(numbers, pos.Number)
+				}
+				indices[pos.Number] = append(indices[pos.Number], pos.Index)
+			}
+		}
+	}
+	slices.Sort(numbers)
+	if len(f.addresses)*len(topics) > 1 {
+		// Merge the positions of the lookups.
+		for number, list := range indices {
+			slices.Sort(list)
+			indices[number] = slices.Compact(list)
+		}
+	}
+	return numbers, indices, nil
+}
+
+// logsAt returns the logs at the given indices within the block which match the
+// filter criteria. The log index only covers addresses and first topics, so the
+// remaining topics are checked here.
+func (f *Filter) logsAt(ctx context.Context, header *types.Header, indices []uint) ([]*types.Log, error) {
+	hash := header.Hash()
+	cached, err := f.sys.cachedLogElem(ctx, hash, header.Number.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	logs := make([]*types.Log, 0, len(indices))
+	for _, index := range indices {
+		if index >= uint(len(cached.logs)) {
+			return nil, fmt.Errorf("log index of block #%d [%x..] refers to missing log %d", header.Number, hash[:4], index)
+		}
+		logs = append(logs, cached.logs[index])
+	}
+	return f.deriveLogs(ctx, cached, header, filterLogs(logs, nil, nil, f.addresses, f.topics))
+}
+
 // unindexedLogs returns the logs matching the filter criteria based on raw block
 // iteration and bloom matching.
 func (f *Filter) unindexedLogs(ctx context.Context, end uint64, logChan chan *types.Log) error {
@@ -292,6 +458,12 @@ func (f *Filter) checkMatches(ctx context.Context, header *types.Header) ([]*typ
 		return nil, err
 	}
 	logs := filterLogs(cached.logs, nil, nil, f.addresses, f.topics)
+	return f.deriveLogs(ctx, cached, header, logs)
+}
+
+// deriveLogs fills in the transaction hashes of logs of the block, copying them so
+// that the cached logs are not modified.
+func (f *Filter) deriveLogs(ctx context.Context, cached *logCacheElem, header *types.Header, logs []*types.Log) ([]*types.Log, error) {
 	if len(logs) == 0 {
 		return nil, nil
 	}
@@ -300,7 +472,7 @@ func (f *Filter) checkMatches(ctx context.Context, header *types.Header) ([]*typ
 		return logs, nil
 	}
 
-	body, err := f.sys.cachedGetBody(ctx, cached, hash, header.Number.Uint64())
+	body, err := f.sys.cachedGetBody(ctx, cached, header.Hash(), header.Number.Uint64())
 	if err != nil {
 		return nil, err
 	}
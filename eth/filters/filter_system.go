@@ -42,6 +42,7 @@ import (
 type Config struct {
 	LogCacheSize int           // maximum number of cached blocks (default: 32)
 	Timeout      time.Duration // how long filters stay active (default: 5min)
+	Persistent   bool          // store log and block filters in the database
 }
 
 func (cfg Config) withDefaults() Config {
@@ -71,6 +72,10 @@ type Backend interface {
 
 	BloomStatus() (uint64, uint64)
 	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
+
+	// LogIndexStatus returns the section size of the log index and the number of
+	// sections indexed, which is zero if the index is disabled.
+	LogIndexStatus() (uint64, uint64)
 }
 
 // FilterSystem holds resources shared by all filters.
@@ -285,8 +290,16 @@ func (es *EventSystem) subscribe(sub *subscription) *Subscription {
 // given criteria to the given logs channel. Default value for the from and to
 // block is "latest". If the fromBlock > toBlock an error is returned.
 func (es *EventSystem) SubscribeLogs(crit ethereum.FilterQuery, logs chan []*types.Log) (*Subscription, error) {
+	if err := checkLogsQuery(crit); err != nil {
+		return nil, err
+	}
+	return es.subscribeLogs(crit, logs), nil
+}
+
+// checkLogsQuery validates the criteria of a filter for newly mined logs.
+func checkLogsQuery(crit ethereum.FilterQuery) error {
 	if len(crit.Topics) > maxTopics {
-		return nil, errExceedMaxTopics
+		return errExceedMaxTopics
 	}
 	var from, to rpc.BlockNumber
 	if crit.FromBlock == nil {
@@ -302,22 +315,22 @@ func (es *EventSystem) SubscribeLogs(crit ethereum.FilterQuery, logs chan []*typ
 
 	// Pending logs are not supported anymore.
 	if from == rpc.PendingBlockNumber || to == rpc.PendingBlockNumber {
-		return nil, errPendingLogsUnsupported
+		return errPendingLogsUnsupported
 	}
 
 	// only interested in new mined logs
 	if from == rpc.LatestBlockNumber && to == rpc.LatestBlockNumber {
-		return es.subscribeLogs(crit, logs), nil
+		return nil
 	}
 	// only interested in mined logs within a specific block range
 	if from >= 0 && to >= 0 && to >= from {
-		return es.subscribeLogs(crit, logs), nil
+		return nil
 	}
 	// interested in logs from a specific block number to new mined blocks
 	if from >= 0 && to == rpc.LatestBlockNumber {
-		return es.subscribeLogs(crit, logs), nil
+		return nil
 	}
-	return nil, errInvalidBlockRange
+	return errInvalidBlockRange
 }
 
 // subscribeLogs creates a subscription that will write all logs matching the
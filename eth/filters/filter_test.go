@@ -26,12 +26,14 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/triedb"
@@ -387,3 +389,210 @@ func TestFilters(t *testing.T) {
 		}
 	})
 }
+
+func TestGetLogsPage(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		_, sys = newTestFilterSystem(t, db, Config{})
+		api    = NewFilterAPI(sys)
+		addr   = common.Address{0xfe}
+		gspec  = &core.Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+	)
+	_, chain, receipts := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 10, func(i int, gen *core.BlockGen) {
+		if i%3 == 2 {
+			return // some blocks without logs
+		}
+		receipt := types.NewReceipt(nil, false, 0)
+		receipt.Logs = []*types.Log{{Address: addr}, {Address: addr}, {Address: addr}}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		gen.AddUncheckedReceipt(receipt)
+		gen.AddUncheckedTx(types.NewTransaction(999, common.HexToAddress("0x999"), big.NewInt(999), 999, gen.BaseFee(), nil))
+	})
+	gspec.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	crit := FilterCriteria{FromBlock: big.NewInt(0), Addresses: []common.Address{addr}}
+	want, err := sys.NewRangeFilter(0, rpc.LatestBlockNumber.Int64(), crit.Addresses, nil).Logs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(want) != 21 {
+		t.Fatalf("wrong number of logs in test chain: %d", len(want))
+	}
+
+	// Collect all logs page by page.
+	var (
+		have   []*types.Log
+		cursor *hexutil.Bytes
+		pages  int
+	)
+	for {
+		page, err := api.GetLogsPage(context.Background(), crit, 4, cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Logs) > 4 {
+			t.Fatalf("page %d has %d logs", pages, len(page.Logs))
+		}
+		have = append(have, page.Logs...)
+		pages++
+		if len(page.Cursor) == 0 {
+			break
+		}
+		cursor = &page.Cursor
+	}
+	if pages != 6 {
+		t.Errorf("wrong number of pages: %d", pages)
+	}
+	haveJSON, _ := json.Marshal(have)
+	wantJSON, _ := json.Marshal(want)
+	if string(haveJSON) != string(wantJSON) {
+		t.Fatalf("wrong logs:\nhave %s\nwant %s", haveJSON, wantJSON)
+	}
+	// Streamed logs match as well.
+	stream, err := (&StreamingFilterAPI{api}).GetLogs(context.Background(), crit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if streamJSON, _ := json.Marshal(stream); string(streamJSON) != string(wantJSON) {
+		t.Fatalf("wrong streamed logs:\nhave %s\nwant %s", streamJSON, wantJSON)
+	}
+
+	// Cursors are checked against the query and the canonical chain.
+	page, err := api.GetLogsPage(context.Background(), crit, 4, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.GetLogsPage(context.Background(), FilterCriteria{FromBlock: big.NewInt(5)}, 4, &page.Cursor); err != errInvalidCursor {
+		t.Errorf("expected invalid cursor error for cursor before range, got %v", err)
+	}
+	invalid := hexutil.Bytes{1, 2, 3}
+	if _, err := api.GetLogsPage(context.Background(), crit, 4, &invalid); err != errInvalidCursor {
+		t.Errorf("expected invalid cursor error for malformed cursor, got %v", err)
+	}
+	if _, err := api.GetLogsPage(context.Background(), crit, 0, nil); err != errInvalidPageSize {
+		t.Errorf("expected invalid page size error, got %v", err)
+	}
+	rawdb.WriteCanonicalHash(db, common.Hash{0x01}, chain[1].NumberU64())
+	if _, err := api.GetLogsPage(context.Background(), crit, 4, &page.Cursor); err != errCursorReorged {
+		t.Errorf("expected reorged cursor error, got %v", err)
+	}
+}
+
+// testIndexerChain feeds the current head of a test backend into a chain indexer.
+type testIndexerChain struct {
+	*testBackend
+	feed event.Feed
+}
+
+func (c *testIndexerChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return c.feed.Subscribe(ch)
+}
+
+func TestLogIndex(t *testing.T) {
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(t, db, Config{})
+		addr1        = common.Address{0xfe}
+		addr2        = common.Address{0xff}
+		topic1       = common.Hash{0x01}
+		topic2       = common.Hash{0x02}
+		gspec        = &core.Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+	)
+	_, chain, receipts := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 20, func(i int, gen *core.BlockGen) {
+		receipt := types.NewReceipt(nil, false, 0)
+		if i%2 == 0 {
+			receipt.Logs = append(receipt.Logs, &types.Log{Address: addr1, Topics: []common.Hash{topic1}})
+		}
+		if i%3 == 0 {
+			receipt.Logs = append(receipt.Logs, &types.Log{Address: addr1, Topics: []common.Hash{topic2, topic1}})
+		}
+		if i%5 == 0 {
+			receipt.Logs = append(receipt.Logs, &types.Log{Address: addr2, Topics: []common.Hash{topic1}})
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		gen.AddUncheckedReceipt(receipt)
+		gen.AddUncheckedTx(types.NewTransaction(999, common.HexToAddress("0x999"), big.NewInt(999), 999, gen.BaseFee(), nil))
+	})
+	gspec.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+
+	queries := []struct {
+		addresses []common.Address
+		topics    [][]common.Hash
+	}{
+		{addresses: []common.Address{addr1}},
+		{addresses: []common.Address{addr1, addr2}, topics: [][]common.Hash{{topic1}}},
+		{addresses: []common.Address{addr1}, topics: [][]common.Hash{{topic2}, {topic1}}},
+		{addresses: []common.Address{addr2}, topics: [][]common.Hash{{topic2}}},
+		{topics: [][]common.Hash{{topic1}}},
+	}
+	var want []string
+	for _, q := range queries {
+		logs, err := sys.NewRangeFilter(0, rpc.LatestBlockNumber.Int64(), q.addresses, q.topics).Logs(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		enc, _ := json.Marshal(logs)
+		want = append(want, string(enc))
+	}
+
+	// Index the first two sections, the rest of the chain is filtered without index.
+	backend.logSectionSize = 8
+	backend.logIndexer = core.NewLogIndexer(db, 8, 0)
+	backend.logIndexer.Start(&testIndexerChain{testBackend: backend})
+	defer backend.logIndexer.Close()
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if sections, _, _ := backend.logIndexer.Sections(); sections == 2 {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("log index not generated")
+		}
+	}
+	for i, q := range queries {
+		logs, err := sys.NewRangeFilter(0, rpc.LatestBlockNumber.Int64(), q.addresses, q.topics).Logs(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have, _ := json.Marshal(logs); string(have) != want[i] {
+			t.Errorf("query %d: wrong logs with index\nhave %s\nwant %s", i, have, want[i])
+		}
+	}
+
+	// The index is used for the indexed sections: logs missing from it are not found.
+	rawdb.WriteLogIndex(db, addr1, nil, 0, chain[6].Hash(), nil)
+	logs, err := sys.NewRangeFilter(0, rpc.LatestBlockNumber.Int64(), []common.Address{addr1}, nil).Logs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, log := range logs {
+		if log.BlockNumber < 8 {
+			t.Fatalf("log of block %d found despite missing index entry", log.BlockNumber)
+		}
+	}
+	// Only the logs at the indexed positions are returned.
+	rawdb.WriteLogIndex(db, addr1, nil, 0, chain[6].Hash(), []rawdb.LogPosition{{Number: 7, Index: 1}})
+	logs, err = sys.NewRangeFilter(0, 7, []common.Address{addr1}, nil).Logs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].BlockNumber != 7 || logs[0].Index != 1 || logs[0].Topics[0] != topic2 {
+		t.Fatalf("wrong logs for indexed position: %v", logs)
+	}
+}
@@ -0,0 +1,326 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// persistentFilterTimeout is how long persistent filters are kept without being
+	// polled. Unlike the timeout of in-memory filters, it is long enough to cover node
+	// maintenance.
+	persistentFilterTimeout = 24 * time.Hour
+
+	// maxPersistentFilters is the maximum number of persistent filters. Filters
+	// created beyond it are kept in memory only.
+	maxPersistentFilters = 10000
+
+	// maxPersistentPollBlocks is the maximum number of blocks a poll of a persistent
+	// filter scans. Filters which fell further behind catch up over several polls.
+	maxPersistentPollBlocks = 2000
+)
+
+// persistentFilter is a log or block filter stored in the database. Instead of
+// collecting events while the node runs, it keeps track of the last block delivered
+// to the client and retrieves the changes from the chain when polled.
+type persistentFilter struct {
+	Blocks    bool             `json:"blocks,omitempty"` // block filter instead of log filter
+	Addresses []common.Address `json:"addresses,omitempty"`
+	Topics    [][]common.Hash  `json:"topics,omitempty"`
+	FromBlock *big.Int         `json:"fromBlock,omitempty"`
+	ToBlock   *big.Int         `json:"toBlock,omitempty"`
+
+	Number   uint64      `json:"number"`   // last block delivered to the client
+	Hash     common.Hash `json:"hash"`     // hash of the last delivered block, zero if not reached yet
+	LastPoll time.Time   `json:"lastPoll"` // time of the last poll, guarded by FilterAPI.filtersMu
+
+	mu sync.Mutex // serializes polls, guards the position
+}
+
+// criteria returns the filter criteria of a log filter.
+func (f *persistentFilter) criteria() FilterCriteria {
+	return FilterCriteria{FromBlock: f.FromBlock, ToBlock: f.ToBlock, Addresses: f.Addresses, Topics: f.Topics}
+}
+
+// filterGapError is returned when a persistent filter can't deliver the changes of
+// some blocks because their data is no longer available. The filter continues after
+// the missing blocks on the next poll.
+type filterGapError struct {
+	from, to uint64
+}
+
+func (e *filterGapError) Error() string {
+	return fmt.Sprintf("filter missed blocks %d-%d: chain data no longer available", e.from, e.to)
+}
+
+func (e *filterGapError) ErrorCode() int { return -32000 }
+
+func (e *filterGapError) ErrorData() interface{} {
+	return map[string]hexutil.Uint64{"fromBlock": hexutil.Uint64(e.from), "toBlock": hexutil.Uint64(e.to)}
+}
+
+// loadPersistentFilters reads the persistent filters from the database.
+func (api *FilterAPI) loadPersistentFilters() {
+	for id, blob := range rawdb.ReadFilters(api.sys.backend.ChainDb()) {
+		f := new(persistentFilter)
+		if err := json.Unmarshal(blob, f); err != nil {
+			log.Warn("Dropping invalid persistent filter", "id", id, "err", err)
+			rawdb.DeleteFilter(api.sys.backend.ChainDb(), id)
+			continue
+		}
+		api.persistent[rpc.ID(id)] = f
+	}
+	if len(api.persistent) > 0 {
+		log.Info("Loaded persistent filters", "count", len(api.persistent))
+	}
+}
+
+// persistentFilterExpired returns whether the persistent filter was not polled for
+// longer than persistentFilterTimeout. The time the node was down doesn't count, so
+// that clients get the chance to poll their filters again after maintenance.
+func (api *FilterAPI) persistentFilterExpired(f *persistentFilter, now time.Time) bool {
+	lastPoll := f.LastPoll
+	if lastPoll.Before(api.started) {
+		lastPoll = api.started
+	}
+	return now.Sub(lastPoll) > persistentFilterTimeout
+}
+
+// storePersistentFilter marks the filter as polled and writes it to the database.
+// It must be called with the lock of the filter held.
+func (api *FilterAPI) storePersistentFilter(id rpc.ID, f *persistentFilter) {
+	api.filtersMu.Lock()
+	defer api.filtersMu.Unlock()
+
+	// Don't resurrect filters uninstalled while being polled.
+	if api.persistent[id] != f {
+		return
+	}
+	f.LastPoll = time.Now()
+	blob, err := json.Marshal(f)
+	if err != nil {
+		log.Error("Failed to encode persistent filter", "id", id, "err", err)
+		return
+	}
+	rawdb.WriteFilter(api.sys.backend.ChainDb(), string(id), blob)
+}
+
+// newPersistentFilter installs a persistent filter delivering the changes after the
+// current head block. If the maximum number of persistent filters is reached, no
+// filter is installed and false is returned.
+func (api *FilterAPI) newPersistentFilter(f *persistentFilter) (rpc.ID, bool) {
+	head := api.sys.backend.CurrentHeader()
+	f.Number, f.Hash = head.Number.Uint64(), head.Hash()
+	if f.FromBlock != nil && f.FromBlock.Sign() > 0 && f.FromBlock.Uint64() > f.Number+1 {
+		// Start at a future block, whose hash is not known yet.
+		f.Number, f.Hash = f.FromBlock.Uint64()-1, common.Hash{}
+	}
+
+	id := rpc.NewID()
+	api.filtersMu.Lock()
+	if len(api.persistent) >= maxPersistentFilters {
+		api.filtersMu.Unlock()
+		return "", false
+	}
+	api.persistent[id] = f
+	api.filtersMu.Unlock()
+
+	f.mu.Lock()
+	api.storePersistentFilter(id, f)
+	f.mu.Unlock()
+	return id, true
+}
+
+// persistentFilterChanges returns the changes of a persistent filter since the last
+// time it was polled.
+func (api *FilterAPI) persistentFilterChanges(ctx context.Context, id rpc.ID, f *persistentFilter) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	number, hash := f.Number, f.Hash
+	hashes, logs, err := api.collectPersistentChanges(ctx, f)
+	if err != nil {
+		if _, ok := err.(*filterGapError); !ok {
+			// Retry from the same position next time.
+			f.Number, f.Hash = number, hash
+		}
+	}
+	api.storePersistentFilter(id, f)
+	if err != nil {
+		return nil, err
+	}
+	if f.Blocks {
+		return returnHashes(hashes), nil
+	}
+	return returnLogs(logs), nil
+}
+
+// collectPersistentChanges retrieves the block hashes or logs after the position
+// of the filter and moves the position towards the head block, by at most
+// maxPersistentPollBlocks blocks.
+func (api *FilterAPI) collectPersistentChanges(ctx context.Context, f *persistentFilter) ([]common.Hash, []*types.Log, error) {
+	backend := api.sys.backend
+
+	// If the last delivered block was reorged out, roll back to the canonical chain
+	// and report the logs of the dropped blocks as removed.
+	var logs []*types.Log
+	for f.Hash != (common.Hash{}) {
+		canonical, err := backend.HeaderByNumber(ctx, rpc.BlockNumber(f.Number))
+		if err != nil {
+			return nil, nil, err
+		}
+		if canonical != nil && canonical.Hash() == f.Hash {
+			break
+		}
+		dropped, err := backend.HeaderByHash(ctx, f.Hash)
+		if err != nil {
+			return nil, nil, err
+		}
+		if dropped == nil || f.Number == 0 {
+			// The dropped block is not known anymore, continue from the canonical
+			// block at the same height, or the head if the chain was rewound.
+			if canonical == nil {
+				canonical = backend.CurrentHeader()
+			}
+			f.Number, f.Hash = canonical.Number.Uint64(), canonical.Hash()
+			break
+		}
+		if !f.Blocks {
+			removed, err := api.blockLogs(ctx, dropped, f)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, log := range removed {
+				logcopy := *log
+				logcopy.Removed = true
+				logs = append(logs, &logcopy)
+			}
+		}
+		f.Number, f.Hash = f.Number-1, dropped.ParentHash
+	}
+
+	// Deliver the blocks up to the head, or the end of the filter range.
+	head := backend.CurrentHeader()
+	end := head.Number.Uint64()
+	if f.ToBlock != nil && f.ToBlock.Sign() >= 0 && f.ToBlock.Uint64() < end {
+		end = f.ToBlock.Uint64()
+	}
+	if f.Number >= end {
+		return []common.Hash{}, logs, nil
+	}
+	begin := f.Number + 1
+	if end-begin >= maxPersistentPollBlocks {
+		end = begin + maxPersistentPollBlocks - 1
+	}
+	available, err := api.firstAvailable(ctx, begin, end, f.Blocks)
+	if err != nil {
+		return nil, nil, err
+	}
+	if available > begin {
+		if len(logs) > 0 {
+			// Deliver the logs removed by a reorg first, the gap is reported by the
+			// next poll which starts from the rolled back position.
+			return []common.Hash{}, logs, nil
+		}
+		last, err := backend.HeaderByNumber(ctx, rpc.BlockNumber(available-1))
+		if err != nil {
+			return nil, nil, err
+		}
+		if last == nil {
+			return nil, nil, fmt.Errorf("header #%d not found", available-1)
+		}
+		f.Number, f.Hash = last.Number.Uint64(), last.Hash()
+		return nil, nil, &filterGapError{from: begin, to: available - 1}
+	}
+	last, err := backend.HeaderByNumber(ctx, rpc.BlockNumber(end))
+	if err != nil {
+		return nil, nil, err
+	}
+	if last == nil {
+		return nil, nil, fmt.Errorf("header #%d not found", end)
+	}
+	var hashes []common.Hash
+	if f.Blocks {
+		for number := begin; number < end; number++ {
+			header, err := backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+			if err != nil {
+				return nil, nil, err
+			}
+			if header == nil {
+				return nil, nil, fmt.Errorf("header #%d not found", number)
+			}
+			hashes = append(hashes, header.Hash())
+		}
+		hashes = append(hashes, last.Hash())
+	} else {
+		found, err := api.sys.NewRangeFilter(int64(begin), int64(end), f.Addresses, f.Topics).Logs(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		logs = append(logs, found...)
+	}
+	f.Number, f.Hash = end, last.Hash()
+	return hashes, logs, nil
+}
+
+// blockLogs returns the logs of the block matching the criteria of the filter.
+func (api *FilterAPI) blockLogs(ctx context.Context, header *types.Header, f *persistentFilter) ([]*types.Log, error) {
+	return api.sys.NewBlockFilter(header.Hash(), f.Addresses, f.Topics).blockLogs(ctx, header)
+}
+
+// firstAvailable returns the first block within [begin, end] whose data needed by
+// the filter is still available, or end+1 if there is none. Since history is pruned
+// from the oldest block, the available blocks are found by binary search.
+func (api *FilterAPI) firstAvailable(ctx context.Context, begin, end uint64, headersOnly bool) (uint64, error) {
+	var err error
+	available := func(number uint64) bool {
+		if err != nil {
+			return false
+		}
+		var header *types.Header
+		if header, err = api.sys.backend.HeaderByNumber(ctx, rpc.BlockNumber(number)); header == nil || err != nil {
+			return false
+		}
+		if headersOnly || header.ReceiptHash == types.EmptyReceiptsHash {
+			return true
+		}
+		var receipts types.Receipts
+		receipts, err = api.sys.backend.GetReceipts(ctx, header.Hash())
+		return len(receipts) > 0
+	}
+	if available(begin) {
+		return begin, nil
+	}
+	n := sort.Search(int(end-begin), func(i int) bool {
+		return available(begin + 1 + uint64(i))
+	})
+	return begin + 1 + uint64(n), err
+}
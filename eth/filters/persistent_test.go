@@ -0,0 +1,229 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+func TestPersistentFilters(t *testing.T) {
+	var (
+		db    = rawdb.NewMemoryDatabase()
+		addr  = common.Address{0xfe}
+		gspec = &core.Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		addLog = func(i int, gen *core.BlockGen) {
+			receipt := types.NewReceipt(nil, false, 0)
+			receipt.Logs = []*types.Log{{Address: addr}}
+			receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+			gen.AddUncheckedReceipt(receipt)
+			gen.AddUncheckedTx(types.NewTransaction(999, common.HexToAddress("0x999"), big.NewInt(999), 999, gen.BaseFee(), nil))
+		}
+		writeBlocks = func(blocks []*types.Block, receipts []types.Receipts) {
+			for i, block := range blocks {
+				rawdb.WriteBlock(db, block)
+				rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+				rawdb.WriteHeadBlockHash(db, block.Hash())
+				rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+			}
+		}
+		checkLogs = func(have interface{}, want []*types.Block, removed bool) {
+			t.Helper()
+			logs := have.([]*types.Log)
+			if len(logs) != len(want) {
+				t.Fatalf("wrong number of logs: have %d, want %d", len(logs), len(want))
+			}
+			for i, log := range logs {
+				if log.BlockHash != want[i].Hash() || log.Removed != removed {
+					t.Fatalf("wrong log %d: block %d %x, removed %v", i, log.BlockNumber, log.BlockHash, log.Removed)
+				}
+			}
+		}
+	)
+	genDb, chain, receipts := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 14, addLog)
+	gspec.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	writeBlocks(chain[:5], receipts[:5])
+
+	_, sys := newTestFilterSystem(t, db, Config{Persistent: true})
+	api := NewFilterAPI(sys)
+	logsID, err := api.NewFilter(FilterCriteria{Addresses: []common.Address{addr}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocksID := api.NewBlockFilter()
+
+	// Filters are restored after a restart and deliver the blocks imported meanwhile.
+	writeBlocks(chain[5:10], receipts[5:10])
+	_, sys = newTestFilterSystem(t, db, Config{Persistent: true})
+	api = NewFilterAPI(sys)
+
+	logs, err := api.GetFilterChanges(context.Background(), logsID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLogs(logs, chain[5:10], false)
+	hashes, err := api.GetFilterChanges(context.Background(), blocksID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []common.Hash
+	for _, block := range chain[5:10] {
+		want = append(want, block.Hash())
+	}
+	if !reflect.DeepEqual(hashes, want) {
+		t.Fatalf("wrong block hashes: have %v, want %v", hashes, want)
+	}
+
+	// Logs of blocks dropped by a reorg are reported as removed.
+	fork, forkReceipts := core.GenerateChain(gspec.Config, chain[7], ethash.NewFaker(), genDb, 3, func(i int, gen *core.BlockGen) {
+		gen.SetExtra([]byte("fork"))
+		addLog(i, gen)
+	})
+	writeBlocks(fork, forkReceipts)
+	logs, err = api.GetFilterChanges(context.Background(), logsID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := logs.([]*types.Log)
+	checkLogs(all[:2], []*types.Block{chain[9], chain[8]}, true)
+	checkLogs(all[2:], fork, false)
+
+	// Blocks without data are reported as a gap, and skipped by the next poll.
+	next, nextReceipts := core.GenerateChain(gspec.Config, fork[2], ethash.NewFaker(), genDb, 3, addLog)
+	writeBlocks(next, nextReceipts)
+	rawdb.DeleteReceipts(db, next[0].Hash(), next[0].NumberU64())
+	rawdb.DeleteReceipts(db, next[1].Hash(), next[1].NumberU64())
+
+	_, err = api.GetFilterChanges(context.Background(), logsID)
+	if gap, ok := err.(*filterGapError); !ok || gap.from != 12 || gap.to != 13 {
+		t.Fatalf("expected gap error for blocks 12-13, got %v", err)
+	}
+	logs, err = api.GetFilterChanges(context.Background(), logsID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLogs(logs, next[2:], false)
+
+	// Logs removed by a reorg are delivered before a gap in the new chain.
+	fork, forkReceipts = core.GenerateChain(gspec.Config, next[1], ethash.NewFaker(), genDb, 3, func(i int, gen *core.BlockGen) {
+		gen.SetExtra([]byte("fork"))
+		addLog(i, gen)
+	})
+	writeBlocks(fork, forkReceipts)
+	rawdb.DeleteReceipts(db, fork[0].Hash(), fork[0].NumberU64())
+	rawdb.DeleteReceipts(db, fork[1].Hash(), fork[1].NumberU64())
+
+	logs, err = api.GetFilterChanges(context.Background(), logsID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLogs(logs, next[2:], true)
+	_, err = api.GetFilterChanges(context.Background(), logsID)
+	if gap, ok := err.(*filterGapError); !ok || gap.from != 14 || gap.to != 15 {
+		t.Fatalf("expected gap error for blocks 14-15, got %v", err)
+	}
+	logs, err = api.GetFilterChanges(context.Background(), logsID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLogs(logs, fork[2:], false)
+
+	// Uninstalled filters are removed from the database.
+	if !api.UninstallFilter(logsID) || !api.UninstallFilter(blocksID) {
+		t.Fatal("failed to uninstall filters")
+	}
+	if filters := rawdb.ReadFilters(db); len(filters) != 0 {
+		t.Fatalf("filters left in database: %v", filters)
+	}
+}
+
+func TestPersistentFilterLimits(t *testing.T) {
+	var (
+		db    = rawdb.NewMemoryDatabase()
+		gspec = &core.Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+	)
+	_, chain, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), maxPersistentPollBlocks+10, nil)
+	gspec.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	_, sys := newTestFilterSystem(t, db, Config{Persistent: true})
+	api := NewFilterAPI(sys)
+
+	// Filters beyond the limit are kept in memory.
+	for i := 0; i < maxPersistentFilters; i++ {
+		api.NewBlockFilter()
+	}
+	blocksID := api.NewBlockFilter()
+	if _, ok := api.filters[blocksID]; !ok {
+		t.Fatal("filter beyond the limit not installed in memory")
+	}
+	if n := len(rawdb.ReadFilters(db)); n != maxPersistentFilters {
+		t.Fatalf("wrong number of persistent filters: %d", n)
+	}
+
+	// Polls scan a limited number of blocks.
+	for id := range api.persistent {
+		blocksID = id
+		break
+	}
+	for _, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+	}
+	hashes, err := api.GetFilterChanges(context.Background(), blocksID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(hashes.([]common.Hash)); n != maxPersistentPollBlocks {
+		t.Fatalf("wrong number of blocks in first poll: %d", n)
+	}
+	hashes, err = api.GetFilterChanges(context.Background(), blocksID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(hashes.([]common.Hash)); n != 10 {
+		t.Fatalf("wrong number of blocks in second poll: %d", n)
+	}
+	// Filters time out after a day without polls, not counting the node downtime.
+	f := &persistentFilter{LastPoll: api.started.Add(-48 * time.Hour)}
+	if api.persistentFilterExpired(f, api.started.Add(time.Hour)) {
+		t.Fatal("filter expired right after startup")
+	}
+	if !api.persistentFilterExpired(f, api.started.Add(persistentFilterTimeout+time.Second)) {
+		t.Fatal("filter not expired a day after startup")
+	}
+	f.LastPoll = api.started.Add(time.Hour)
+	if api.persistentFilterExpired(f, api.started.Add(persistentFilterTimeout+time.Second)) {
+		t.Fatal("filter expired within a day of the last poll")
+	}
+}
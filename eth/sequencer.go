@@ -0,0 +1,390 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	sequencerCallTimeout    = 5 * time.Second        // Timeout of a single forwarding attempt
+	sequencerRetryBackoff   = 100 * time.Millisecond // Initial delay between forwarding rounds
+	sequencerMaxBackoff     = 2 * time.Second        // Maximum delay between forwarding rounds
+	sequencerMaxCooldown    = 30 * time.Second       // Maximum time a failing endpoint is deprioritized
+	sequencerOutboxLimit    = 4096                   // Maximum number of transactions waiting in the outbox
+	sequencerOutboxInterval = time.Second            // Interval between attempts to flush the outbox
+
+	// rpcTimeoutErrorCode is the JSON-RPC error code returned by a server that
+	// timed out processing the request.
+	rpcTimeoutErrorCode = -32002
+)
+
+var (
+	sequencerFailoverMeter    = metrics.NewRegisteredMeter("eth/sequencer/failover", nil)
+	sequencerOutboxGauge      = metrics.NewRegisteredGauge("eth/sequencer/outbox", nil)
+	sequencerOutboxSentMeter  = metrics.NewRegisteredMeter("eth/sequencer/outbox/sent", nil)
+	sequencerOutboxDropMeter  = metrics.NewRegisteredMeter("eth/sequencer/outbox/dropped", nil)
+	errSequencerOutboxFull    = errors.New("sequencer outbox full")
+	errNoSequencerEndpoints   = errors.New("no sequencer endpoints configured")
+	errSequencerForwarderDown = errors.New("sequencer forwarder closed")
+)
+
+// sequencerEndpoint is a single sequencer RPC endpoint along with its health.
+type sequencerEndpoint struct {
+	index  int
+	client *rpc.Client
+
+	latency metrics.Timer // Duration of forwarding calls
+	errors  metrics.Meter // Failed forwarding calls, excluding rejected transactions
+
+	failures       int       // Number of consecutive failures
+	unhealthyUntil time.Time // Endpoint is only used as a last resort until then
+}
+
+// outboxTx is a transaction accepted while no sequencer endpoint was
+// reachable, waiting to be forwarded.
+type outboxTx struct {
+	hash    common.Hash
+	data    []byte
+	expires time.Time
+}
+
+// sequencerForwarder forwards transactions to a list of sequencer endpoints.
+// Endpoints are tried in the configured order, but endpoints that recently
+// failed are moved to the back until they recover. Transient failures are
+// retried with backoff, and if a transaction can still not be forwarded it is
+// optionally kept in a short-lived outbox and retried in the background.
+type sequencerForwarder struct {
+	endpoints []*sequencerEndpoint
+	retries   int           // Number of additional forwarding rounds after transient failures
+	outboxTTL time.Duration // Lifetime of transactions in the outbox, 0 disables the outbox
+
+	outbox    []*outboxTx
+	outboxSet map[common.Hash]struct{}
+	lock      sync.Mutex // Protects endpoint health and the outbox
+	flushLock sync.Mutex // Ensures queued transactions are only forwarded once
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// dialSequencers connects to the comma separated list of sequencer endpoints.
+func dialSequencers(ctx context.Context, urls string) ([]*rpc.Client, error) {
+	var clients []*rpc.Client
+	for _, url := range strings.Split(urls, ",") {
+		if url = strings.TrimSpace(url); url == "" {
+			continue
+		}
+		client, err := rpc.DialContext(ctx, url)
+		if err != nil {
+			for _, c := range clients {
+				c.Close()
+			}
+			return nil, err
+		}
+		clients = append(clients, client)
+	}
+	if len(clients) == 0 {
+		return nil, errNoSequencerEndpoints
+	}
+	return clients, nil
+}
+
+// newSequencerForwarder creates a forwarder for the given sequencer clients,
+// ordered by preference. The forwarder takes ownership of the clients.
+func newSequencerForwarder(clients []*rpc.Client, retries int, outboxTTL time.Duration) *sequencerForwarder {
+	f := &sequencerForwarder{
+		retries:   retries,
+		outboxTTL: outboxTTL,
+		outboxSet: make(map[common.Hash]struct{}),
+		quit:      make(chan struct{}),
+	}
+	for i, client := range clients {
+		prefix := fmt.Sprintf("eth/sequencer/endpoint%d", i)
+		f.endpoints = append(f.endpoints, &sequencerEndpoint{
+			index:   i,
+			client:  client,
+			latency: metrics.NewRegisteredTimer(prefix+"/latency", nil),
+			errors:  metrics.NewRegisteredMeter(prefix+"/errors", nil),
+		})
+	}
+	if outboxTTL > 0 {
+		f.wg.Add(1)
+		go f.loop()
+	}
+	return f
+}
+
+// Close stops retrying queued transactions and closes all endpoints.
+func (f *sequencerForwarder) Close() {
+	close(f.quit)
+	f.wg.Wait()
+
+	f.lock.Lock()
+	if n := len(f.outbox); n > 0 {
+		log.Warn("Dropping transactions not forwarded to the sequencer", "count", n)
+		sequencerOutboxDropMeter.Mark(int64(n))
+	}
+	f.outbox, f.outboxSet = nil, make(map[common.Hash]struct{})
+	sequencerOutboxGauge.Update(0)
+	f.lock.Unlock()
+
+	for _, ep := range f.endpoints {
+		ep.client.Close()
+	}
+}
+
+// Forward sends the transaction to the sequencer. An error is returned if the
+// sequencer rejected the transaction, or if no endpoint could be reached and
+// the transaction could not be queued in the outbox.
+func (f *sequencerForwarder) Forward(ctx context.Context, tx *types.Transaction) error {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	err = f.send(ctx, data)
+	if err == nil || !isTransientSequencerError(err) || ctx.Err() != nil || f.outboxTTL == 0 {
+		return err
+	}
+	if qerr := f.enqueue(tx.Hash(), data); qerr != nil {
+		log.Warn("Failed to queue transaction for the sequencer", "hash", tx.Hash(), "err", qerr)
+		return err
+	}
+	log.Warn("Sequencer unavailable, queued transaction for forwarding", "hash", tx.Hash(), "err", err)
+	return nil
+}
+
+// send forwards the encoded transaction, retrying transient failures with
+// exponential backoff.
+func (f *sequencerForwarder) send(ctx context.Context, data []byte) error {
+	var err error
+	backoff := sequencerRetryBackoff
+	for attempt := 0; attempt <= f.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-f.quit:
+				return errSequencerForwarderDown
+			}
+			backoff = min(2*backoff, sequencerMaxBackoff)
+		}
+		if err = f.sendOnce(ctx, data); err == nil || !isTransientSequencerError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// sendOnce tries all endpoints, healthy ones first, until one accepts or
+// rejects the transaction.
+func (f *sequencerForwarder) sendOnce(ctx context.Context, data []byte) error {
+	err := errNoSequencerEndpoints
+	for i, ep := range f.candidates() {
+		if i > 0 {
+			sequencerFailoverMeter.Mark(1)
+		}
+		if err = f.call(ctx, ep, data); err == nil || !isTransientSequencerError(err) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+// call forwards the encoded transaction to a single endpoint and updates its
+// health accordingly.
+func (f *sequencerForwarder) call(ctx context.Context, ep *sequencerEndpoint, data []byte) error {
+	callCtx, cancel := context.WithTimeout(ctx, sequencerCallTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := ep.client.CallContext(callCtx, nil, "eth_sendRawTransaction", hexutil.Encode(data))
+	ep.latency.UpdateSince(start)
+
+	// A previous attempt may have reached the sequencer despite failing locally
+	if err != nil && strings.Contains(err.Error(), txpool.ErrAlreadyKnown.Error()) {
+		err = nil
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if err != nil && isTransientSequencerError(err) {
+		ep.errors.Mark(1)
+		ep.failures++
+		cooldown := min(sequencerRetryBackoff<<min(ep.failures, 16), sequencerMaxCooldown)
+		ep.unhealthyUntil = time.Now().Add(cooldown)
+		log.Debug("Sequencer endpoint failed", "endpoint", ep.index, "failures", ep.failures, "cooldown", cooldown, "err", err)
+		return err
+	}
+	if ep.failures > 0 {
+		log.Info("Sequencer endpoint recovered", "endpoint", ep.index, "failures", ep.failures)
+		ep.failures, ep.unhealthyUntil = 0, time.Time{}
+	}
+	return err
+}
+
+// candidates returns the endpoints in the order they should be tried: healthy
+// endpoints in the configured order, followed by unhealthy ones by the time
+// their cooldown ends.
+func (f *sequencerForwarder) candidates() []*sequencerEndpoint {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	now := time.Now()
+	endpoints := make([]*sequencerEndpoint, len(f.endpoints))
+	copy(endpoints, f.endpoints)
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		a, b := endpoints[i], endpoints[j]
+		aHealthy, bHealthy := !a.unhealthyUntil.After(now), !b.unhealthyUntil.After(now)
+		if aHealthy || bHealthy {
+			return aHealthy && !bHealthy
+		}
+		return a.unhealthyUntil.Before(b.unhealthyUntil)
+	})
+	return endpoints
+}
+
+// enqueue adds an encoded transaction to the outbox.
+func (f *sequencerForwarder) enqueue(hash common.Hash, data []byte) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if _, ok := f.outboxSet[hash]; ok {
+		return nil
+	}
+	if len(f.outbox) >= sequencerOutboxLimit {
+		return errSequencerOutboxFull
+	}
+	f.outbox = append(f.outbox, &outboxTx{hash: hash, data: data, expires: time.Now().Add(f.outboxTTL)})
+	f.outboxSet[hash] = struct{}{}
+	sequencerOutboxGauge.Update(int64(len(f.outbox)))
+	return nil
+}
+
+// loop periodically retries forwarding the transactions in the outbox.
+func (f *sequencerForwarder) loop() {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(sequencerOutboxInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			f.flush()
+		case <-f.quit:
+			return
+		}
+	}
+}
+
+// flush forwards the queued transactions in the order they were accepted. It
+// stops at the first transient failure, as the sequencer is still unavailable.
+func (f *sequencerForwarder) flush() {
+	f.flushLock.Lock()
+	defer f.flushLock.Unlock()
+
+	f.lock.Lock()
+	queued := make([]*outboxTx, len(f.outbox))
+	copy(queued, f.outbox)
+	f.lock.Unlock()
+
+	var done []common.Hash
+	for _, tx := range queued {
+		if time.Now().After(tx.expires) {
+			log.Warn("Dropping transaction not forwarded to the sequencer in time", "hash", tx.hash)
+			sequencerOutboxDropMeter.Mark(1)
+			done = append(done, tx.hash)
+			continue
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-f.quit:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		err := f.sendOnce(ctx, tx.data)
+		cancel()
+		if err != nil && isTransientSequencerError(err) {
+			break
+		}
+		if err != nil {
+			log.Warn("Sequencer rejected queued transaction", "hash", tx.hash, "err", err)
+			sequencerOutboxDropMeter.Mark(1)
+		} else {
+			log.Info("Forwarded queued transaction to the sequencer", "hash", tx.hash)
+			sequencerOutboxSentMeter.Mark(1)
+		}
+		done = append(done, tx.hash)
+	}
+	if len(done) == 0 {
+		return
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, hash := range done {
+		delete(f.outboxSet, hash)
+	}
+	remaining := f.outbox[:0]
+	for _, tx := range f.outbox {
+		if _, ok := f.outboxSet[tx.hash]; ok {
+			remaining = append(remaining, tx)
+		}
+	}
+	f.outbox = remaining
+	sequencerOutboxGauge.Update(int64(len(f.outbox)))
+}
+
+// isTransientSequencerError returns whether forwarding failed because the
+// endpoint could not process the request, as opposed to the sequencer
+// rejecting the transaction.
+func isTransientSequencerError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, errSequencerForwarderDown) {
+		return false
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusInternalServerError || httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == rpcTimeoutErrorCode
+	}
+	return true
+}
@@ -0,0 +1,231 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testSequencer is a sequencer endpoint which records the transactions it
+// receives, and can be made unavailable or reject transactions.
+type testSequencer struct {
+	srv  *httptest.Server
+	down atomic.Bool
+	fail error
+
+	lock sync.Mutex
+	txs  []common.Hash
+}
+
+type testSequencerAPI struct{ s *testSequencer }
+
+func (api *testSequencerAPI) SendRawTransaction(input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	if api.s.fail != nil {
+		return common.Hash{}, api.s.fail
+	}
+	api.s.lock.Lock()
+	defer api.s.lock.Unlock()
+	api.s.txs = append(api.s.txs, tx.Hash())
+	return tx.Hash(), nil
+}
+
+func newTestSequencer(t *testing.T) *testSequencer {
+	s := new(testSequencer)
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &testSequencerAPI{s}); err != nil {
+		t.Fatal(err)
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		server.ServeHTTP(w, r)
+	}))
+	t.Cleanup(func() {
+		s.srv.Close()
+		server.Stop()
+	})
+	return s
+}
+
+func (s *testSequencer) received() []common.Hash {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]common.Hash(nil), s.txs...)
+}
+
+func newTestForwarder(t *testing.T, retries int, outboxTTL time.Duration, seqs ...*testSequencer) *sequencerForwarder {
+	var urls []string
+	for _, s := range seqs {
+		urls = append(urls, s.srv.URL)
+	}
+	clients, err := dialSequencers(context.Background(), strings.Join(urls, ", "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := newSequencerForwarder(clients, retries, outboxTTL)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func outboxLen(f *sequencerForwarder) int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return len(f.outbox)
+}
+
+func newForwardedTx(nonce uint64) *types.Transaction {
+	return types.NewTx(&types.LegacyTx{Nonce: nonce, GasPrice: common.Big1, Gas: 21000})
+}
+
+func TestSequencerFailover(t *testing.T) {
+	primary, secondary := newTestSequencer(t), newTestSequencer(t)
+	f := newTestForwarder(t, 0, 0, primary, secondary)
+
+	// All transactions go to the primary while it is healthy
+	if err := f.Forward(context.Background(), newForwardedTx(0)); err != nil {
+		t.Fatalf("failed to forward: %v", err)
+	}
+	// Transactions fail over to the secondary while the primary is down
+	primary.down.Store(true)
+	for i := uint64(1); i < 3; i++ {
+		if err := f.Forward(context.Background(), newForwardedTx(i)); err != nil {
+			t.Fatalf("failed to forward: %v", err)
+		}
+	}
+	if n := len(primary.received()); n != 1 {
+		t.Fatalf("primary received %d txs, want 1", n)
+	}
+	if n := len(secondary.received()); n != 2 {
+		t.Fatalf("secondary received %d txs, want 2", n)
+	}
+	// The primary is deprioritized after failing
+	if candidates := f.candidates(); candidates[0].index != 1 {
+		t.Fatalf("unhealthy endpoint tried first")
+	}
+}
+
+func TestSequencerRejection(t *testing.T) {
+	primary, secondary := newTestSequencer(t), newTestSequencer(t)
+	primary.fail = errors.New("nonce too low")
+	f := newTestForwarder(t, 2, time.Minute, primary, secondary)
+
+	// A rejected transaction is neither retried, failed over nor queued
+	if err := f.Forward(context.Background(), newForwardedTx(0)); err == nil {
+		t.Fatal("expected rejection")
+	}
+	if n := len(secondary.received()); n != 0 {
+		t.Fatalf("secondary received %d txs, want 0", n)
+	}
+	if n := outboxLen(f); n != 0 {
+		t.Fatalf("outbox has %d txs, want 0", n)
+	}
+}
+
+func TestSequencerRetry(t *testing.T) {
+	seq := newTestSequencer(t)
+	seq.down.Store(true)
+	f := newTestForwarder(t, 3, 0, seq)
+
+	go func() {
+		time.Sleep(sequencerRetryBackoff)
+		seq.down.Store(false)
+	}()
+	if err := f.Forward(context.Background(), newForwardedTx(0)); err != nil {
+		t.Fatalf("failed to forward with retries: %v", err)
+	}
+	if n := len(seq.received()); n != 1 {
+		t.Fatalf("sequencer received %d txs, want 1", n)
+	}
+}
+
+func TestSequencerOutbox(t *testing.T) {
+	seq := newTestSequencer(t)
+	seq.down.Store(true)
+
+	// Without an outbox the error is returned to the user
+	f := newTestForwarder(t, 0, 0, seq)
+	if err := f.Forward(context.Background(), newForwardedTx(0)); err == nil {
+		t.Fatal("expected error without outbox")
+	}
+	// With an outbox the transactions are accepted, and forwarded in order once
+	// the sequencer is back
+	f = newTestForwarder(t, 0, time.Minute, seq)
+	txs := []*types.Transaction{newForwardedTx(0), newForwardedTx(1)}
+	for _, tx := range txs {
+		if err := f.Forward(context.Background(), tx); err != nil {
+			t.Fatalf("failed to queue tx: %v", err)
+		}
+	}
+	f.flush()
+	if n := len(seq.received()); n != 0 {
+		t.Fatalf("sequencer received %d txs while down", n)
+	}
+	seq.down.Store(false)
+	f.flush()
+
+	received := seq.received()
+	if len(received) != len(txs) {
+		t.Fatalf("sequencer received %d txs, want %d", len(received), len(txs))
+	}
+	for i, tx := range txs {
+		if received[i] != tx.Hash() {
+			t.Fatalf("tx %d: hash mismatch: have %x, want %x", i, received[i], tx.Hash())
+		}
+	}
+	if n := outboxLen(f); n != 0 {
+		t.Fatalf("outbox has %d txs after flush, want 0", n)
+	}
+}
+
+func TestSequencerOutboxExpiry(t *testing.T) {
+	seq := newTestSequencer(t)
+	seq.down.Store(true)
+	f := newTestForwarder(t, 0, time.Nanosecond, seq)
+
+	if err := f.Forward(context.Background(), newForwardedTx(0)); err != nil {
+		t.Fatalf("failed to queue tx: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	seq.down.Store(false)
+	f.flush()
+
+	if n := len(seq.received()); n != 0 {
+		t.Fatalf("sequencer received %d expired txs", n)
+	}
+	if n := outboxLen(f); n != 0 {
+		t.Fatalf("outbox has %d txs after expiry, want 0", n)
+	}
+}
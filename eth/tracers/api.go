@@ -39,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/internal/telemetry"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -165,6 +166,15 @@ type TraceCallConfig struct {
 	StateOverrides *ethapi.StateOverride
 	BlockOverrides *ethapi.BlockOverrides
 	TxIndex        *hexutil.Uint
+	Deposit        *DepositCallConfig
+}
+
+// DepositCallConfig turns a traced call into a deposit transaction, executed
+// as if it was derived from L1.
+type DepositCallConfig struct {
+	SourceHash common.Hash
+	Mint       *hexutil.Big // Amount minted to the sender before execution
+	IsSystemTx bool
 }
 
 // StdTraceConfig holds extra parameters to standard-json trace functions.
@@ -516,6 +526,73 @@ func (api *API) TraceBadBlock(ctx context.Context, hash common.Hash, config *Tra
 	return api.traceBlock(ctx, block, config)
 }
 
+// streamingAPI is the API as served over RPC. It shadows the block tracing methods
+// to stream the result of every transaction to the caller as soon as it is traced,
+// instead of collecting the results of the whole block in memory first. Go callers
+// keep using the typed methods of API.
+type streamingAPI struct {
+	*API
+}
+
+// TraceBlockByNumber is the streamed version of API.TraceBlockByNumber.
+func (api *streamingAPI) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber, config *TraceConfig) (*rpc.Stream, error) {
+	block, err := api.blockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if api.backend.ChainConfig().IsOptimismPreBedrock(block.Number()) {
+		results, err := api.API.TraceBlockByNumber(ctx, number, config)
+		if err != nil {
+			return nil, err
+		}
+		return streamTraceResults(results), nil
+	}
+	return api.streamBlock(ctx, block, config)
+}
+
+// TraceBlockByHash is the streamed version of API.TraceBlockByHash.
+func (api *streamingAPI) TraceBlockByHash(ctx context.Context, hash common.Hash, config *TraceConfig) (*rpc.Stream, error) {
+	block, err := api.blockByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if api.backend.ChainConfig().IsOptimismPreBedrock(block.Number()) {
+		results, err := api.API.TraceBlockByHash(ctx, hash, config)
+		if err != nil {
+			return nil, err
+		}
+		return streamTraceResults(results), nil
+	}
+	return api.streamBlock(ctx, block, config)
+}
+
+// TraceBlock is the streamed version of API.TraceBlock.
+func (api *streamingAPI) TraceBlock(ctx context.Context, blob hexutil.Bytes, config *TraceConfig) (*rpc.Stream, error) {
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(blob, block); err != nil {
+		return nil, fmt.Errorf("could not decode block: %v", err)
+	}
+	return api.streamBlock(ctx, block, config)
+}
+
+// TraceBlockFromFile is the streamed version of API.TraceBlockFromFile.
+func (api *streamingAPI) TraceBlockFromFile(ctx context.Context, file string, config *TraceConfig) (*rpc.Stream, error) {
+	blob, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not read file: %v", err)
+	}
+	return api.TraceBlock(ctx, blob, config)
+}
+
+// TraceBadBlock is the streamed version of API.TraceBadBlock.
+func (api *streamingAPI) TraceBadBlock(ctx context.Context, hash common.Hash, config *TraceConfig) (*rpc.Stream, error) {
+	block := rawdb.ReadBadBlock(api.backend.ChainDb(), hash)
+	if block == nil {
+		return nil, fmt.Errorf("bad block %#x not found", hash)
+	}
+	return api.streamBlock(ctx, block, config)
+}
+
 // StandardTraceBlockToFile dumps the structured logs created during the
 // execution of EVM to the local file system and returns a list of files
 // to the caller.
@@ -609,21 +686,55 @@ func (api *API) StandardTraceBadBlockToFile(ctx context.Context, hash common.Has
 // executes all the transactions contained within. The return value will be one item
 // per transaction, dependent on the requested tracer.
 func (api *API) traceBlock(ctx context.Context, block *types.Block, config *TraceConfig) ([]*txTraceResult, error) {
-	if block.NumberU64() == 0 {
-		return nil, errors.New("genesis is not traceable")
+	parent, err := api.traceableParent(ctx, block)
+	if err != nil {
+		return nil, err
 	}
-	// Prepare base state
-	parent, err := api.blockByNumberAndHash(ctx, rpc.BlockNumber(block.NumberU64()-1), block.ParentHash())
+	results := make([]*txTraceResult, 0, len(block.Transactions()))
+	err = api.streamBlockTraces(ctx, block, parent, config, func(result *txTraceResult) error {
+		results = append(results, result)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return results, nil
+}
+
+// streamBlock is like traceBlock, but streams the results as the transactions are
+// traced.
+func (api *API) streamBlock(ctx context.Context, block *types.Block, config *TraceConfig) (*rpc.Stream, error) {
+	parent, err := api.traceableParent(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	// The state is only regenerated once the result is written.
+	return rpc.NewStream(func(yield func(interface{}) error) error {
+		return api.streamBlockTraces(ctx, block, parent, config, func(result *txTraceResult) error {
+			return yield(result)
+		})
+	}), nil
+}
+
+// traceableParent returns the parent of the block to be traced, whose state the
+// transactions of the block are traced on.
+func (api *API) traceableParent(ctx context.Context, block *types.Block) (*types.Block, error) {
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	return api.blockByNumberAndHash(ctx, rpc.BlockNumber(block.NumberU64()-1), block.ParentHash())
+}
+
+// streamBlockTraces traces all the transactions of a block on top of the state of
+// its parent, passing the result of every transaction to yield in order.
+func (api *API) streamBlockTraces(ctx context.Context, block *types.Block, parent *types.Block, config *TraceConfig, yield func(*txTraceResult) error) error {
 	reexec := defaultTraceReexec
 	if config != nil && config.Reexec != nil {
 		reexec = *config.Reexec
 	}
 	statedb, release, err := api.backend.StateAtBlock(ctx, parent, reexec, nil, true, false)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer release()
 	// JS tracers have high overhead. In this case run a parallel
@@ -631,7 +742,16 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 	// in separate worker threads.
 	if config != nil && config.Tracer != nil && *config.Tracer != "" {
 		if isJS := DefaultDirectory.IsJS(*config.Tracer); isJS {
-			return api.traceBlockParallel(ctx, block, statedb, config)
+			results, err := api.traceBlockParallel(ctx, block, statedb, config)
+			if err != nil {
+				return err
+			}
+			for _, result := range results {
+				if err := yield(result); err != nil {
+					return err
+				}
+			}
+			return nil
 		}
 	}
 	// Native tracers have low overhead
@@ -641,7 +761,6 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 		feeCurrencyContext = core.GetFeeCurrencyContext(block.Header(), api.backend.ChainConfig(), statedb)
 		blockCtx           = core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil, api.backend.ChainConfig(), statedb, feeCurrencyContext)
 		signer             = types.MakeSigner(api.backend.ChainConfig(), block.Number(), block.Time())
-		results            = make([]*txTraceResult, len(txs))
 	)
 	if beaconRoot := block.BeaconRoot(); beaconRoot != nil {
 		vmenv := vm.NewEVM(blockCtx, vm.TxContext{}, statedb, api.backend.ChainConfig(), vm.Config{})
@@ -658,11 +777,25 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 		}
 		res, err := api.traceTx(ctx, tx, msg, txctx, blockCtx, statedb, config)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if err := yield(&txTraceResult{TxHash: tx.Hash(), Result: res}); err != nil {
+			return err
 		}
-		results[i] = &txTraceResult{TxHash: tx.Hash(), Result: res}
 	}
-	return results, nil
+	return nil
+}
+
+// streamTraceResults returns a stream of already computed trace results.
+func streamTraceResults(results []*txTraceResult) *rpc.Stream {
+	return rpc.NewStream(func(yield func(interface{}) error) error {
+		for _, result := range results {
+			if err := yield(result); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 // traceBlockParallel is for tracers that have a high overhead (read JS tracers). One thread
@@ -913,6 +1046,7 @@ func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *
 	if err != nil {
 		return nil, err
 	}
+	rpc.CacheableAt(ctx, blockHash, blockNumber)
 	tx, vmctx, statedb, release, err := api.backend.StateAtTransaction(ctx, block, int(index), reexec)
 	if err != nil {
 		return nil, err
@@ -1015,6 +1149,14 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 	)
 	if config != nil {
 		traceConfig = &config.TraceConfig
+
+		if deposit := config.Deposit; deposit != nil {
+			tx = args.ToDepositTransaction(deposit.SourceHash, (*big.Int)(deposit.Mint), deposit.IsSystemTx)
+			msg, err = core.TransactionToMessage(tx, types.LatestSigner(api.backend.ChainConfig()), vmctx.BaseFee, feeCurrencyContext.ExchangeRates)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 	return api.traceTx(ctx, tx, msg, new(Context), vmctx, statedb, traceConfig)
 }
@@ -1069,7 +1211,10 @@ func (api *API) traceTx(ctx context.Context, tx *types.Transaction, message *cor
 
 	// Call Prepare to clear out the statedb access list
 	statedb.SetTxContext(txctx.TxHash, txctx.TxIndex)
+	_, span := telemetry.Start(ctx, "evm.trace", telemetry.String("tx", txctx.TxHash.Hex()))
 	_, err = core.ApplyTransactionWithEVM(message, api.backend.ChainConfig(), new(core.GasPool).AddGas(message.GasLimit), statedb, vmctx.BlockNumber, txctx.BlockHash, tx, &usedGas, vmenv)
+	span.SetError(err)
+	span.End()
 	if err != nil {
 		return nil, fmt.Errorf("tracing failed: %w", err)
 	}
@@ -1082,7 +1227,7 @@ func APIs(backend Backend) []rpc.API {
 	return []rpc.API{
 		{
 			Namespace: "debug",
-			Service:   NewAPI(backend),
+			Service:   &streamingAPI{NewAPI(backend)},
 		},
 	}
 }
@@ -328,6 +328,8 @@ func TestTraceCall(t *testing.T) {
 	})
 
 	uintPtr := func(i int) *hexutil.Uint { x := hexutil.Uint(i); return &x }
+	unfunded := common.HexToAddress("0xdeadbeef")
+	depositGas := params.TxGas
 
 	defer backend.teardown()
 	api := NewAPI(backend)
@@ -456,6 +458,32 @@ func TestTraceCall(t *testing.T) {
 		{"pc":0,"op":"NUMBER","gas":24946984,"gasCost":2,"depth":1,"stack":[]},
 		{"pc":1,"op":"STOP","gas":24946982,"gasCost":0,"depth":1,"stack":["0x1337"]}]}`,
 		},
+		// Transfer from an unfunded account should fail
+		{
+			blockNumber: rpc.LatestBlockNumber,
+			call: ethapi.TransactionArgs{
+				From:  &unfunded,
+				To:    &accounts[0].addr,
+				Value: (*hexutil.Big)(big.NewInt(params.Ether)),
+			},
+			config:    nil,
+			expectErr: fmt.Errorf("tracing failed: insufficient funds for gas * price + value: address %s have 0 want 1000000000000000000", unfunded),
+		},
+		// Unless it's a deposit minting the transferred value
+		{
+			blockNumber: rpc.LatestBlockNumber,
+			call: ethapi.TransactionArgs{
+				From:  &unfunded,
+				To:    &accounts[0].addr,
+				Value: (*hexutil.Big)(big.NewInt(params.Ether)),
+				Gas:   (*hexutil.Uint64)(&depositGas),
+			},
+			config: &TraceCallConfig{
+				Deposit: &DepositCallConfig{Mint: (*hexutil.Big)(big.NewInt(params.Ether))},
+			},
+			expectErr: nil,
+			expect:    `{"gas":21000,"failed":false,"returnValue":"","structLogs":[]}`,
+		},
 	}
 	for i, testspec := range testSuite {
 		result, err := api.TraceCall(context.Background(), testspec.call, rpc.BlockNumberOrHash{BlockNumber: &testspec.blockNumber}, testspec.config)
@@ -623,6 +651,15 @@ func TestTraceBlock(t *testing.T) {
 	defer backend.chain.Stop()
 	api := NewAPI(backend)
 
+	// The block traces are streamed over RPC.
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("debug", APIs(backend)[0].Service); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
 	var testSuite = []struct {
 		blockNumber rpc.BlockNumber
 		config      *TraceConfig
@@ -676,6 +713,15 @@ func TestTraceBlock(t *testing.T) {
 		if string(have) != want {
 			t.Errorf("test %d, result mismatch, have\n%v\n, want\n%v\n", i, string(have), want)
 		}
+		// The streamed result must match.
+		var streamed json.RawMessage
+		if err := client.Call(&streamed, "debug_traceBlockByNumber", tc.blockNumber, tc.config); err != nil {
+			t.Errorf("test %d, want no stream error, have %v", i, err)
+			continue
+		}
+		if string(streamed) != want {
+			t.Errorf("test %d, streamed result mismatch, have\n%v\n, want\n%v\n", i, string(streamed), want)
+		}
 	}
 }
 
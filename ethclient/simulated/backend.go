@@ -135,7 +135,7 @@ func newWithNode(stack *node.Node, conf *eth.Config, blockPeriod uint64) (*Backe
 	filterSystem := filters.NewFilterSystem(backend.APIBackend, filters.Config{})
 	stack.RegisterAPIs([]rpc.API{{
 		Namespace: "eth",
-		Service:   filters.NewFilterAPI(filterSystem),
+		Service:   filters.NewStreamingFilterAPI(filterSystem),
 	}})
 	// Start the node
 	if err := stack.Start(); err != nil {
@@ -208,6 +208,37 @@ func (n *Backend) AdjustTime(adjustment time.Duration) error {
 	return n.beacon.AdjustTime(adjustment)
 }
 
+// CommitEmpty seals the given number of blocks without any transactions.
+func (n *Backend) CommitEmpty(blocks uint64) error {
+	return n.beacon.CommitEmpty(blocks)
+}
+
+// CommitTransactions seals a block including exactly the given transactions in
+// the given order, whether or not they were sent to the pool. If any of them
+// can't be included, no block is sealed.
+func (n *Backend) CommitTransactions(txs types.Transactions) (common.Hash, error) {
+	return n.beacon.CommitTransactions(txs)
+}
+
+// SetNextBlockTime sets the exact timestamp of the next committed block. It
+// must be after the timestamp of the current head.
+func (n *Backend) SetNextBlockTime(timestamp uint64) error {
+	return n.beacon.SetNextBlockTime(timestamp)
+}
+
+// Snapshot records the current state of the chain, returning an id which can
+// be passed to Revert.
+func (n *Backend) Snapshot() uint64 {
+	return n.beacon.Snapshot()
+}
+
+// Revert rewinds the chain to the state recorded by the snapshot with the given
+// id, removing all pending transactions. The snapshot and all snapshots taken
+// after it can't be reverted to afterwards.
+func (n *Backend) Revert(id uint64) error {
+	return n.beacon.Revert(id)
+}
+
 // Client returns a client that accesses the simulated chain.
 func (n *Backend) Client() Client {
 	return n.client
@@ -306,3 +306,163 @@ func TestAdjustTimeAfterFork(t *testing.T) {
 		t.Errorf("failed to build block on fork")
 	}
 }
+
+func TestCommitEmpty(t *testing.T) {
+	t.Parallel()
+	sim := simTestBackend(testAddr)
+	defer sim.Close()
+
+	client := sim.Client()
+	ctx := context.Background()
+
+	tx, err := newTx(sim, testKey)
+	if err != nil {
+		t.Fatalf("could not create transaction: %v", err)
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		t.Fatalf("sending transaction: %v", err)
+	}
+	if err := sim.CommitEmpty(3); err != nil {
+		t.Fatalf("committing empty blocks: %v", err)
+	}
+	for i := int64(1); i <= 3; i++ {
+		block, err := client.BlockByNumber(ctx, big.NewInt(i))
+		if err != nil {
+			t.Fatalf("block %d missing: %v", i, err)
+		}
+		if n := len(block.Transactions()); n != 0 {
+			t.Errorf("block %d: have %d txs, want none", i, n)
+		}
+	}
+	// The pending transaction is included in the next regular block
+	sim.Commit()
+	if receipt, err := client.TransactionReceipt(ctx, tx.Hash()); err != nil || receipt.BlockNumber.Uint64() != 4 {
+		t.Errorf("pending transaction not included in block 4: %v", err)
+	}
+}
+
+func TestCommitTransactions(t *testing.T) {
+	t.Parallel()
+	sim := simTestBackend(testAddr)
+	defer sim.Close()
+
+	client := sim.Client()
+	ctx := context.Background()
+
+	// A pooled transaction is ignored in favour of the given ones
+	pooled, err := newTx(sim, testKey)
+	if err != nil {
+		t.Fatalf("could not create transaction: %v", err)
+	}
+	if err := client.SendTransaction(ctx, pooled); err != nil {
+		t.Fatalf("sending transaction: %v", err)
+	}
+	key, _ := crypto.GenerateKey()
+	signer := types.LatestSignerForChainID(big.NewInt(1337))
+	fund := types.MustSignNewTx(testKey, signer, &types.DynamicFeeTx{
+		ChainID:   big.NewInt(1337),
+		Nonce:     0,
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: big.NewInt(10 * params.GWei),
+		Gas:       params.TxGas,
+		To:        ptr(crypto.PubkeyToAddress(key.PublicKey)),
+		Value:     big.NewInt(params.Ether / 1000),
+	})
+	spend := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+		ChainID:   big.NewInt(1337),
+		Nonce:     0,
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: big.NewInt(10 * params.GWei),
+		Gas:       params.TxGas,
+		To:        &testAddr,
+	})
+	// Transactions which can't be included in the given order fail the block
+	if _, err := sim.CommitTransactions(types.Transactions{spend, fund}); err == nil {
+		t.Fatal("block with unexecutable transaction order committed")
+	}
+	hash, err := sim.CommitTransactions(types.Transactions{fund, spend})
+	if err != nil {
+		t.Fatalf("committing transactions: %v", err)
+	}
+	block, err := client.BlockByHash(ctx, hash)
+	if err != nil {
+		t.Fatalf("committed block missing: %v", err)
+	}
+	if txs := block.Transactions(); len(txs) != 2 || txs[0].Hash() != fund.Hash() || txs[1].Hash() != spend.Hash() {
+		t.Fatalf("committed transactions mismatch: have %d txs", len(txs))
+	}
+}
+
+func TestSetNextBlockTime(t *testing.T) {
+	t.Parallel()
+	sim := simTestBackend(testAddr)
+	defer sim.Close()
+
+	client := sim.Client()
+	head, _ := client.HeaderByNumber(context.Background(), nil)
+
+	if err := sim.SetNextBlockTime(head.Time); err == nil {
+		t.Fatal("timestamp of head accepted")
+	}
+	next := head.Time + 1000
+	if err := sim.SetNextBlockTime(next); err != nil {
+		t.Fatalf("setting next block time: %v", err)
+	}
+	sim.Commit()
+	if head, _ = client.HeaderByNumber(context.Background(), nil); head.Time != next {
+		t.Errorf("block timestamp mismatch: have %d, want %d", head.Time, next)
+	}
+	// Only the next block is affected
+	sim.Commit()
+	if head, _ = client.HeaderByNumber(context.Background(), nil); head.Time <= next {
+		t.Errorf("block timestamp not after previous one: have %d, previous %d", head.Time, next)
+	}
+}
+
+func TestSnapshotRevert(t *testing.T) {
+	t.Parallel()
+	sim := simTestBackend(testAddr)
+	defer sim.Close()
+
+	client := sim.Client()
+	ctx := context.Background()
+
+	sim.Commit()
+	first := sim.Snapshot()
+	balance, _ := client.BalanceAt(ctx, testAddr, nil)
+
+	tx, err := newTx(sim, testKey)
+	if err != nil {
+		t.Fatalf("could not create transaction: %v", err)
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		t.Fatalf("sending transaction: %v", err)
+	}
+	sim.Commit()
+	second := sim.Snapshot()
+	sim.Commit()
+
+	if err := sim.Revert(first); err != nil {
+		t.Fatalf("reverting snapshot: %v", err)
+	}
+	if num, _ := client.BlockNumber(ctx); num != 1 {
+		t.Errorf("head mismatch after revert: have %d, want 1", num)
+	}
+	if have, _ := client.BalanceAt(ctx, testAddr, nil); have.Cmp(balance) != 0 {
+		t.Errorf("balance mismatch after revert: have %v, want %v", have, balance)
+	}
+	if _, err := client.TransactionReceipt(ctx, tx.Hash()); err == nil {
+		t.Error("reverted transaction still included")
+	}
+	// Snapshots taken after the reverted one are discarded
+	if err := sim.Revert(second); err == nil {
+		t.Error("reverted to discarded snapshot")
+	}
+	// The reverted transaction is not pending anymore
+	sim.Commit()
+	if _, err := client.TransactionReceipt(ctx, tx.Hash()); err == nil {
+		t.Error("reverted transaction included again")
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,98 @@
+package celoapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/contracts"
+)
+
+// defaultGasReportBlocks is the number of blocks covered by a fee currency
+// gas report if the caller does not specify it.
+const defaultGasReportBlocks = 100
+
+// FeeCurrencyAPI provides fee currency related information under the celo
+// namespace.
+type FeeCurrencyAPI struct {
+	eth Ethereum
+}
+
+func NewFeeCurrencyAPI(e Ethereum) *FeeCurrencyAPI {
+	return &FeeCurrencyAPI{eth: e}
+}
+
+// FeeCurrencyGasReport summarises the gas used for debiting and crediting
+// fees in one fee currency and compares it to the intrinsic gas configured
+// in the FeeCurrencyDirectory.
+type FeeCurrencyGasReport struct {
+	FeeCurrency  common.Address  `json:"feeCurrency"`
+	IntrinsicGas *hexutil.Uint64 `json:"intrinsicGas"` // nil if the currency is no longer registered
+	Count        hexutil.Uint64  `json:"count"`
+	Min          hexutil.Uint64  `json:"min"`
+	Avg          hexutil.Uint64  `json:"avg"`
+	Max          hexutil.Uint64  `json:"max"`
+	Overruns     hexutil.Uint64  `json:"overruns"`
+}
+
+// FeeCurrencyGasReport returns, for every registered fee currency and every
+// fee currency used within the last `blocks` blocks, the minimum, average and
+// maximum gas used by fee debit+credit as observed by this node, together
+// with the intrinsic gas currently configured in the FeeCurrencyDirectory.
+func (api *FeeCurrencyAPI) FeeCurrencyGasReport(ctx context.Context, blocks *hexutil.Uint64) ([]*FeeCurrencyGasReport, error) {
+	n := uint64(defaultGasReportBlocks)
+	if blocks != nil {
+		n = uint64(*blocks)
+	}
+	if n == 0 || n > contracts.FeeCurrencyGasStatsBlocks {
+		return nil, fmt.Errorf("blocks must be between 1 and %d", contracts.FeeCurrencyGasStatsBlocks)
+	}
+	state, err := api.eth.BlockChain().State()
+	if err != nil {
+		return nil, fmt.Errorf("retrieve HEAD blockchain state: %w", err)
+	}
+	feeContext, err := contracts.GetFeeCurrencyContext(&contracts.CeloBackend{
+		ChainConfig: api.eth.BlockChain().Config(),
+		State:       state,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("retrieve fee currency context: %w", err)
+	}
+
+	head := api.eth.BlockChain().CurrentBlock().Number.Uint64()
+	var from uint64
+	if head+1 > n {
+		from = head + 1 - n
+	}
+	stats := contracts.FeeCurrencyGasReport(from, head, api.eth.BlockChain().GetCanonicalHash)
+
+	reports := make(map[common.Address]*FeeCurrencyGasReport)
+	for currency, gas := range feeContext.IntrinsicGasCosts {
+		gas := hexutil.Uint64(gas)
+		reports[currency] = &FeeCurrencyGasReport{FeeCurrency: currency, IntrinsicGas: &gas}
+	}
+	for currency, s := range stats {
+		report, ok := reports[currency]
+		if !ok {
+			report = &FeeCurrencyGasReport{FeeCurrency: currency}
+			reports[currency] = report
+		}
+		report.Count = hexutil.Uint64(s.Count)
+		report.Min = hexutil.Uint64(s.Min)
+		report.Avg = hexutil.Uint64(s.Avg())
+		report.Max = hexutil.Uint64(s.Max)
+		report.Overruns = hexutil.Uint64(s.Overruns)
+	}
+
+	result := make([]*FeeCurrencyGasReport, 0, len(reports))
+	for _, report := range reports {
+		result = append(result, report)
+	}
+	slices.SortFunc(result, func(a, b *FeeCurrencyGasReport) int {
+		return bytes.Compare(a.FeeCurrency[:], b.FeeCurrency[:])
+	})
+	return result, nil
+}
@@ -38,11 +38,13 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/gasestimator"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/ethereum/go-ethereum/internal/telemetry"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/params"
@@ -282,6 +284,80 @@ func (api *TxPoolAPI) Inspect() map[string]map[string]map[string]string {
 	return content
 }
 
+// RPCTxLifecycleEvent is a transaction lifecycle event as returned over RPC.
+type RPCTxLifecycleEvent struct {
+	Hash        common.Hash     `json:"hash"`
+	Type        string          `json:"type"`
+	Time        hexutil.Uint64  `json:"time"`
+	Reason      string          `json:"reason,omitempty"`
+	ReplacedBy  *common.Hash    `json:"replacedBy,omitempty"`
+	BlockHash   *common.Hash    `json:"blockHash,omitempty"`
+	BlockNumber *hexutil.Uint64 `json:"blockNumber,omitempty"`
+}
+
+func newRPCTxLifecycleEvent(ev txpool.TxLifecycleEvent) *RPCTxLifecycleEvent {
+	result := &RPCTxLifecycleEvent{
+		Hash:   ev.Hash,
+		Type:   string(ev.Type),
+		Time:   hexutil.Uint64(ev.Time.Unix()),
+		Reason: string(ev.Reason),
+	}
+	switch ev.Type {
+	case txpool.TxEventReplaced:
+		result.ReplacedBy = &ev.ReplacedBy
+	case txpool.TxEventIncluded:
+		result.BlockHash = &ev.BlockHash
+		result.BlockNumber = (*hexutil.Uint64)(&ev.BlockNumber)
+	}
+	return result
+}
+
+// TxHistory returns the lifecycle events recorded for a transaction by the
+// pool, oldest first. Only recently seen transactions are retained.
+func (api *TxPoolAPI) TxHistory(hash common.Hash) []*RPCTxLifecycleEvent {
+	events := api.b.TxPoolHistory(hash)
+	result := make([]*RPCTxLifecycleEvent, len(events))
+	for i, ev := range events {
+		result[i] = newRPCTxLifecycleEvent(ev)
+	}
+	return result
+}
+
+// TxLifecycle creates a subscription that is triggered for each lifecycle
+// event of a transaction in the pool. If hashes are given, only events of
+// these transactions are sent.
+func (api *TxPoolAPI) TxLifecycle(ctx context.Context, hashes []common.Hash) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	watched := make(map[common.Hash]struct{}, len(hashes))
+	for _, hash := range hashes {
+		watched[hash] = struct{}{}
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan txpool.TxLifecycleEvent, 128)
+		sub := api.b.SubscribeTxLifecycleEvent(events)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				if _, ok := watched[ev.Hash]; ok || len(watched) == 0 {
+					notifier.Notify(rpcSub.ID, newRPCTxLifecycleEvent(ev))
+				}
+			case <-rpcSub.Err():
+				return
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
 // EthereumAccountAPI provides an API to access accounts managed by this node.
 // It offers only methods that can retrieve accounts.
 type EthereumAccountAPI struct {
@@ -890,6 +966,7 @@ func (api *BlockChainAPI) GetBlockByNumber(ctx context.Context, number rpc.Block
 func (api *BlockChainAPI) GetBlockByHash(ctx context.Context, hash common.Hash, fullTx bool) (map[string]interface{}, error) {
 	block, err := api.b.BlockByHash(ctx, hash)
 	if block != nil {
+		rpc.CacheableAt(ctx, hash, block.NumberU64())
 		return api.rpcMarshalBlock(ctx, block, true, fullTx)
 	}
 	return nil, err
@@ -1220,7 +1297,13 @@ func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.S
 
 	// Execute the message.
 	gp := new(core.GasPool).AddGas(math.MaxUint64)
+	_, span := telemetry.Start(ctx, "evm.execute", telemetry.Uint64("gas", msg.GasLimit))
 	result, err := core.ApplyMessage(evm, msg, gp)
+	if result != nil {
+		span.SetAttributes(telemetry.Uint64("gasUsed", result.UsedGas))
+	}
+	span.SetError(err)
+	span.End()
 	if err := state.Error(); err != nil {
 		return nil, err
 	}
@@ -1284,6 +1367,10 @@ func (api *BlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockN
 	if len(result.Revert()) > 0 {
 		return nil, newRevertError(result.Revert())
 	}
+	// Calls at a block hash always yield the same result
+	if blockNrOrHash.BlockHash != nil {
+		rpc.CacheableAt(ctx, header.Hash(), header.Number.Uint64())
+	}
 	return result.Return(), result.Err
 }
 
@@ -1983,6 +2070,7 @@ func (api *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash commo
 	receipt := receipts[index]
 
 	// Derive the sender.
+	rpc.CacheableAt(ctx, blockHash, blockNumber)
 	signer := types.MakeSigner(api.b.ChainConfig(), header.Number, header.Time)
 	return marshalReceipt(receipt, blockHash, blockNumber, signer, tx, int(index), api.b.ChainConfig()), nil
 }
@@ -276,9 +276,7 @@ func TestNewRPCTransactionDynamicFee(t *testing.T) {
 			checkTxFields(t, signed, rpcTx, s, blockhash, blockNumber, transactionIndex, overrides)
 		})
 
-		// TODO unskip this when cip 66 txs are enabled currently they are not supporeted in the celo signer.
 		t.Run("CeloDenominatedTx", func(t *testing.T) {
-			t.Skip("CeloDenominatedTx is currently not supported in the celo signer")
 			tx := types.NewTx(&types.CeloDenominatedTx{
 				ChainID:             config.ChainID,
 				Nonce:               nonce,
@@ -426,6 +424,7 @@ func allEnabledChainConfig() *params.ChainConfig {
 		EcotoneTime:         &zeroTime,
 		FjordTime:           &zeroTime,
 		Cel2Time:            &zeroTime,
+		Cip66Time:           &zeroTime,
 		GingerbreadBlock:    big.NewInt(0),
 	}
 }
@@ -210,8 +210,16 @@ func (args *TransactionArgs) setFeeDefaults(ctx context.Context, b CeloBackend)
 	// for more information.
 	eip1559ParamsSet := args.MaxFeePerGas != nil && args.MaxPriorityFeePerGas != nil
 
-	if args.MaxFeeInFeeCurrency != nil && args.FeeCurrency == nil {
-		return errors.New("feeCurrency must be set when maxFeeInFeeCurrency is given")
+	if args.MaxFeeInFeeCurrency != nil {
+		if args.FeeCurrency == nil {
+			return errors.New("feeCurrency must be set when maxFeeInFeeCurrency is given")
+		}
+		if !b.ChainConfig().IsCip66(head.Time) {
+			return errors.New("maxFeeInFeeCurrency is not valid before CIP-66 is active")
+		}
+		if args.GasPrice != nil {
+			return errors.New("both gasPrice and maxFeeInFeeCurrency specified")
+		}
 	}
 	// Sanity check the EIP-1559 fee parameters if present.
 	if args.GasPrice == nil && eip1559ParamsSet {
@@ -534,6 +542,22 @@ func (args *TransactionArgs) ToMessage(baseFee *big.Int, exchangeRates common.Ex
 	}
 }
 
+// ToDepositTransaction converts the arguments to a deposit transaction, minting
+// the given amount to the sender first.
+// This assumes that CallDefaults has been called.
+func (args *TransactionArgs) ToDepositTransaction(sourceHash common.Hash, mint *big.Int, isSystemTx bool) *types.Transaction {
+	return types.NewTx(&types.DepositTx{
+		SourceHash:          sourceHash,
+		From:                args.from(),
+		To:                  args.To,
+		Mint:                mint,
+		Value:               (*big.Int)(args.Value),
+		Gas:                 uint64(*args.Gas),
+		IsSystemTransaction: isSystemTx,
+		Data:                args.data(),
+	})
+}
+
 // ToTransaction converts the arguments to a transaction.
 // This assumes that setDefaults has been called.
 func (args *TransactionArgs) ToTransaction() *types.Transaction {
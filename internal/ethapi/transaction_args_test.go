@@ -32,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -248,13 +249,6 @@ func TestSetFeeDefaults(t *testing.T) {
 			nil,
 		},
 		// CIP-66
-		{
-			"CIP-66 transaction, maxPriorityFeePerGas gets set in non-converted value",
-			"cancun",
-			&TransactionArgs{MaxFeePerGas: maxFee, MaxFeeInFeeCurrency: fortytwo, FeeCurrency: &feeCurrency},
-			&TransactionArgs{MaxFeePerGas: maxFee, MaxPriorityFeePerGas: fortytwo, MaxFeeInFeeCurrency: fortytwo, FeeCurrency: &feeCurrency},
-			nil,
-		},
 		{
 			"set maxFeeInFeeCurrency without feeCurrency",
 			"cancun",
@@ -288,6 +282,70 @@ func TestSetFeeDefaults(t *testing.T) {
 	}
 }
 
+// TestSetFeeDefaultsCip66 tests the fee defaults of CIP-66 transactions, which
+// need a chain config with the CIP-66 fork scheduled.
+func TestSetFeeDefaultsCip66(t *testing.T) {
+	var (
+		b           = newCeloBackendMock()
+		fortytwo    = (*hexutil.Big)(big.NewInt(42))
+		maxFee      = (*hexutil.Big)(new(big.Int).Add(new(big.Int).Mul(b.current.BaseFee, big.NewInt(2)), fortytwo.ToInt()))
+		feeCurrency = common.BigToAddress(big.NewInt(42))
+		cip66Time   = uint64(600)
+	)
+	b.config.Cel2Time = &cip66Time
+	b.config.Cip66Time = &cip66Time
+
+	tests := []struct {
+		name string
+		fork string // options: legacy, london, cancun
+		in   *TransactionArgs
+		want *TransactionArgs
+		err  error
+	}{
+		{
+			"CIP-66 transaction, maxPriorityFeePerGas gets set in non-converted value",
+			"cancun",
+			&TransactionArgs{MaxFeePerGas: maxFee, MaxFeeInFeeCurrency: fortytwo, FeeCurrency: &feeCurrency},
+			&TransactionArgs{MaxFeePerGas: maxFee, MaxPriorityFeePerGas: fortytwo, MaxFeeInFeeCurrency: fortytwo, FeeCurrency: &feeCurrency},
+			nil,
+		},
+		{
+			"set maxFeeInFeeCurrency before CIP-66",
+			"london",
+			&TransactionArgs{MaxFeePerGas: maxFee, MaxPriorityFeePerGas: fortytwo, MaxFeeInFeeCurrency: fortytwo, FeeCurrency: &feeCurrency},
+			nil,
+			errors.New("maxFeeInFeeCurrency is not valid before CIP-66 is active"),
+		},
+		{
+			"set maxFeeInFeeCurrency with gasPrice",
+			"cancun",
+			&TransactionArgs{GasPrice: fortytwo, MaxFeeInFeeCurrency: fortytwo, FeeCurrency: &feeCurrency},
+			nil,
+			errors.New("both gasPrice and maxFeeInFeeCurrency specified"),
+		},
+	}
+	for i, test := range tests {
+		if err := b.setFork(test.fork); err != nil {
+			t.Fatalf("failed to set fork: %v", err)
+		}
+		got := test.in
+		err := got.setFeeDefaults(context.Background(), b)
+		if err != nil {
+			if test.err == nil {
+				t.Fatalf("test %d (%s): unexpected error: %s", i, test.name, err)
+			} else if err.Error() != test.err.Error() {
+				t.Fatalf("test %d (%s): unexpected error: (got: %s, want: %s)", i, test.name, err, test.err)
+			}
+			continue
+		} else if test.err != nil {
+			t.Fatalf("test %d (%s): expected error: %s", i, test.name, test.err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Fatalf("test %d (%s): did not fill defaults as expected: (got: %v, want: %v)", i, test.name, got, test.want)
+		}
+	}
+}
+
 type celoBackendMock struct {
 	*backendMock
 }
@@ -463,8 +521,13 @@ func (b *backendMock) TxPoolContent() (map[common.Address][]*types.Transaction,
 func (b *backendMock) TxPoolContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction) {
 	return nil, nil
 }
-func (b *backendMock) SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription      { return nil }
+func (b *backendMock) SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription { return nil }
+func (b *backendMock) TxPoolHistory(hash common.Hash) []txpool.TxLifecycleEvent        { return nil }
+func (b *backendMock) SubscribeTxLifecycleEvent(chan<- txpool.TxLifecycleEvent) event.Subscription {
+	return nil
+}
 func (b *backendMock) BloomStatus() (uint64, uint64)                                        { return 0, 0 }
+func (b *backendMock) LogIndexStatus() (uint64, uint64)                                     { return 0, 0 }
 func (b *backendMock) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {}
 func (b *backendMock) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription         { return nil }
 func (b *backendMock) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription {
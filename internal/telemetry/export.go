@@ -0,0 +1,289 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	exportInterval  = time.Second     // Maximum delay before ended spans are exported
+	exportBatchSize = 512             // Maximum number of spans per export request
+	exportQueueSize = 8192            // Number of ended spans queued for export, further spans are dropped
+	exportTimeout   = 5 * time.Second // Timeout of export requests to the collector
+)
+
+var (
+	exportedSpansMeter = metrics.NewRegisteredMeter("telemetry/spans/exported", nil)
+	droppedSpansMeter  = metrics.NewRegisteredMeter("telemetry/spans/dropped", nil)
+)
+
+// Config contains the settings of tracing.
+type Config struct {
+	SampleRatio    float64 // Fraction of new traces which are sampled, between 0 and 1
+	RemoteSampling bool    // Whether the sampled flag of remote traceparent headers decides sampling, instead of SampleRatio
+	File           string  // File to append spans to as OTLP/JSON lines
+	Endpoint       string  // OTLP/HTTP collector URL to post spans to, e.g. http://localhost:4318/v1/traces
+	ServiceName    string  // Name of the service reported with the spans
+}
+
+// exporter batches ended spans and exports them to the configured file and
+// collector.
+type exporter struct {
+	config Config
+	file   *os.File
+	client *http.Client
+
+	spans chan *Span
+	quit  chan struct{}
+	done  chan struct{}
+}
+
+func newExporter(config Config) (*exporter, error) {
+	if config.File == "" && config.Endpoint == "" {
+		return nil, errors.New("no trace file or collector endpoint configured")
+	}
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return nil, fmt.Errorf("invalid sample ratio %v", config.SampleRatio)
+	}
+	if config.ServiceName == "" {
+		config.ServiceName = "geth"
+	}
+	e := &exporter{
+		config: config,
+		client: &http.Client{Timeout: exportTimeout},
+		spans:  make(chan *Span, exportQueueSize),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if config.File != "" {
+		file, err := os.OpenFile(config.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		e.file = file
+	}
+	go e.loop()
+	return e, nil
+}
+
+// sample decides whether a new trace is sampled.
+func (e *exporter) sample() bool {
+	return e.config.SampleRatio >= 1 || rand.Float64() < e.config.SampleRatio
+}
+
+// add queues an ended span for export.
+func (e *exporter) add(span *Span) {
+	select {
+	case e.spans <- span:
+	default:
+		droppedSpansMeter.Mark(1)
+	}
+}
+
+// close exports all queued spans and stops the exporter.
+func (e *exporter) close() {
+	close(e.quit)
+	<-e.done
+	if e.file != nil {
+		e.file.Close()
+	}
+}
+
+func (e *exporter) loop() {
+	defer close(e.done)
+
+	var (
+		ticker = time.NewTicker(exportInterval)
+		batch  []*Span
+	)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case span := <-e.spans:
+			if batch = append(batch, span); len(batch) >= exportBatchSize {
+				e.export(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				e.export(batch)
+				batch = nil
+			}
+		case <-e.quit:
+			for {
+				select {
+				case span := <-e.spans:
+					batch = append(batch, span)
+				default:
+					if len(batch) > 0 {
+						e.export(batch)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// export writes a batch of spans to the file and the collector.
+func (e *exporter) export(batch []*Span) {
+	data, err := json.Marshal(e.encode(batch))
+	if err != nil {
+		log.Warn("Failed to encode trace spans", "err", err)
+		return
+	}
+	if e.file != nil {
+		if _, err := e.file.Write(append(data, '\n')); err != nil {
+			log.Warn("Failed to write trace spans", "file", e.config.File, "err", err)
+		}
+	}
+	if e.config.Endpoint != "" {
+		resp, err := e.client.Post(e.config.Endpoint, "application/json", bytes.NewReader(data))
+		if err != nil {
+			log.Warn("Failed to export trace spans", "endpoint", e.config.Endpoint, "err", err)
+		} else {
+			resp.Body.Close()
+			if resp.StatusCode/100 != 2 {
+				log.Warn("Trace collector rejected spans", "endpoint", e.config.Endpoint, "status", resp.Status)
+			}
+		}
+	}
+	exportedSpansMeter.Mark(int64(len(batch)))
+}
+
+// The types below are the OTLP/JSON encoding of an ExportTraceServiceRequest.
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            *otlpStatus     `json:"status,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	otlpSpanKindInternal = 1
+	otlpSpanKindServer   = 2
+	otlpStatusError      = 2
+)
+
+func (e *exporter) encode(batch []*Span) *otlpRequest {
+	spans := make([]otlpSpan, 0, len(batch))
+	for _, s := range batch {
+		s.lock.Lock()
+		span := otlpSpan{
+			TraceID:           s.traceID.String(),
+			SpanID:            s.id.String(),
+			Name:              s.name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+		}
+		if s.parent != (SpanID{}) {
+			span.ParentSpanID = s.parent.String()
+		} else {
+			span.Kind = otlpSpanKindServer
+		}
+		for _, attr := range s.attrs {
+			span.Attributes = append(span.Attributes, encodeAttribute(attr))
+		}
+		if s.err != nil {
+			span.Status = &otlpStatus{Code: otlpStatusError, Message: s.err.Error()}
+		}
+		s.lock.Unlock()
+		spans = append(spans, span)
+	}
+	return &otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: []otlpAttribute{encodeAttribute(String("service.name", e.config.ServiceName))}},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: "github.com/ethereum/go-ethereum"}, Spans: spans}},
+	}}}
+}
+
+func encodeAttribute(attr Attribute) otlpAttribute {
+	var value otlpValue
+	switch v := attr.Value.(type) {
+	case string:
+		value.StringValue = &v
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		value.IntValue = &s
+	case uint64:
+		s := strconv.FormatUint(v, 10)
+		value.IntValue = &s
+	case bool:
+		value.BoolValue = &v
+	default:
+		s := fmt.Sprint(v)
+		value.StringValue = &s
+	}
+	return otlpAttribute{Key: attr.Key, Value: value}
+}
@@ -0,0 +1,279 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package telemetry implements request-scoped tracing spans, exported in the
+// OpenTelemetry protocol (OTLP) JSON encoding.
+//
+// Traces are started by the RPC server for sampled requests. Code serving the request
+// creates child spans with Start, which does nothing unless the request is traced:
+//
+//	ctx, span := telemetry.Start(ctx, "ethapi.doCall", telemetry.Uint64("block", number))
+//	defer span.End()
+//
+// All methods of Span are safe to call on a nil span.
+package telemetry
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// exp is the active exporter, nil if tracing is disabled.
+var exp atomic.Pointer[exporter]
+
+// Enable starts tracing with the given config, replacing the active config.
+func Enable(config Config) error {
+	e, err := newExporter(config)
+	if err != nil {
+		return err
+	}
+	if old := exp.Swap(e); old != nil {
+		old.close()
+	}
+	return nil
+}
+
+// Close stops tracing, exporting all ended spans.
+func Close() {
+	if e := exp.Swap(nil); e != nil {
+		e.close()
+	}
+}
+
+// Enabled reports whether tracing is enabled.
+func Enabled() bool {
+	return exp.Load() != nil
+}
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+func (id TraceID) String() string { return hex.EncodeToString(id[:]) }
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+func (id SpanID) String() string { return hex.EncodeToString(id[:]) }
+
+// Attribute is a key-value pair describing a span.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// String returns a string attribute.
+func String(key, value string) Attribute { return Attribute{key, value} }
+
+// Int64 returns an integer attribute.
+func Int64(key string, value int64) Attribute { return Attribute{key, value} }
+
+// Uint64 returns an unsigned integer attribute.
+func Uint64(key string, value uint64) Attribute { return Attribute{key, value} }
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attribute { return Attribute{key, value} }
+
+// Span is a timed operation within a trace.
+type Span struct {
+	exporter *exporter
+	traceID  TraceID
+	id       SpanID
+	parent   SpanID
+	name     string
+	start    time.Time
+
+	lock  sync.Mutex
+	end   time.Time
+	attrs []Attribute
+	err   error
+}
+
+type spanContextKey struct{}
+
+// unsampledContextKey marks contexts whose trace was declined by sampling, so that
+// nested calls of StartTrace don't sample the same request again.
+type unsampledContextKey struct{}
+
+// SpanFromContext returns the span of the context, or nil if it is not traced.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+// Start starts a child span of the span in the context. If the context is not
+// traced, no span is started and nil is returned.
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+	return newSpan(ctx, parent.exporter, parent.traceID, parent.id, name, attrs)
+}
+
+// StartTrace starts a span, continuing the trace of the context if there is one.
+// Otherwise a new trace is started if it is sampled. If the context was already
+// declined by sampling, no span is started.
+func StartTrace(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	if parent := SpanFromContext(ctx); parent != nil {
+		return newSpan(ctx, parent.exporter, parent.traceID, parent.id, name, attrs)
+	}
+	if ctx.Value(unsampledContextKey{}) != nil {
+		return ctx, nil
+	}
+	e := exp.Load()
+	if e == nil {
+		return ctx, nil
+	}
+	if !e.sample() {
+		return unsampled(ctx), nil
+	}
+	var id TraceID
+	randomID(id[:])
+	return newSpan(ctx, e, id, SpanID{}, name, attrs)
+}
+
+// ContinueTrace starts a span continuing the remote trace of a W3C traceparent
+// header. The remote sampling decision is only honoured if remote sampling is
+// configured, otherwise the trace is sampled like a new one. If the header is
+// empty or invalid, a new trace is started as by StartTrace.
+func ContinueTrace(ctx context.Context, traceparent string, name string, attrs ...Attribute) (context.Context, *Span) {
+	e := exp.Load()
+	if e == nil {
+		return ctx, nil
+	}
+	traceID, parent, sampled, ok := parseTraceparent(traceparent)
+	if !ok {
+		return StartTrace(ctx, name, attrs...)
+	}
+	if !e.config.RemoteSampling {
+		sampled = e.sample()
+	}
+	if !sampled {
+		return unsampled(ctx), nil
+	}
+	return newSpan(ctx, e, traceID, parent, name, attrs)
+}
+
+// unsampled marks the context as declined by sampling.
+func unsampled(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unsampledContextKey{}, struct{}{})
+}
+
+func newSpan(ctx context.Context, e *exporter, traceID TraceID, parent SpanID, name string, attrs []Attribute) (context.Context, *Span) {
+	span := &Span{
+		exporter: e,
+		traceID:  traceID,
+		parent:   parent,
+		name:     name,
+		start:    time.Now(),
+		attrs:    attrs,
+	}
+	randomID(span.id[:])
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// TraceID returns the ID of the span's trace.
+func (s *Span) TraceID() TraceID {
+	if s == nil {
+		return TraceID{}
+	}
+	return s.traceID
+}
+
+// Traceparent returns the W3C traceparent header value identifying the span.
+func (s *Span) Traceparent() string {
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", s.traceID, s.id)
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// SetError marks the span as failed with the given error, if it is not nil.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.err = err
+}
+
+// End ends the span and queues it for export. Only the first call has an effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	if !s.end.IsZero() {
+		s.lock.Unlock()
+		return
+	}
+	s.end = time.Now()
+	s.lock.Unlock()
+
+	s.exporter.add(s)
+}
+
+// parseTraceparent parses a version 00 W3C traceparent header.
+func parseTraceparent(header string) (traceID TraceID, parent SpanID, sampled bool, ok bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) != 4 || parts[0] != "00" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return traceID, parent, false, false
+	}
+	if _, err := hex.Decode(traceID[:], []byte(parts[1])); err != nil || traceID == (TraceID{}) {
+		return traceID, parent, false, false
+	}
+	if _, err := hex.Decode(parent[:], []byte(parts[2])); err != nil || parent == (SpanID{}) {
+		return traceID, parent, false, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return traceID, parent, false, false
+	}
+	return traceID, parent, flags[0]&1 == 1, true
+}
+
+// randomID fills the ID with random, non-zero bytes.
+func randomID(id []byte) {
+	for {
+		for i := range id {
+			id[i] = byte(rand.Intn(256))
+		}
+		for _, b := range id {
+			if b != 0 {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package telemetry
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		header  string
+		sampled bool
+		ok      bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", false, true},
+		{"", false, false},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false, false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01", false, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902bx-01", false, false},
+	}
+	for _, test := range tests {
+		traceID, parent, sampled, ok := parseTraceparent(test.header)
+		if ok != test.ok || sampled != test.sampled {
+			t.Errorf("%q: got sampled=%v ok=%v, want sampled=%v ok=%v", test.header, sampled, ok, test.sampled, test.ok)
+			continue
+		}
+		if ok && (traceID.String() != test.header[3:35] || parent.String() != test.header[36:52]) {
+			t.Errorf("%q: wrong IDs %v %v", test.header, traceID, parent)
+		}
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	ctx, span := StartTrace(context.Background(), "root")
+	if span != nil || SpanFromContext(ctx) != nil {
+		t.Fatal("span started while disabled")
+	}
+	// Nil spans must be usable.
+	span.SetAttributes(String("key", "value"))
+	span.SetError(errors.New("fail"))
+	span.End()
+}
+
+func TestExport(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "spans.json")
+	if err := Enable(Config{SampleRatio: 1, RemoteSampling: true, File: file}); err != nil {
+		t.Fatal(err)
+	}
+	// Spans are only started within traces.
+	if _, span := Start(context.Background(), "orphan"); span != nil {
+		t.Fatal("child span started without a trace")
+	}
+	remote := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx, root := ContinueTrace(context.Background(), remote, "root", String("method", "eth_call"))
+	_, child := Start(ctx, "child", Uint64("number", 5), Bool("ok", true))
+	child.SetError(errors.New("execution reverted"))
+	child.End()
+	root.End()
+	root.End()
+	if _, span := ContinueTrace(context.Background(), remote[:len(remote)-1]+"0", "unsampled"); span != nil {
+		t.Fatal("span started for unsampled remote trace")
+	}
+	Close()
+
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var spans []otlpSpan
+	for lines := bufio.NewScanner(f); lines.Scan(); {
+		var req otlpRequest
+		if err := json.Unmarshal(lines.Bytes(), &req); err != nil {
+			t.Fatal(err)
+		}
+		for _, rs := range req.ResourceSpans {
+			if name := *rs.Resource.Attributes[0].Value.StringValue; name != "geth" {
+				t.Errorf("wrong service name %q", name)
+			}
+			for _, ss := range rs.ScopeSpans {
+				spans = append(spans, ss.Spans...)
+			}
+		}
+	}
+	if len(spans) != 2 {
+		t.Fatalf("wrong number of exported spans: %d", len(spans))
+	}
+	c, r := spans[0], spans[1]
+	if r.Name != "root" || r.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || r.ParentSpanID != "00f067aa0ba902b7" {
+		t.Errorf("wrong root span: %+v", r)
+	}
+	if c.Name != "child" || c.TraceID != r.TraceID || c.ParentSpanID != r.SpanID {
+		t.Errorf("wrong child span: %+v", c)
+	}
+	if c.Status == nil || c.Status.Code != otlpStatusError || c.Status.Message != "execution reverted" {
+		t.Errorf("wrong child status: %+v", c.Status)
+	}
+	if len(c.Attributes) != 2 || *c.Attributes[0].Value.IntValue != "5" || !*c.Attributes[1].Value.BoolValue {
+		t.Errorf("wrong child attributes: %+v", c.Attributes)
+	}
+}
+
+func TestSampling(t *testing.T) {
+	defer Close()
+
+	remote := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	file := filepath.Join(t.TempDir(), "spans.json")
+
+	// Remote sampling flags are ignored unless configured.
+	if err := Enable(Config{SampleRatio: 0, File: file}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, span := ContinueTrace(context.Background(), remote, "rpc.http")
+	if span != nil {
+		t.Fatal("span started for remote trace sampled by the caller")
+	}
+	// Declined requests are not sampled again.
+	if err := Enable(Config{SampleRatio: 1, File: file}); err != nil {
+		t.Fatal(err)
+	}
+	if _, span := StartTrace(ctx, "eth_call"); span != nil {
+		t.Fatal("span started for request declined by sampling")
+	}
+	// Remote traces sampled locally continue the remote trace.
+	_, span = ContinueTrace(context.Background(), remote[:len(remote)-1]+"0", "rpc.http")
+	if span == nil {
+		t.Fatal("no span started for remote trace")
+	}
+	if span.TraceID().String() != remote[3:35] {
+		t.Errorf("wrong trace ID %v", span.TraceID())
+	}
+}
@@ -190,6 +190,16 @@ web3._extend({
 			name: 'stopWS',
 			call: 'admin_stopWS'
 		}),
+		new web3._extend.Method({
+			name: 'exportTxPool',
+			call: 'admin_exportTxPool',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'importTxPool',
+			call: 'admin_importTxPool',
+			params: 1
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -539,6 +549,21 @@ web3._extend({
 			inputFormatter: [web3._extend.formatters.inputCallFormatter, web3._extend.formatters.inputBlockNumberFormatter, null],
 			outputFormatter: web3._extend.utils.toDecimal
 		}),
+		new web3._extend.Method({
+			name: 'sendBundle',
+			call: 'eth_sendBundle',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'callBundle',
+			call: 'eth_callBundle',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'sendRawTransactionConditional',
+			call: 'eth_sendRawTransactionConditional',
+			params: 2
+		}),
 		new web3._extend.Method({
 			name: 'submitTransaction',
 			call: 'eth_submitTransaction',
@@ -665,6 +690,11 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.utils.fromDecimal]
 		}),
+		new web3._extend.Method({
+			name: 'buildReport',
+			call: 'miner_buildReport',
+			params: 1
+		}),
 	],
 	properties: []
 });
@@ -780,6 +810,11 @@ web3._extend({
 			call: 'txpool_contentFrom',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'txHistory',
+			call: 'txpool_txHistory',
+			params: 1,
+		}),
 	]
 });
 `
@@ -879,11 +914,44 @@ web3._extend({
 			call: 'dev_addWithdrawal',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'addDeposit',
+			call: 'dev_addDeposit',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'setFeeRecipient',
 			call: 'dev_setFeeRecipient',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'commitEmpty',
+			call: 'dev_commitEmpty',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'commitTransactions',
+			call: 'dev_commitTransactions',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'setNextBlockTimestamp',
+			call: 'dev_setNextBlockTimestamp',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'snapshot',
+			call: 'dev_snapshot',
+			outputFormatter: web3._extend.utils.toDecimal
+		}),
+		new web3._extend.Method({
+			name: 'revert',
+			call: 'dev_revert',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
 	],
 });
 `
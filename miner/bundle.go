@@ -0,0 +1,347 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	maxBundleTxs          = 64   // Maximum number of transactions in a bundle
+	maxBundles            = 1024 // Maximum number of bundles waiting for inclusion
+	maxBundlesPerSender   = 16   // Maximum number of bundles waiting for inclusion per sender
+	maxBundleFutureBlocks = 32   // Maximum number of blocks a bundle may target ahead of the head
+)
+
+var (
+	ErrEmptyBundle         = errors.New("bundle has no transactions")
+	ErrBundleTooLarge      = fmt.Errorf("bundle has more than %d transactions", maxBundleTxs)
+	ErrBundleTxType        = errors.New("transaction type not supported in bundles")
+	ErrBundleTimestamps    = errors.New("bundle min timestamp after max timestamp")
+	ErrBundleOutdated      = errors.New("bundle target block already mined")
+	ErrBundleTooFarAhead   = fmt.Errorf("bundle target block more than %d blocks ahead", maxBundleFutureBlocks)
+	ErrBundlePoolFull      = errors.New("bundle pool full")
+	ErrBundleSenderLimit   = fmt.Errorf("sender has %d bundles waiting for inclusion", maxBundlesPerSender)
+	errBundleTxFailed      = errors.New("bundle transaction failed")
+	errBundleTxReverted    = errors.New("bundle transaction reverted")
+	errBundleNotApplicable = errors.New("bundle not applicable to block")
+
+	bundleIncludedMeter = metrics.NewRegisteredMeter("miner/bundle/included", nil)
+	bundleFailedMeter   = metrics.NewRegisteredMeter("miner/bundle/failed", nil)
+)
+
+// Bundle is an ordered set of transactions that is included in a block
+// atomically and contiguously, or not at all.
+type Bundle struct {
+	Txs          types.Transactions
+	BlockNumber  uint64 // Block the bundle targets
+	MinTimestamp uint64 // Minimum block timestamp, 0 if unbounded
+	MaxTimestamp uint64 // Maximum block timestamp, 0 if unbounded
+
+	// RevertingTxHashes are the transactions allowed to revert without
+	// invalidating the bundle.
+	RevertingTxHashes []common.Hash
+}
+
+// Hash returns the hash identifying the bundle, derived from the hashes of its
+// transactions.
+func (b *Bundle) Hash() common.Hash {
+	hashes := make([]byte, 0, len(b.Txs)*common.HashLength)
+	for _, tx := range b.Txs {
+		hashes = append(hashes, tx.Hash().Bytes()...)
+	}
+	return crypto.Keccak256Hash(hashes)
+}
+
+// applicable returns whether the bundle may be included in the given block.
+func (b *Bundle) applicable(header *types.Header) bool {
+	if b.BlockNumber != header.Number.Uint64() {
+		return false
+	}
+	if b.MinTimestamp != 0 && header.Time < b.MinTimestamp {
+		return false
+	}
+	if b.MaxTimestamp != 0 && header.Time > b.MaxTimestamp {
+		return false
+	}
+	return true
+}
+
+// mayRevert returns whether the given bundle transaction is allowed to revert.
+func (b *Bundle) mayRevert(hash common.Hash) bool {
+	for _, h := range b.RevertingTxHashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks that the bundle is well formed.
+func (b *Bundle) validate() error {
+	if len(b.Txs) == 0 {
+		return ErrEmptyBundle
+	}
+	if len(b.Txs) > maxBundleTxs {
+		return ErrBundleTooLarge
+	}
+	for _, tx := range b.Txs {
+		if tx.Type() == types.DepositTxType || tx.Type() == types.BlobTxType {
+			return fmt.Errorf("%w: %d", ErrBundleTxType, tx.Type())
+		}
+	}
+	if b.MinTimestamp != 0 && b.MaxTimestamp != 0 && b.MinTimestamp > b.MaxTimestamp {
+		return ErrBundleTimestamps
+	}
+	return nil
+}
+
+// BundleTxResult is the outcome of executing a bundle transaction.
+type BundleTxResult struct {
+	Hash    common.Hash
+	GasUsed uint64
+	Receipt *types.Receipt // Nil if the transaction could not be executed
+	Err     error          // Execution error, or errBundleTxReverted
+}
+
+// bundlePool holds the bundles waiting for inclusion, in submission order. The
+// sender of a bundle is the sender of its first transaction.
+type bundlePool struct {
+	bundles []*Bundle
+	known   map[common.Hash]struct{}
+	senders map[common.Address]int // Number of pooled bundles per sender
+	signer  types.Signer
+	lock    sync.Mutex
+}
+
+func newBundlePool(signer types.Signer) *bundlePool {
+	return &bundlePool{
+		known:   make(map[common.Hash]struct{}),
+		senders: make(map[common.Address]int),
+		signer:  signer,
+	}
+}
+
+// add inserts a bundle for inclusion in a block after head. A bundle with the
+// same transactions as a pooled one replaces it.
+func (p *bundlePool) add(bundle *Bundle, head *types.Header) (common.Hash, error) {
+	if err := bundle.validate(); err != nil {
+		return common.Hash{}, err
+	}
+	if bundle.BlockNumber <= head.Number.Uint64() {
+		return common.Hash{}, ErrBundleOutdated
+	}
+	if bundle.BlockNumber > head.Number.Uint64()+maxBundleFutureBlocks {
+		return common.Hash{}, ErrBundleTooFarAhead
+	}
+	sender, err := types.Sender(p.signer, bundle.Txs[0])
+	if err != nil {
+		return common.Hash{}, err
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.prune(head)
+	hash := bundle.Hash()
+	if _, ok := p.known[hash]; ok {
+		for i, old := range p.bundles {
+			if old.Hash() == hash {
+				p.bundles[i] = bundle
+				break
+			}
+		}
+		return hash, nil
+	}
+	if len(p.bundles) >= maxBundles {
+		return common.Hash{}, ErrBundlePoolFull
+	}
+	if p.senders[sender] >= maxBundlesPerSender {
+		return common.Hash{}, ErrBundleSenderLimit
+	}
+	p.bundles = append(p.bundles, bundle)
+	p.known[hash] = struct{}{}
+	p.senders[sender]++
+	return hash, nil
+}
+
+// applicable returns the bundles which may be included in the given block, in
+// submission order.
+func (p *bundlePool) applicable(header *types.Header) []*Bundle {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var bundles []*Bundle
+	for _, bundle := range p.bundles {
+		if bundle.applicable(header) {
+			bundles = append(bundles, bundle)
+		}
+	}
+	return bundles
+}
+
+// prune drops the bundles targeting blocks up to head. The lock must be held.
+func (p *bundlePool) prune(head *types.Header) {
+	remaining := p.bundles[:0]
+	for _, bundle := range p.bundles {
+		if bundle.BlockNumber > head.Number.Uint64() {
+			remaining = append(remaining, bundle)
+		} else {
+			delete(p.known, bundle.Hash())
+			// The sender was recovered when adding the bundle, can't fail
+			sender, _ := types.Sender(p.signer, bundle.Txs[0])
+			if p.senders[sender]--; p.senders[sender] <= 0 {
+				delete(p.senders, sender)
+			}
+		}
+	}
+	clear(p.bundles[len(remaining):])
+	p.bundles = remaining
+}
+
+// AddBundle submits a bundle for inclusion in the block it targets, returning
+// the bundle hash.
+func (miner *Miner) AddBundle(bundle *Bundle) (common.Hash, error) {
+	return miner.bundles.add(bundle, miner.chain.CurrentBlock())
+}
+
+// commitBundles includes the applicable bundles into the sealing block. Bundles
+// which can't be included as a whole are skipped.
+func (miner *Miner) commitBundles(env *environment) {
+	for _, bundle := range miner.bundles.applicable(env.header) {
+		results, err := miner.commitBundle(env, bundle, false)
+		for i, tx := range bundle.Txs {
+			env.report.consider()
+			switch {
+			case err == nil:
+				env.report.include(tx, results[i].GasUsed)
+			case i < len(results) && results[i].Err != nil:
+				env.report.skip(tx.Hash(), tx.FeeCurrency(), SkipBundleFailed, results[i].Err)
+			default:
+				env.report.skip(tx.Hash(), tx.FeeCurrency(), SkipBundleFailed, nil)
+			}
+		}
+		if err != nil {
+			log.Debug("Skipping bundle", "hash", bundle.Hash(), "err", err)
+			bundleFailedMeter.Mark(1)
+			continue
+		}
+		bundleIncludedMeter.Mark(1)
+	}
+}
+
+// commitBundle applies all transactions of the bundle to the environment. If
+// any transaction fails, or reverts without being allowed to, the environment
+// is restored to its state before the bundle. In simulation mode, all
+// transactions are executed regardless of failures and the results are
+// returned.
+func (miner *Miner) commitBundle(env *environment, bundle *Bundle, simulate bool) ([]*BundleTxResult, error) {
+	if !simulate && !bundle.applicable(env.header) {
+		return nil, errBundleNotApplicable
+	}
+	// Transactions are finalised into the state one by one, so the journal
+	// can't be used to revert the whole bundle.
+	var (
+		state        = env.state.Copy()
+		gasPool      = env.gasPool.Gas()
+		multiGasPool = env.multiGasPool.Copy()
+		gasUsed      = env.header.GasUsed
+		txCount      = len(env.txs)
+		tcount       = env.tcount
+
+		results []*BundleTxResult
+		failure error
+	)
+	for _, tx := range bundle.Txs {
+		result := &BundleTxResult{Hash: tx.Hash()}
+		results = append(results, result)
+
+		if err := miner.commitBundleTx(env, tx); err != nil {
+			result.Err = err
+		} else {
+			result.Receipt = env.receipts[len(env.receipts)-1]
+			result.GasUsed = result.Receipt.GasUsed
+			if result.Receipt.Status == types.ReceiptStatusFailed && !bundle.mayRevert(tx.Hash()) {
+				result.Err = errBundleTxReverted
+			}
+		}
+		if result.Err != nil && failure == nil {
+			failure = fmt.Errorf("%w: tx %s: %w", errBundleTxFailed, tx.Hash(), result.Err)
+			if !simulate {
+				break
+			}
+		}
+	}
+	if failure != nil {
+		env.state = state
+		env.gasPool.SetGas(gasPool)
+		env.multiGasPool = multiGasPool
+		env.header.GasUsed = gasUsed
+		env.txs, env.receipts = env.txs[:txCount], env.receipts[:txCount]
+		env.tcount = tcount
+	}
+	return results, failure
+}
+
+// commitBundleTx applies a single bundle transaction, applying the same
+// fee currency restrictions as for pool transactions.
+func (miner *Miner) commitBundleTx(env *environment, tx *types.Transaction) error {
+	if fc := tx.FeeCurrency(); fc != nil {
+		if _, ok := env.feeCurrencyAllowlist[*fc]; !ok {
+			return fmt.Errorf("fee currency %s not allowed", fc)
+		}
+	}
+	if left := env.multiGasPool.PoolFor(tx.FeeCurrency()).Gas(); left < tx.Gas() {
+		return fmt.Errorf("not enough fee currency gas left: have %d, want %d", left, tx.Gas())
+	}
+	env.state.SetTxContext(tx.Hash(), env.tcount)
+
+	availableGas := env.gasPool.Gas()
+	if err := miner.commitTransaction(env, tx); err != nil {
+		return err
+	}
+	// Can't fail, checked above
+	env.multiGasPool.PoolFor(tx.FeeCurrency()).SubGas(availableGas - env.gasPool.Gas())
+	return nil
+}
+
+// SimulateBundle executes the bundle on top of the given parent block, as the
+// first transactions of a block with the given timestamp. It returns the
+// result of each transaction, and an error if the bundle could not be included.
+func (miner *Miner) SimulateBundle(bundle *Bundle, parent common.Hash, timestamp uint64) ([]*BundleTxResult, *types.Header, error) {
+	if err := bundle.validate(); err != nil {
+		return nil, nil, err
+	}
+	env, err := miner.prepareWork(&generateParams{
+		timestamp:  timestamp,
+		parentHash: parent,
+		coinbase:   miner.config.PendingFeeRecipient,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	miner.initGasPools(env)
+	results, err := miner.commitBundle(env, bundle, true)
+	return results, env.header, err
+}
@@ -0,0 +1,174 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// newBundleTestTxs creates a bundle funding the test user, which then deploys
+// a contract whose constructor reverts.
+func newBundleTestTxs() types.Transactions {
+	var (
+		signer   = types.LatestSigner(params.TestChainConfig)
+		gasPrice = big.NewInt(2 * params.InitialBaseFee)
+	)
+	fund := types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+		Nonce:    0,
+		To:       &testUserAddress,
+		Value:    big.NewInt(1000000000000000),
+		Gas:      params.TxGas,
+		GasPrice: gasPrice,
+	})
+	revert := types.MustSignNewTx(testUserKey, signer, &types.LegacyTx{
+		Nonce:    0,
+		Gas:      100000,
+		GasPrice: gasPrice,
+		Data:     common.FromHex("0x60006000fd"), // PUSH1 0 PUSH1 0 REVERT
+	})
+	return types.Transactions{fund, revert}
+}
+
+// Tests that bundles are included atomically at the top of the block, and only
+// if none of their transactions revert unless allowed to.
+func TestCommitBundle(t *testing.T) {
+	t.Parallel()
+
+	var (
+		engine  = ethash.NewFaker()
+		backend = newTestWorkerBackend(t, params.TestChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+		miner   = New(backend, testConfig, engine)
+		parent  = backend.chain.CurrentBlock()
+		txs     = newBundleTestTxs()
+	)
+	build := func() *types.Block {
+		t.Helper()
+		res := miner.generateWork(&generateParams{
+			parentHash: parent.Hash(),
+			timestamp:  parent.Time + 1,
+			coinbase:   testBankAddress,
+		})
+		if res.err != nil {
+			t.Fatalf("failed to build block: %v", res.err)
+		}
+		return res.block
+	}
+	// A bundle with a reverting transaction is not included
+	if _, err := miner.AddBundle(&Bundle{Txs: txs, BlockNumber: 1}); err != nil {
+		t.Fatalf("failed to add bundle: %v", err)
+	}
+	if block := build(); len(block.Transactions()) != 0 {
+		t.Fatalf("reverting bundle included: %d txs", len(block.Transactions()))
+	}
+	// Unless the revert is allowed, resubmitting replaces the pooled bundle
+	if _, err := miner.AddBundle(&Bundle{Txs: txs, BlockNumber: 1, RevertingTxHashes: []common.Hash{txs[1].Hash()}}); err != nil {
+		t.Fatalf("failed to add bundle: %v", err)
+	}
+	block := build()
+	if len(block.Transactions()) != len(txs) {
+		t.Fatalf("bundle not included: have %d txs, want %d", len(block.Transactions()), len(txs))
+	}
+	for i, tx := range block.Transactions() {
+		if tx.Hash() != txs[i].Hash() {
+			t.Errorf("tx %d: bundle order not retained", i)
+		}
+	}
+	// Bundles for other blocks or timestamps are not included
+	if _, err := miner.AddBundle(&Bundle{Txs: txs[:1], BlockNumber: 1, MinTimestamp: parent.Time + 2}); err != nil {
+		t.Fatalf("failed to add bundle: %v", err)
+	}
+	if block := build(); len(block.Transactions()) != len(txs) {
+		t.Fatalf("inapplicable bundle included: %d txs", len(block.Transactions()))
+	}
+	if _, err := miner.AddBundle(&Bundle{Txs: txs, BlockNumber: 0}); !errors.Is(err, ErrBundleOutdated) {
+		t.Fatalf("outdated bundle: have %v, want %v", err, ErrBundleOutdated)
+	}
+}
+
+// Tests that simulating a bundle reports the outcome of every transaction.
+func TestSimulateBundle(t *testing.T) {
+	t.Parallel()
+
+	var (
+		engine  = ethash.NewFaker()
+		backend = newTestWorkerBackend(t, params.TestChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+		miner   = New(backend, testConfig, engine)
+		txs     = newBundleTestTxs()
+	)
+	results, header, err := miner.SimulateBundle(&Bundle{Txs: txs}, backend.chain.CurrentBlock().Hash(), uint64(time.Now().Unix()))
+	if !errors.Is(err, errBundleTxFailed) {
+		t.Fatalf("simulation error mismatch: have %v, want %v", err, errBundleTxFailed)
+	}
+	if header.Number.Uint64() != 1 {
+		t.Fatalf("simulated block number mismatch: have %d, want 1", header.Number)
+	}
+	if len(results) != len(txs) {
+		t.Fatalf("result count mismatch: have %d, want %d", len(results), len(txs))
+	}
+	if results[0].Err != nil || results[0].GasUsed != params.TxGas {
+		t.Errorf("funding tx: err %v, gas used %d", results[0].Err, results[0].GasUsed)
+	}
+	if !errors.Is(results[1].Err, errBundleTxReverted) || results[1].Receipt == nil {
+		t.Errorf("reverting tx: err %v", results[1].Err)
+	}
+}
+
+// Tests that the bundle pool limits how far ahead bundles may target, and how
+// many bundles a single sender may have waiting for inclusion.
+func TestBundlePoolLimits(t *testing.T) {
+	t.Parallel()
+
+	var (
+		signer = types.LatestSigner(params.TestChainConfig)
+		pool   = newBundlePool(signer)
+		head   = &types.Header{Number: big.NewInt(10)}
+	)
+	newBundle := func(nonce uint64, number uint64) *Bundle {
+		tx := types.MustSignNewTx(testUserKey, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &testBankAddress,
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(params.InitialBaseFee),
+		})
+		return &Bundle{Txs: types.Transactions{tx}, BlockNumber: number}
+	}
+	if _, err := pool.add(newBundle(0, 11+maxBundleFutureBlocks), head); !errors.Is(err, ErrBundleTooFarAhead) {
+		t.Fatalf("far future bundle: have %v, want %v", err, ErrBundleTooFarAhead)
+	}
+	for i := 0; i < maxBundlesPerSender; i++ {
+		if _, err := pool.add(newBundle(uint64(i), 11), head); err != nil {
+			t.Fatalf("bundle %d: failed to add: %v", i, err)
+		}
+	}
+	if _, err := pool.add(newBundle(maxBundlesPerSender, 12), head); !errors.Is(err, ErrBundleSenderLimit) {
+		t.Fatalf("bundle over sender limit: have %v, want %v", err, ErrBundleSenderLimit)
+	}
+	// Once the bundles are outdated, the sender may submit new ones
+	head = &types.Header{Number: big.NewInt(11)}
+	if _, err := pool.add(newBundle(maxBundlesPerSender, 12), head); err != nil {
+		t.Fatalf("failed to add bundle after pruning: %v", err)
+	}
+}
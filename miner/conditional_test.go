@@ -0,0 +1,112 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that conditional transactions are only included if their conditional
+// holds for the block being built, and are marked rejected if it can't hold.
+func TestCommitConditional(t *testing.T) {
+	t.Parallel()
+
+	var (
+		engine  = ethash.NewFaker()
+		backend = newTestWorkerBackend(t, params.TestChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+		miner   = New(backend, testConfig, engine)
+		parent  = backend.chain.CurrentBlock()
+		signer  = types.LatestSigner(params.TestChainConfig)
+	)
+	newTx := func(gasPrice int64, cond *types.TransactionConditional) *types.Transaction {
+		tx := types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+			Nonce:    0,
+			To:       &testUserAddress,
+			Value:    big.NewInt(1000),
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(gasPrice),
+		})
+		tx.SetConditional(cond)
+		return tx
+	}
+	build := func() *newPayloadResult {
+		t.Helper()
+		res := miner.generateWork(&generateParams{
+			parentHash: parent.Hash(),
+			timestamp:  parent.Time + 1,
+			coinbase:   testBankAddress,
+		})
+		if res.err != nil {
+			t.Fatalf("failed to build block: %v", res.err)
+		}
+		return res
+	}
+	// A conditional on account storage which doesn't match the state
+	root := common.HexToHash("0x01")
+	mismatch := newTx(params.InitialBaseFee, &types.TransactionConditional{
+		KnownAccounts: types.KnownAccounts{testBankAddress: {StorageRoot: &root}},
+	})
+	if err := backend.txPool.Add([]*types.Transaction{mismatch}, true, true)[0]; err != nil {
+		t.Fatalf("failed to add tx: %v", err)
+	}
+	res := build()
+	if n := len(res.block.Transactions()); n != 0 {
+		t.Fatalf("tx with failing conditional included: %d txs", n)
+	}
+	if !mismatch.Rejected() {
+		t.Error("tx with failing conditional not marked rejected")
+	}
+	if len(res.report.Skipped) != 1 || res.report.Skipped[0].Reason != SkipConditional {
+		t.Errorf("skipped txs mismatch: %+v", res.report.Skipped)
+	}
+	// A conditional which may only hold in a later block
+	future := newTx(2*params.InitialBaseFee, &types.TransactionConditional{
+		BlockNumberMin: big.NewInt(2),
+	})
+	if err := backend.txPool.Add([]*types.Transaction{future}, true, true)[0]; err != nil {
+		t.Fatalf("failed to add tx: %v", err)
+	}
+	res = build()
+	if n := len(res.block.Transactions()); n != 0 {
+		t.Fatalf("tx with future conditional included: %d txs", n)
+	}
+	if future.Rejected() {
+		t.Error("tx with future conditional marked rejected")
+	}
+	// A conditional on the block number and the state of an account
+	valid := newTx(4*params.InitialBaseFee, &types.TransactionConditional{
+		KnownAccounts:  types.KnownAccounts{testBankAddress: {StorageRoot: &types.EmptyRootHash}},
+		BlockNumberMin: big.NewInt(1),
+	})
+	if err := backend.txPool.Add([]*types.Transaction{valid}, true, true)[0]; err != nil {
+		t.Fatalf("failed to add tx: %v", err)
+	}
+	res = build()
+	if txs := res.block.Transactions(); len(txs) != 1 || txs[0].Hash() != valid.Hash() {
+		t.Fatalf("tx with holding conditional not included: %d txs", len(txs))
+	}
+	if valid.Rejected() {
+		t.Error("tx with holding conditional marked rejected")
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/contracts"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tests that the miner doesn't include transactions paying fees in a currency
+// whose exchange rate jumped, even if the transaction pool still holds them.
+func TestExchangeRateGuardExcludesFeeCurrency(t *testing.T) {
+	t.Parallel()
+
+	var (
+		engine  = beacon.New(ethash.NewFaker())
+		gspec   = core.DeveloperGenesisBlock(11_500_000, &testBankAddress)
+		signer  = types.LatestSigner(gspec.Config)
+		oracle  = common.HexToAddress("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb0001") // oracle of core.DevFeeCurrencyAddr
+		setRate = crypto.Keccak256([]byte("setExchangeRate(address,uint256,uint256)"))[:4]
+	)
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+
+	pool := legacypool.New(testTxPoolConfig, chain)
+	txpool, _ := txpool.New(testTxPoolConfig.PriceLimit, chain, []txpool.SubPool{pool})
+	defer txpool.Close()
+
+	config := testConfig
+	config.FeeCurrencyDefault = 0.5
+	config.ExchangeRateGuard = contracts.ExchangeRateGuardConfig{MaxChange: 2}
+	miner := New(&testWorkerBackend{chain: chain, txPool: txpool, genesis: gspec}, config, engine)
+
+	build := func(parent *types.Header) *newPayloadResult {
+		t.Helper()
+		res := miner.generateWork(&generateParams{
+			parentHash:  parent.Hash(),
+			timestamp:   parent.Time + 1,
+			coinbase:    testBankAddress,
+			withdrawals: types.Withdrawals{},
+			beaconRoot:  new(common.Hash),
+		})
+		if res.err != nil {
+			t.Fatalf("failed to build block: %v", res.err)
+		}
+		return res
+	}
+	add := func(tx *types.Transaction) {
+		t.Helper()
+		if err := txpool.Add([]*types.Transaction{tx}, false, true)[0]; err != nil {
+			t.Fatalf("failed to add tx: %v", err)
+		}
+	}
+	// Make the exchange rate of the fee currency jump within the first block
+	data := append(common.CopyBytes(setRate), common.LeftPadBytes(core.DevFeeCurrencyAddr.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(big.NewInt(100).Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(big.NewInt(1).Bytes(), 32)...)
+	add(types.MustSignNewTx(core.DevPrivateKey, signer, &types.DynamicFeeTx{
+		ChainID:   gspec.Config.ChainID,
+		Nonce:     0,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(100_000_000_000),
+		Gas:       100_000,
+		To:        &oracle,
+		Data:      data,
+	}))
+	res := build(chain.CurrentBlock())
+	if n := len(res.block.Transactions()); n != 1 {
+		t.Fatalf("exchange rate update not included: %d txs", n)
+	}
+	if _, err := chain.InsertChain(types.Blocks{res.block}); err != nil {
+		t.Fatal(err)
+	}
+	if err := txpool.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The pool doesn't guard exchange rates and keeps the transaction, but the
+	// miner doesn't include it.
+	add(types.MustSignNewTx(core.DevPrivateKey, signer, &types.CeloDynamicFeeTxV2{
+		ChainID:     gspec.Config.ChainID,
+		Nonce:       1,
+		GasTipCap:   big.NewInt(1),
+		GasFeeCap:   big.NewInt(100_000_000_000),
+		Gas:         100_000,
+		To:          &testUserAddress,
+		Value:       big.NewInt(0),
+		FeeCurrency: &core.DevFeeCurrencyAddr,
+	}))
+	head := chain.CurrentBlock()
+	if n := len(build(head).block.Transactions()); n != 0 {
+		t.Fatalf("transaction in excluded fee currency included: %d txs", n)
+	}
+	env, err := miner.prepareWork(&generateParams{parentHash: head.Hash(), timestamp: head.Time + 1, coinbase: testBankAddress})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := env.feeCurrencyAllowlist[core.DevFeeCurrencyAddr]; ok {
+		t.Error("fee currency with jumping exchange rate not excluded")
+	}
+	if _, ok := env.feeCurrencyAllowlist[core.DevFeeCurrencyAddr2]; !ok {
+		t.Error("fee currency with unchanged exchange rate excluded")
+	}
+}
@@ -24,14 +24,18 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/contracts"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -59,8 +63,11 @@ type Config struct {
 	EffectiveGasCeil          uint64 // if non-zero, a gas ceiling to apply independent of the header's gaslimit value
 
 	// Celo:
-	FeeCurrencyDefault float64                    // Default fraction of block gas limit
-	FeeCurrencyLimits  map[common.Address]float64 // Fee currency-to-limit fraction mapping
+	FeeCurrencyDefault float64                           // Default fraction of block gas limit
+	FeeCurrencyLimits  map[common.Address]float64        // Fee currency-to-limit fraction mapping
+	ExchangeRateGuard  contracts.ExchangeRateGuardConfig // Exclusion of fee currencies with stale or erratic exchange rates
+
+	OrderingPolicy string `toml:",omitempty"` // Transaction ordering policy, see OrderingPolicies
 }
 
 // DefaultConfig contains default settings for miner.
@@ -75,6 +82,7 @@ var DefaultConfig = Config{
 	Recommit: 2 * time.Second,
 
 	FeeCurrencyDefault: DefaultFeeCurrencyLimit,
+	OrderingPolicy:     OrderingPriority,
 }
 
 // Miner is the main object which takes care of submitting new work to consensus
@@ -92,10 +100,26 @@ type Miner struct {
 	backend Backend
 
 	feeCurrencyBlocklist *AddressBlocklist
+	exchangeRateGuard    *contracts.ExchangeRateGuard
+
+	bundles      *bundlePool                                // Bundles waiting for inclusion
+	ordering     OrderingPolicy                             // Ordering of the pending transactions in blocks
+	localsFirst  bool                                       // Whether local transactions are included before remote ones
+	buildReports *lru.Cache[engine.PayloadID, *BuildReport] // Reports of the recently built payloads
 }
 
 // New creates a new miner with provided config.
 func New(eth Backend, config Config, engine consensus.Engine) *Miner {
+	name := config.OrderingPolicy
+	if name == "" {
+		name = OrderingPriority
+	}
+	ordering, err := LookupOrderingPolicy(name)
+	if err != nil {
+		log.Warn("Falling back to default transaction ordering", "err", err)
+		name = OrderingPriority
+		ordering, _ = LookupOrderingPolicy(name)
+	}
 	return &Miner{
 		backend:     eth,
 		config:      &config,
@@ -106,6 +130,11 @@ func New(eth Backend, config Config, engine consensus.Engine) *Miner {
 		pending:     &pending{},
 
 		feeCurrencyBlocklist: NewAddressBlocklist(),
+		exchangeRateGuard:    contracts.NewExchangeRateGuard(config.ExchangeRateGuard),
+		bundles:              newBundlePool(types.LatestSigner(eth.BlockChain().Config())),
+		ordering:             ordering,
+		localsFirst:          name == OrderingPriority,
+		buildReports:         newBuildReportCache(),
 	}
 }
 
@@ -41,8 +41,8 @@ func newTxWithMinerFee(tx *txpool.LazyTransaction, from common.Address, baseFee
 	tip := new(uint256.Int).Set(tx.GasTipCap)
 	if baseFee != nil {
 		baseFeeConverted := baseFee
-		if tx.FeeCurrency != nil {
-			baseFeeBig, err := exchange.ConvertCeloToCurrency(rates, tx.FeeCurrency, baseFee.ToBig())
+		if currency := tx.DenominationCurrency(); currency != nil {
+			baseFeeBig, err := exchange.ConvertCeloToCurrency(rates, currency, baseFee.ToBig())
 			if err != nil {
 				return nil, err
 			}
@@ -58,9 +58,9 @@ func newTxWithMinerFee(tx *txpool.LazyTransaction, from common.Address, baseFee
 		}
 	}
 
-	// Convert tip back into celo if the transaction is in a different currency
-	if tx.FeeCurrency != nil {
-		tipBig, err := exchange.ConvertCurrencyToCelo(rates, tx.FeeCurrency, tip.ToBig())
+	// Convert tip back into celo if the transaction is denominated in a different currency
+	if currency := tx.DenominationCurrency(); currency != nil {
+		tipBig, err := exchange.ConvertCurrencyToCelo(rates, currency, tip.ToBig())
 		if err != nil {
 			return nil, err
 		}
@@ -74,41 +74,60 @@ func newTxWithMinerFee(tx *txpool.LazyTransaction, from common.Address, baseFee
 	}, nil
 }
 
-// txByPriceAndTime implements both the sort and the heap interface, making it useful
+// txHeap implements both the sort and the heap interface, making it useful
 // for all at once sorting as well as individually adding and removing elements.
-type txByPriceAndTime []*txWithMinerFee
-
-func (s txByPriceAndTime) Len() int { return len(s) }
-func (s txByPriceAndTime) Less(i, j int) bool {
-	// If the prices are equal, use the time the transaction was first seen for
-	// deterministic sorting
-	cmp := s[i].fees.Cmp(s[j].fees)
-	if cmp == 0 {
-		return s[i].tx.Time.Before(s[j].tx.Time)
-	}
-	return cmp > 0
+type txHeap struct {
+	txs  []*txWithMinerFee
+	less func(a, b *txWithMinerFee) bool // Ordering of the transactions
 }
-func (s txByPriceAndTime) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
 
-func (s *txByPriceAndTime) Push(x interface{}) {
-	*s = append(*s, x.(*txWithMinerFee))
+func (s txHeap) Len() int           { return len(s.txs) }
+func (s txHeap) Less(i, j int) bool { return s.less(s.txs[i], s.txs[j]) }
+func (s txHeap) Swap(i, j int)      { s.txs[i], s.txs[j] = s.txs[j], s.txs[i] }
+
+func (s *txHeap) Push(x interface{}) {
+	s.txs = append(s.txs, x.(*txWithMinerFee))
 }
 
-func (s *txByPriceAndTime) Pop() interface{} {
-	old := *s
+func (s *txHeap) Pop() interface{} {
+	old := s.txs
 	n := len(old)
 	x := old[n-1]
 	old[n-1] = nil
-	*s = old[0 : n-1]
+	s.txs = old[0 : n-1]
 	return x
 }
 
+// byPriceAndTime orders transactions by their effective miner tip.
+func byPriceAndTime(a, b *txWithMinerFee) bool {
+	// If the prices are equal, use the time the transaction was first seen for
+	// deterministic sorting
+	cmp := a.fees.Cmp(b.fees)
+	if cmp == 0 {
+		return a.tx.Time.Before(b.tx.Time)
+	}
+	return cmp > 0
+}
+
+// byTimeAndPrice orders transactions by the time they were first seen.
+func byTimeAndPrice(a, b *txWithMinerFee) bool {
+	// If the transactions arrived at the same time, prefer the higher tip and
+	// fall back to the sender for deterministic sorting
+	if !a.tx.Time.Equal(b.tx.Time) {
+		return a.tx.Time.Before(b.tx.Time)
+	}
+	if cmp := a.fees.Cmp(b.fees); cmp != 0 {
+		return cmp > 0
+	}
+	return a.from.Cmp(b.from) < 0
+}
+
 // transactionsByPriceAndNonce represents a set of transactions that can return
 // transactions in a profit-maximizing sorted order, while supporting removing
 // entire batches of transactions for non-executable accounts.
 type transactionsByPriceAndNonce struct {
 	txs     map[common.Address][]*txpool.LazyTransaction // Per account nonce-sorted list of transactions
-	heads   txByPriceAndTime                             // Next transaction for each unique account (price heap)
+	heads   txHeap                                       // Next transaction for each unique account (price heap)
 	signer  types.Signer                                 // Signer for the set of transactions
 	baseFee *uint256.Int                                 // Current base fee
 
@@ -122,20 +141,32 @@ type transactionsByPriceAndNonce struct {
 // Note, the input map is reowned so the caller should not interact any more with
 // if after providing it to the constructor.
 func newTransactionsByPriceAndNonce(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int, rates common.ExchangeRates) *transactionsByPriceAndNonce {
+	return newTransactionsByNonce(signer, txs, baseFee, rates, byPriceAndTime)
+}
+
+// newTransactionsByTimeAndNonce creates a transaction set that can retrieve
+// transactions in the order they were first seen, in a nonce-honouring way.
+func newTransactionsByTimeAndNonce(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int, rates common.ExchangeRates) *transactionsByPriceAndNonce {
+	return newTransactionsByNonce(signer, txs, baseFee, rates, byTimeAndPrice)
+}
+
+// newTransactionsByNonce creates a transaction set ordering the next transaction
+// of each account by less.
+func newTransactionsByNonce(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int, rates common.ExchangeRates, less func(a, b *txWithMinerFee) bool) *transactionsByPriceAndNonce {
 	// Convert the basefee from header format to uint256 format
 	var baseFeeUint *uint256.Int
 	if baseFee != nil {
 		baseFeeUint = uint256.MustFromBig(baseFee)
 	}
 	// Initialize a price and received time based heap with the head transactions
-	heads := make(txByPriceAndTime, 0, len(txs))
+	heads := txHeap{txs: make([]*txWithMinerFee, 0, len(txs)), less: less}
 	for from, accTxs := range txs {
 		wrapped, err := newTxWithMinerFee(accTxs[0], from, baseFeeUint, rates)
 		if err != nil {
 			delete(txs, from)
 			continue
 		}
-		heads = append(heads, wrapped)
+		heads.txs = append(heads.txs, wrapped)
 		txs[from] = accTxs[1:]
 	}
 	heap.Init(&heads)
@@ -152,18 +183,18 @@ func newTransactionsByPriceAndNonce(signer types.Signer, txs map[common.Address]
 
 // Peek returns the next transaction by price.
 func (t *transactionsByPriceAndNonce) Peek() (*txpool.LazyTransaction, *uint256.Int) {
-	if len(t.heads) == 0 {
+	if len(t.heads.txs) == 0 {
 		return nil, nil
 	}
-	return t.heads[0].tx, t.heads[0].fees
+	return t.heads.txs[0].tx, t.heads.txs[0].fees
 }
 
 // Shift replaces the current best head with the next one from the same account.
 func (t *transactionsByPriceAndNonce) Shift() {
-	acc := t.heads[0].from
+	acc := t.heads.txs[0].from
 	if txs, ok := t.txs[acc]; ok && len(txs) > 0 {
 		if wrapped, err := newTxWithMinerFee(txs[0], acc, t.baseFee, t.exchangeRates); err == nil {
-			t.heads[0], t.txs[acc] = wrapped, txs[1:]
+			t.heads.txs[0], t.txs[acc] = wrapped, txs[1:]
 			heap.Fix(&t.heads, 0)
 			return
 		}
@@ -181,10 +212,10 @@ func (t *transactionsByPriceAndNonce) Pop() {
 // Empty returns if the price heap is empty. It can be used to check it simpler
 // than calling peek and checking for nil return.
 func (t *transactionsByPriceAndNonce) Empty() bool {
-	return len(t.heads) == 0
+	return len(t.heads.txs) == 0
 }
 
 // Clear removes the entire content of the heap.
 func (t *transactionsByPriceAndNonce) Clear() {
-	t.heads, t.txs = nil, nil
+	t.heads.txs, t.txs = nil, nil
 }
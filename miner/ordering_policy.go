@@ -0,0 +1,225 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+)
+
+// Names of the built-in transaction ordering policies.
+const (
+	OrderingPriority  = "priority"  // Highest effective miner tip first
+	OrderingFIFO      = "fifo"      // Earliest arrival first
+	OrderingFairShare = "fairshare" // Fee currencies take turns by gas used
+)
+
+// TransactionSet is a set of pending transactions the block is filled from, in
+// the order they should be included. Transactions of an account are always
+// returned in nonce order.
+type TransactionSet interface {
+	// Peek returns the next transaction to include, along with its effective
+	// miner tip in the native currency, or nil if the set is empty.
+	Peek() (*txpool.LazyTransaction, *uint256.Int)
+
+	// Shift replaces the peeked transaction with the next one of the same
+	// account, after the peeked one was included.
+	Shift()
+
+	// Pop removes the peeked transaction along with all remaining ones of the
+	// same account, after the peeked one could not be included.
+	Pop()
+
+	// Empty returns whether there are no transactions left.
+	Empty() bool
+
+	// Clear removes all transactions from the set.
+	Clear()
+}
+
+// OrderingPolicy creates the transaction set for the given per account, nonce
+// sorted pending transactions. The set may take ownership of the txs map.
+type OrderingPolicy func(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int, rates common.ExchangeRates) TransactionSet
+
+var (
+	orderingPolicies = map[string]OrderingPolicy{
+		OrderingPriority: func(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int, rates common.ExchangeRates) TransactionSet {
+			return newTransactionsByPriceAndNonce(signer, txs, baseFee, rates)
+		},
+		OrderingFIFO: func(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int, rates common.ExchangeRates) TransactionSet {
+			return newTransactionsByTimeAndNonce(signer, txs, baseFee, rates)
+		},
+		OrderingFairShare: func(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int, rates common.ExchangeRates) TransactionSet {
+			return newTransactionsByCurrencyShare(signer, txs, baseFee, rates)
+		},
+	}
+	orderingPoliciesLock sync.RWMutex
+)
+
+// RegisterOrderingPolicy makes an ordering policy available under the given
+// name, to be selected through the miner config. Registering a policy under an
+// existing name replaces it.
+func RegisterOrderingPolicy(name string, policy OrderingPolicy) {
+	orderingPoliciesLock.Lock()
+	defer orderingPoliciesLock.Unlock()
+
+	orderingPolicies[name] = policy
+}
+
+// LookupOrderingPolicy returns the ordering policy registered under the given
+// name. The empty name selects the default priority ordering.
+func LookupOrderingPolicy(name string) (OrderingPolicy, error) {
+	if name == "" {
+		name = OrderingPriority
+	}
+	orderingPoliciesLock.RLock()
+	defer orderingPoliciesLock.RUnlock()
+
+	policy, ok := orderingPolicies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown transaction ordering policy %q", name)
+	}
+	return policy, nil
+}
+
+// OrderingPolicies returns the names of all registered ordering policies.
+func OrderingPolicies() []string {
+	orderingPoliciesLock.RLock()
+	defer orderingPoliciesLock.RUnlock()
+
+	names := make([]string, 0, len(orderingPolicies))
+	for name := range orderingPolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// transactionsByCurrencyShare is a transaction set in which fee currencies take
+// turns, always picking the best priced transaction of the currency which used
+// the least gas so far. Accounts are grouped by the fee currency of their first
+// pending transaction, and the gas of all their transactions is accounted to
+// that currency.
+type transactionsByCurrencyShare struct {
+	sets    map[common.Address]*transactionsByPriceAndNonce // Per fee currency transactions, native under the zero address
+	used    map[common.Address]uint64                       // Gas limit of the transactions included per fee currency
+	current *common.Address                                 // Fee currency of the peeked transaction
+}
+
+// newTransactionsByCurrencyShare creates a transaction set sharing the block
+// between the fee currencies.
+func newTransactionsByCurrencyShare(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int, rates common.ExchangeRates) *transactionsByCurrencyShare {
+	groups := make(map[common.Address]map[common.Address][]*txpool.LazyTransaction)
+	for from, accTxs := range txs {
+		currency := feeCurrencyKey(accTxs[0].FeeCurrency)
+		if groups[currency] == nil {
+			groups[currency] = make(map[common.Address][]*txpool.LazyTransaction)
+		}
+		groups[currency][from] = accTxs
+	}
+	sets := make(map[common.Address]*transactionsByPriceAndNonce, len(groups))
+	for currency, group := range groups {
+		sets[currency] = newTransactionsByPriceAndNonce(signer, group, baseFee, rates)
+	}
+	return &transactionsByCurrencyShare{
+		sets: sets,
+		used: make(map[common.Address]uint64),
+	}
+}
+
+// feeCurrencyKey returns the map key of a fee currency.
+func feeCurrencyKey(currency *common.Address) common.Address {
+	if currency == nil {
+		return common.Address{}
+	}
+	return *currency
+}
+
+// Peek returns the best transaction of the fee currency with the least gas
+// used so far. Ties are broken by the tip, then by the currency address.
+func (t *transactionsByCurrencyShare) Peek() (*txpool.LazyTransaction, *uint256.Int) {
+	if t.current == nil {
+		var (
+			best    *common.Address
+			bestTip *uint256.Int
+		)
+		currencies := make([]common.Address, 0, len(t.sets))
+		for currency := range t.sets {
+			currencies = append(currencies, currency)
+		}
+		slices.SortFunc(currencies, func(a, b common.Address) int { return bytes.Compare(a[:], b[:]) })
+
+		for i, currency := range currencies {
+			set := t.sets[currency]
+			if set.Empty() {
+				delete(t.sets, currency)
+				continue
+			}
+			_, tip := set.Peek()
+			if best != nil {
+				if used, bestUsed := t.used[currency], t.used[*best]; used > bestUsed || (used == bestUsed && !tip.Gt(bestTip)) {
+					continue
+				}
+			}
+			best, bestTip = &currencies[i], tip
+		}
+		t.current = best
+	}
+	if t.current == nil {
+		return nil, nil
+	}
+	return t.sets[*t.current].Peek()
+}
+
+// Shift accounts the gas of the peeked transaction to the fee currency of its
+// account group and replaces it with the next one from the same account.
+func (t *transactionsByCurrencyShare) Shift() {
+	if tx, _ := t.Peek(); tx != nil {
+		t.used[*t.current] += tx.Gas
+		t.sets[*t.current].Shift()
+	}
+	t.current = nil
+}
+
+// Pop removes the peeked transaction, *not* replacing it with the next one
+// from the same account.
+func (t *transactionsByCurrencyShare) Pop() {
+	if tx, _ := t.Peek(); tx != nil {
+		t.sets[*t.current].Pop()
+	}
+	t.current = nil
+}
+
+// Empty returns whether there are no transactions left.
+func (t *transactionsByCurrencyShare) Empty() bool {
+	tx, _ := t.Peek()
+	return tx == nil
+}
+
+// Clear removes the entire content of the set.
+func (t *transactionsByCurrencyShare) Clear() {
+	t.sets, t.current = nil, nil
+}
@@ -0,0 +1,254 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// orderingTestTxs returns the pending transactions of three accounts, one of
+// them paying in a fee currency, with distinct arrival times and tips. The
+// transaction hashes are set to their names.
+func orderingTestTxs(feeCurrency common.Address) map[common.Address][]*txpool.LazyTransaction {
+	newTx := func(name string, arrival int64, tip uint64, gas uint64, currency *common.Address) *txpool.LazyTransaction {
+		return &txpool.LazyTransaction{
+			Hash:        common.BytesToHash([]byte(name)),
+			Time:        time.Unix(arrival, 0),
+			GasFeeCap:   uint256.NewInt(tip),
+			GasTipCap:   uint256.NewInt(tip),
+			Gas:         gas,
+			FeeCurrency: currency,
+		}
+	}
+	return map[common.Address][]*txpool.LazyTransaction{
+		common.HexToAddress("0xa"): {newTx("a0", 3, 1, 21000, nil), newTx("a1", 5, 1, 21000, nil)},
+		common.HexToAddress("0xb"): {newTx("b0", 1, 2, 21000, nil), newTx("b1", 4, 2, 21000, nil)},
+		common.HexToAddress("0xc"): {newTx("c0", 2, 10, 50000, &feeCurrency), newTx("c1", 6, 10, 50000, &feeCurrency)},
+	}
+}
+
+// Tests that each built-in ordering policy includes transactions in its
+// expected, deterministic order.
+func TestOrderingPolicies(t *testing.T) {
+	t.Parallel()
+
+	var (
+		feeCurrency = common.HexToAddress("0xfc")
+		rates       = common.ExchangeRates{feeCurrency: big.NewRat(1, 1)}
+	)
+	tests := []struct {
+		policy string
+		want   []string
+	}{
+		{OrderingPriority, []string{"c0", "c1", "b0", "b1", "a0", "a1"}},
+		{OrderingFIFO, []string{"b0", "c0", "a0", "b1", "a1", "c1"}},
+		{OrderingFairShare, []string{"c0", "b0", "b1", "a0", "c1", "a1"}},
+	}
+	for _, tt := range tests {
+		policy, err := LookupOrderingPolicy(tt.policy)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.policy, err)
+		}
+		for run := 0; run < 10; run++ {
+			txset := policy(types.HomesteadSigner{}, orderingTestTxs(feeCurrency), nil, rates)
+
+			var have []string
+			for tx, _ := txset.Peek(); tx != nil; tx, _ = txset.Peek() {
+				have = append(have, string(tx.Hash.Bytes()[common.HashLength-2:]))
+				txset.Shift()
+			}
+			if !slices.Equal(have, tt.want) {
+				t.Fatalf("%s: order mismatch: have %v, want %v", tt.policy, have, tt.want)
+			}
+			if !txset.Empty() {
+				t.Fatalf("%s: set not empty after draining", tt.policy)
+			}
+		}
+	}
+}
+
+// Tests that the fair share policy charges the gas of an account switching fee
+// currencies to the currency its account was grouped by.
+func TestOrderingFairShareMixedCurrencies(t *testing.T) {
+	t.Parallel()
+
+	feeCurrency := common.HexToAddress("0xfc")
+	txs := orderingTestTxs(feeCurrency)
+
+	a := txs[common.HexToAddress("0xa")]
+	a[1].FeeCurrency, a[1].Gas = &feeCurrency, 100000
+	txs[common.HexToAddress("0xa")] = append(a, &txpool.LazyTransaction{
+		Hash:      common.BytesToHash([]byte("a2")),
+		Time:      time.Unix(7, 0),
+		GasFeeCap: uint256.NewInt(1),
+		GasTipCap: uint256.NewInt(1),
+		Gas:       21000,
+	})
+	c := txs[common.HexToAddress("0xc")]
+	txs[common.HexToAddress("0xc")] = append(c, &txpool.LazyTransaction{
+		Hash:        common.BytesToHash([]byte("c2")),
+		Time:        time.Unix(8, 0),
+		GasFeeCap:   uint256.NewInt(10),
+		GasTipCap:   uint256.NewInt(10),
+		Gas:         50000,
+		FeeCurrency: &feeCurrency,
+	})
+	policy, _ := LookupOrderingPolicy(OrderingFairShare)
+	txset := policy(types.HomesteadSigner{}, txs, nil, common.ExchangeRates{feeCurrency: big.NewRat(1, 1)})
+
+	var have []string
+	for tx, _ := txset.Peek(); tx != nil; tx, _ = txset.Peek() {
+		have = append(have, string(tx.Hash.Bytes()[common.HashLength-2:]))
+		txset.Shift()
+	}
+	if want := []string{"c0", "b0", "b1", "a0", "c1", "a1", "c2", "a2"}; !slices.Equal(have, want) {
+		t.Fatalf("order mismatch: have %v, want %v", have, want)
+	}
+}
+
+// Tests that popping a transaction drops the remaining ones of its account.
+func TestOrderingPolicyPop(t *testing.T) {
+	t.Parallel()
+
+	feeCurrency := common.HexToAddress("0xfc")
+	for _, name := range []string{OrderingPriority, OrderingFIFO, OrderingFairShare} {
+		policy, _ := LookupOrderingPolicy(name)
+		txset := policy(types.HomesteadSigner{}, orderingTestTxs(feeCurrency), nil, common.ExchangeRates{feeCurrency: big.NewRat(1, 1)})
+
+		count := 0
+		for tx, _ := txset.Peek(); tx != nil; tx, _ = txset.Peek() {
+			count++
+			txset.Pop()
+		}
+		if count != 3 {
+			t.Errorf("%s: popped %d transactions, want 3", name, count)
+		}
+	}
+}
+
+// Tests that custom ordering policies can be registered and selected.
+func TestRegisterOrderingPolicy(t *testing.T) {
+	if _, err := LookupOrderingPolicy("custom"); err == nil {
+		t.Fatal("unknown policy found")
+	}
+	RegisterOrderingPolicy("custom", func(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int, rates common.ExchangeRates) TransactionSet {
+		return newTransactionsByTimeAndNonce(signer, txs, baseFee, rates)
+	})
+	if _, err := LookupOrderingPolicy("custom"); err != nil {
+		t.Fatalf("registered policy not found: %v", err)
+	}
+	if !slices.Contains(OrderingPolicies(), "custom") {
+		t.Fatalf("registered policy not listed: %v", OrderingPolicies())
+	}
+}
+
+// Tests that local transactions are only preferred by the priority ordering,
+// while the other policies order local and remote transactions together.
+func TestFillTransactionsOrdering(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		policy     string
+		localFirst bool
+	}{
+		{OrderingPriority, true},
+		{OrderingFIFO, false},
+	} {
+		var (
+			engine  = ethash.NewFaker()
+			backend = newTestWorkerBackend(t, params.TestChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+			signer  = types.LatestSigner(params.TestChainConfig)
+			config  = testConfig
+		)
+		config.OrderingPolicy = tt.policy
+		miner := New(backend, config, engine)
+
+		build := func() *types.Block {
+			t.Helper()
+			parent := backend.chain.CurrentBlock()
+			res := miner.generateWork(&generateParams{
+				parentHash: parent.Hash(),
+				timestamp:  parent.Time + 1,
+				coinbase:   testBankAddress,
+			})
+			if res.err != nil {
+				t.Fatalf("%s: failed to build block: %v", tt.policy, res.err)
+			}
+			return res.block
+		}
+		// Fund the remote account in a first block
+		fund := types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+			Nonce:    0,
+			To:       &testUserAddress,
+			Value:    big.NewInt(1000000000000000),
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(params.InitialBaseFee),
+		})
+		if err := backend.txPool.Add([]*types.Transaction{fund}, false, true)[0]; err != nil {
+			t.Fatalf("%s: failed to add funding tx: %v", tt.policy, err)
+		}
+		if _, err := backend.chain.InsertChain(types.Blocks{build()}); err != nil {
+			t.Fatalf("%s: failed to insert block: %v", tt.policy, err)
+		}
+		if err := backend.txPool.Sync(); err != nil {
+			t.Fatalf("%s: failed to sync pool: %v", tt.policy, err)
+		}
+		// A remote transaction arriving before a local one
+		remote := types.MustSignNewTx(testUserKey, signer, &types.LegacyTx{
+			Nonce:    0,
+			To:       &testBankAddress,
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(2 * params.InitialBaseFee),
+		})
+		local := types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+			Nonce:    1,
+			To:       &testUserAddress,
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(2 * params.InitialBaseFee),
+		})
+		if err := backend.txPool.Add([]*types.Transaction{remote}, false, true)[0]; err != nil {
+			t.Fatalf("%s: failed to add remote tx: %v", tt.policy, err)
+		}
+		if err := backend.txPool.Add([]*types.Transaction{local}, true, true)[0]; err != nil {
+			t.Fatalf("%s: failed to add local tx: %v", tt.policy, err)
+		}
+		want := []common.Hash{remote.Hash(), local.Hash()}
+		if tt.localFirst {
+			want = []common.Hash{local.Hash(), remote.Hash()}
+		}
+		txs := build().Transactions()
+		if len(txs) != len(want) {
+			t.Fatalf("%s: have %d txs, want %d", tt.policy, len(txs), len(want))
+		}
+		for i, tx := range txs {
+			if tx.Hash() != want[i] {
+				t.Errorf("%s: tx %d mismatch", tt.policy, i)
+			}
+		}
+	}
+}
@@ -246,3 +246,51 @@ func TestCorrectTransactionSortWithFeeCurrency(t *testing.T) {
 		t.Error("expected tx from user1, got the tx from user2")
 	}
 }
+
+func TestCorrectTransactionSortWithCeloDenominatedTx(t *testing.T) {
+	t.Parallel()
+
+	user1, _ := crypto.GenerateKey()
+	user2, _ := crypto.GenerateKey()
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+
+	rates := make(common.ExchangeRates)
+	feeCurrency := common.BigToAddress(common.Big1)
+	rates[feeCurrency] = big.NewRat(10, 1)
+
+	groups := map[common.Address][]*txpool.LazyTransaction{}
+
+	baseFee := big.NewInt(10)
+
+	addr1 := crypto.PubkeyToAddress(user1.PublicKey)
+	// user1 pays in feeCurrency, but the fees are denominated in celo (CIP-66):
+	// 100 - 10 = 90 celo tip. Converting the fields as if they were given in the
+	// fee currency would result in a tip of 0.
+	groups[addr1] = append(groups[addr1], &txpool.LazyTransaction{
+		GasFeeCap:         uint256.NewInt(100),
+		GasTipCap:         uint256.NewInt(100),
+		Gas:               100,
+		FeeCurrency:       &feeCurrency,
+		NativeDenominated: true,
+	})
+
+	addr2 := crypto.PubkeyToAddress(user2.PublicKey)
+	// user2 pays 80 celos, baseFee is 10, tip is 70
+	groups[addr2] = append(groups[addr2], &txpool.LazyTransaction{
+		GasFeeCap: uint256.NewInt(80),
+		GasTipCap: uint256.NewInt(80),
+		Gas:       100,
+	})
+
+	txset := newTransactionsByPriceAndNonce(signer, groups, baseFee, rates)
+
+	auxTx, fees := txset.Peek()
+	if auxTx.FeeCurrency == nil || fees.Cmp(uint256.NewInt(90)) != 0 {
+		t.Errorf("expected tx from user1 with tip 90, got tip %v", fees)
+	}
+	txset.Shift()
+	auxTx, fees = txset.Peek()
+	if auxTx.FeeCurrency != nil || fees.Cmp(uint256.NewInt(70)) != 0 {
+		t.Errorf("expected tx from user2 with tip 70, got tip %v", fees)
+	}
+}
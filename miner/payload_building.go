@@ -91,6 +91,8 @@ type Payload struct {
 	full     *types.Block
 	sidecars []*types.BlobTxSidecar
 	fullFees *big.Int
+	report   *BuildReport // Report of the build selected as full block
+	builds   int          // Number of successful builds
 	stop     chan struct{}
 	lock     sync.Mutex
 	cond     *sync.Cond
@@ -138,6 +140,7 @@ func (payload *Payload) update(r *newPayloadResult, elapsed time.Duration) {
 		return
 	}
 	log.Debug("New payload update", "id", payload.id, "elapsed", common.PrettyDuration(elapsed))
+	payload.builds++
 
 	// Ensure the newly provided full block has a higher transaction fee.
 	// In post-merge stage, there is no uncle reward anymore and transaction
@@ -146,6 +149,7 @@ func (payload *Payload) update(r *newPayloadResult, elapsed time.Duration) {
 		payload.full = r.block
 		payload.fullFees = r.fees
 		payload.sidecars = r.sidecars
+		payload.report = r.report
 
 		feesInEther := new(big.Float).Quo(new(big.Float).SetInt(r.fees), big.NewFloat(params.Ether))
 		log.Info("Updated payload",
@@ -162,6 +166,22 @@ func (payload *Payload) update(r *newPayloadResult, elapsed time.Duration) {
 	}
 }
 
+// recordBuildReport makes the report of the currently selected build of the
+// payload retrievable by the payload id.
+func (miner *Miner) recordBuildReport(payload *Payload) {
+	payload.lock.Lock()
+	report, builds := payload.report, payload.builds
+	payload.lock.Unlock()
+
+	if report == nil {
+		return
+	}
+	// Reports are shared with the RPC once recorded, so update a copy
+	cpy := *report
+	cpy.ID, cpy.Builds = payload.id, builds
+	miner.buildReports.Add(payload.id, &cpy)
+}
+
 // Resolve returns the latest built payload and also terminates the background
 // thread for updating payload. It's safe to be called multiple times.
 func (payload *Payload) Resolve() *engine.ExecutionPayloadEnvelope {
@@ -183,10 +203,15 @@ func (payload *Payload) ResolveFull() *engine.ExecutionPayloadEnvelope {
 	return payload.resolve(true)
 }
 
+// WaitFull blocks until the full block of the payload has been built, or
+// building it failed.
 func (payload *Payload) WaitFull() {
 	payload.lock.Lock()
 	defer payload.lock.Unlock()
-	payload.cond.Wait()
+
+	for payload.full == nil && payload.err == nil {
+		payload.cond.Wait()
+	}
 }
 
 func (payload *Payload) resolve(onlyFull bool) *engine.ExecutionPayloadEnvelope {
@@ -279,7 +304,10 @@ func (miner *Miner) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
 		// make sure to make it appear as full, otherwise it will wait indefinitely for payload building to complete.
 		payload.full = empty.block
 		payload.fullFees = empty.fees
+		payload.report = empty.report
+		payload.builds = 1
 		payload.cond.Broadcast() // unblocks Resolve
+		miner.recordBuildReport(payload)
 		return payload, nil
 	}
 
@@ -338,6 +366,7 @@ func (miner *Miner) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
 			dur := time.Since(start)
 			// update handles error case
 			payload.update(r, dur)
+			miner.recordBuildReport(payload)
 			if r.err == nil {
 				// after first successful pass, we're updating
 				fullParams.isUpdate = true
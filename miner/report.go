@@ -0,0 +1,163 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/contracts"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// maxBuildReports is the number of payload build reports retained.
+const maxBuildReports = 64
+
+// Reasons a transaction was skipped while building a block.
+const (
+	SkipFeeCurrencyNotAllowed   = "fee-currency-not-allowed"
+	SkipFeeCurrencyGasExhausted = "fee-currency-gas-exhausted"
+	SkipFeeCurrencyEVMCall      = "fee-currency-evm-call"
+	SkipGasLimitReached         = "gas-limit-reached"
+	SkipBlobGasLimitReached     = "blob-gas-limit-reached"
+	SkipEvicted                 = "evicted"
+	SkipReplayProtected         = "replay-protected"
+	SkipNonceTooLow             = "nonce-too-low"
+	SkipNonceTooHigh            = "nonce-too-high"
+	SkipBundleFailed            = "bundle-failed"
+	SkipConditional             = "conditional-failed"
+	SkipInvalid                 = "invalid"
+)
+
+// SkippedTx is a transaction which was considered for a block but not included.
+type SkippedTx struct {
+	Hash        common.Hash     `json:"hash"`
+	FeeCurrency *common.Address `json:"feeCurrency,omitempty"`
+	Reason      string          `json:"reason"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// BuildPhases are the durations of the phases of building a block.
+type BuildPhases struct {
+	Prepare   time.Duration `json:"prepare"`   // Header and state setup
+	ForcedTxs time.Duration `json:"forcedTxs"` // Transactions forced by the engine API
+	Bundles   time.Duration `json:"bundles"`   // Bundle inclusion
+	Fill      time.Duration `json:"fill"`      // Transaction pool inclusion
+	Finalize  time.Duration `json:"finalize"`  // Block assembly
+}
+
+// BuildReport describes how a payload was built: which transactions were
+// considered, which were included and why the others were skipped. Durations
+// are in nanoseconds.
+type BuildReport struct {
+	ID         engine.PayloadID `json:"id"`
+	Number     uint64           `json:"number"`
+	ParentHash common.Hash      `json:"parentHash"`
+	Timestamp  uint64           `json:"timestamp"`
+	Builds     int              `json:"builds"` // Number of builds of the payload so far
+
+	Considered int           `json:"considered"`
+	Included   []common.Hash `json:"included"`
+	Skipped    []*SkippedTx  `json:"skipped"`
+	StopReason string        `json:"stopReason,omitempty"`
+
+	GasUsed            uint64                    `json:"gasUsed"`
+	FeeCurrencyGasUsed map[common.Address]uint64 `json:"feeCurrencyGasUsed"` // Native currency under the zero address
+	Phases             BuildPhases               `json:"phases"`
+	Duration           time.Duration             `json:"duration"`
+}
+
+// newBuildReport creates an empty report for the block being built on header.
+func newBuildReport(header *types.Header) *BuildReport {
+	return &BuildReport{
+		Number:             header.Number.Uint64(),
+		ParentHash:         header.ParentHash,
+		Timestamp:          header.Time,
+		Included:           []common.Hash{},
+		Skipped:            []*SkippedTx{},
+		FeeCurrencyGasUsed: make(map[common.Address]uint64),
+	}
+}
+
+// consider records that a transaction was picked for inclusion. The report may
+// be nil, in which case nothing is recorded.
+func (r *BuildReport) consider() {
+	if r != nil {
+		r.Considered++
+	}
+}
+
+// include records an included transaction and the gas it used.
+func (r *BuildReport) include(tx *types.Transaction, gasUsed uint64) {
+	if r == nil {
+		return
+	}
+	r.Included = append(r.Included, tx.Hash())
+	r.GasUsed += gasUsed
+	r.FeeCurrencyGasUsed[feeCurrencyKey(tx.FeeCurrency())] += gasUsed
+}
+
+// skip records a transaction which was not included, and why.
+func (r *BuildReport) skip(hash common.Hash, feeCurrency *common.Address, reason string, err error) {
+	if r == nil {
+		return
+	}
+	skipped := &SkippedTx{Hash: hash, FeeCurrency: feeCurrency, Reason: reason}
+	if err != nil {
+		skipped.Error = err.Error()
+	}
+	r.Skipped = append(r.Skipped, skipped)
+}
+
+// stop records why the filling of the block ended early.
+func (r *BuildReport) stop(reason string) {
+	if r != nil {
+		r.StopReason = reason
+	}
+}
+
+// skipReason classifies the error of a transaction which failed to apply.
+func skipReason(err error) string {
+	switch {
+	case errors.Is(err, contracts.ErrFeeCurrencyEVMCall):
+		return SkipFeeCurrencyEVMCall
+	case errors.Is(err, core.ErrNonceTooLow):
+		return SkipNonceTooLow
+	case errors.Is(err, core.ErrNonceTooHigh):
+		return SkipNonceTooHigh
+	case errors.Is(err, core.ErrGasLimitReached):
+		return SkipGasLimitReached
+	default:
+		return SkipInvalid
+	}
+}
+
+// newBuildReportCache creates the cache retaining the recent build reports.
+func newBuildReportCache() *lru.Cache[engine.PayloadID, *BuildReport] {
+	return lru.NewCache[engine.PayloadID, *BuildReport](maxBuildReports)
+}
+
+// BuildReport returns the report of the block selected for the payload with
+// the given id, or nil if it's unknown.
+func (miner *Miner) BuildReport(id engine.PayloadID) *BuildReport {
+	report, _ := miner.buildReports.Get(id)
+	return report
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that building a payload records which transactions were included and
+// why the others were skipped, retrievable by the payload id.
+func TestBuildReport(t *testing.T) {
+	t.Parallel()
+
+	w, b := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+
+	// The user can't pay for the bundled transaction
+	unpayable := newBundleTestTxs()[1]
+	if _, err := w.AddBundle(&Bundle{Txs: types.Transactions{unpayable}, BlockNumber: 1}); err != nil {
+		t.Fatalf("failed to add bundle: %v", err)
+	}
+	args := &BuildPayloadArgs{
+		Parent:       b.chain.CurrentBlock().Hash(),
+		Timestamp:    uint64(time.Now().Unix()),
+		FeeRecipient: common.HexToAddress("0xdeadbeef"),
+	}
+	payload, err := w.buildPayload(args)
+	if err != nil {
+		t.Fatalf("failed to build payload: %v", err)
+	}
+	payload.WaitFull()
+
+	// The report is recorded right after the build is delivered to the payload
+	var report *BuildReport
+	for deadline := time.Now().Add(time.Second); report == nil && time.Now().Before(deadline); {
+		if report = w.BuildReport(args.Id()); report == nil {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	if report == nil {
+		t.Fatal("no build report for payload")
+	}
+	if report.ID != args.Id() || report.Number != 1 || report.Builds < 1 {
+		t.Errorf("report header mismatch: id %v, number %d, builds %d", report.ID, report.Number, report.Builds)
+	}
+	if report.Considered != 2 {
+		t.Errorf("considered txs mismatch: have %d, want 2", report.Considered)
+	}
+	if len(report.Included) != 1 || report.Included[0] != pendingTxs[0].Hash() {
+		t.Errorf("included txs mismatch: %v", report.Included)
+	}
+	if len(report.Skipped) != 1 || report.Skipped[0].Hash != unpayable.Hash() || report.Skipped[0].Reason != SkipBundleFailed || report.Skipped[0].Error == "" {
+		t.Errorf("skipped txs mismatch: %+v", report.Skipped)
+	}
+	if report.GasUsed != params.TxGas || report.FeeCurrencyGasUsed[common.Address{}] != params.TxGas {
+		t.Errorf("gas used mismatch: total %d, per currency %v", report.GasUsed, report.FeeCurrencyGasUsed)
+	}
+	if report.Duration == 0 || report.Phases.Fill == 0 {
+		t.Errorf("build durations not recorded: %+v", report.Phases)
+	}
+	if w.BuildReport((&BuildPayloadArgs{}).Id()) != nil {
+		t.Error("report for unknown payload")
+	}
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/holiman/uint256"
 )
@@ -51,6 +52,9 @@ var (
 	errBlockInterruptedByRecommit = errors.New("recommit interrupt while building block")
 	errBlockInterruptedByTimeout  = errors.New("timeout while building block")
 	errBlockInterruptedByResolve  = errors.New("payload resolution while building block")
+
+	txConditionalRejectedMeter = metrics.NewRegisteredMeter("miner/transactionConditional/rejected", nil)
+	txConditionalMinedMeter    = metrics.NewRegisteredMeter("miner/transactionConditional/mined", nil)
 )
 
 // environment is the worker's current environment and holds all
@@ -72,6 +76,8 @@ type environment struct {
 	multiGasPool         *core.MultiGasPool // available per-fee-currency gas used to pack transactions
 	feeCurrencyAllowlist common.AddressSet
 	feeCurrencyContext   *common.FeeCurrencyContext
+
+	report *BuildReport // Report of the block being built, nil if not recorded
 }
 
 const (
@@ -90,6 +96,7 @@ type newPayloadResult struct {
 	sidecars []*types.BlobTxSidecar // collected blobs of blob transactions
 	stateDB  *state.StateDB         // StateDB after executing the transactions
 	receipts []*types.Receipt       // Receipts collected during construction
+	report   *BuildReport           // How the block was built
 }
 
 // generateParams wraps various settings for generating sealing task.
@@ -109,27 +116,38 @@ type generateParams struct {
 	isUpdate  bool               // Optional flag indicating that this is building a discardable update
 }
 
-// generateWork generates a sealing block based on the given parameters.
-func (miner *Miner) generateWork(params *generateParams) *newPayloadResult {
-	work, err := miner.prepareWork(params)
-	if err != nil {
-		return &newPayloadResult{err: err}
-	}
-	if work.gasPool == nil {
+// initGasPools sets up the block and per fee currency gas pools of the
+// environment, if not set yet.
+func (miner *Miner) initGasPools(env *environment) {
+	if env.gasPool == nil {
 		gasLimit := miner.config.EffectiveGasCeil
-		if gasLimit == 0 || gasLimit > work.header.GasLimit {
-			gasLimit = work.header.GasLimit
+		if gasLimit == 0 || gasLimit > env.header.GasLimit {
+			gasLimit = env.header.GasLimit
 		}
-		work.gasPool = new(core.GasPool).AddGas(gasLimit)
+		env.gasPool = new(core.GasPool).AddGas(gasLimit)
 	}
-	if work.multiGasPool == nil {
-		work.multiGasPool = core.NewMultiGasPool(
-			work.header.GasLimit,
-			work.feeCurrencyAllowlist,
+	if env.multiGasPool == nil {
+		env.multiGasPool = core.NewMultiGasPool(
+			env.header.GasLimit,
+			env.feeCurrencyAllowlist,
 			miner.config.FeeCurrencyDefault,
 			miner.config.FeeCurrencyLimits,
 		)
 	}
+}
+
+// generateWork generates a sealing block based on the given parameters.
+func (miner *Miner) generateWork(params *generateParams) *newPayloadResult {
+	start := time.Now()
+	work, err := miner.prepareWork(params)
+	if err != nil {
+		return &newPayloadResult{err: err}
+	}
+	report := newBuildReport(work.header)
+	work.report = report
+	report.Phases.Prepare = time.Since(start)
+
+	miner.initGasPools(work)
 	misc.EnsureCreate2Deployer(miner.chainConfig, work.header.Time, work.state)
 
 	for _, tx := range params.txs {
@@ -144,8 +162,16 @@ func (miner *Miner) generateWork(params *generateParams) *newPayloadResult {
 		// with the work.gasPool.
 		work.multiGasPool.PoolFor(nil).SubGas(tx.Gas())
 		work.tcount++
+		report.include(tx, work.receipts[len(work.receipts)-1].GasUsed)
 	}
+	report.Phases.ForcedTxs = time.Since(start) - report.Phases.Prepare
+
 	if !params.noTxs {
+		phase := time.Now()
+		miner.commitBundles(work)
+		report.Phases.Bundles = time.Since(phase)
+		phase = time.Now()
+
 		// use shared interrupt if present
 		interrupt := params.interrupt
 		if interrupt == nil {
@@ -157,6 +183,10 @@ func (miner *Miner) generateWork(params *generateParams) *newPayloadResult {
 
 		err := miner.fillTransactions(interrupt, work)
 		timer.Stop() // don't need timeout interruption any more
+		report.Phases.Fill = time.Since(phase)
+		if err != nil {
+			report.stop(err.Error())
+		}
 		if errors.Is(err, errBlockInterruptedByTimeout) {
 			log.Warn("Block building is interrupted", "allowance", common.PrettyDuration(miner.config.Recommit))
 		} else if errors.Is(err, errBlockInterruptedByResolve) {
@@ -167,17 +197,22 @@ func (miner *Miner) generateWork(params *generateParams) *newPayloadResult {
 		return &newPayloadResult{err: errInterruptedUpdate}
 	}
 
+	phase := time.Now()
 	body := types.Body{Transactions: work.txs, Withdrawals: params.withdrawals}
 	block, err := miner.engine.FinalizeAndAssemble(miner.chain, work.header, work.state, &body, work.receipts)
 	if err != nil {
 		return &newPayloadResult{err: err}
 	}
+	report.Phases.Finalize = time.Since(phase)
+	report.Duration = time.Since(start)
+
 	return &newPayloadResult{
 		block:    block,
 		fees:     totalFees(block, work.receipts),
 		sidecars: work.sidecars,
 		stateDB:  work.state,
 		receipts: work.receipts,
+		report:   report,
 	}
 }
 
@@ -275,6 +310,12 @@ func (miner *Miner) prepareWork(genParams *generateParams) (*environment, error)
 		common.CurrencyAllowlist(env.feeCurrencyContext.ExchangeRates),
 		header,
 	)
+	// Don't build on exchange rates the local policy doesn't trust. Imported
+	// blocks are still validated with the rates from the state.
+	celoBackend := &contracts.CeloBackend{ChainConfig: miner.chainConfig, State: env.state}
+	for currency := range miner.exchangeRateGuard.Excluded(parent, header.Time, celoBackend, env.feeCurrencyContext.ExchangeRates) {
+		delete(env.feeCurrencyAllowlist, currency)
+	}
 
 	if header.ParentBeaconRoot != nil {
 		vmenv := vm.NewEVM(context, vm.TxContext{}, env.state, miner.chainConfig, vm.Config{})
@@ -376,7 +417,7 @@ func (miner *Miner) applyTransaction(env *environment, tx *types.Transaction) (*
 	return receipt, err
 }
 
-func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *transactionsByPriceAndNonce, interrupt *atomic.Int32) error {
+func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs TransactionSet, interrupt *atomic.Int32) error {
 	gasLimit := env.header.GasLimit
 	if env.gasPool == nil {
 		env.gasPool = new(core.GasPool).AddGas(gasLimit)
@@ -399,6 +440,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		// If we don't have enough gas for any further transactions then we're done.
 		if env.gasPool.Gas() < params.TxGas {
 			log.Trace("Not enough gas for further transactions", "have", env.gasPool, "want", params.TxGas)
+			env.report.stop(SkipGasLimitReached)
 			break
 		}
 		// If we don't have enough blob space for any further blob transactions,
@@ -411,7 +453,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		// Retrieve the next transaction and abort if all done.
 		var (
 			ltx *txpool.LazyTransaction
-			txs *transactionsByPriceAndNonce
+			txs TransactionSet
 		)
 		pltx, ptip := plainTxs.Peek()
 		bltx, btip := blobTxs.Peek()
@@ -431,9 +473,11 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		if ltx == nil {
 			break
 		}
+		env.report.consider()
 		if ltx.FeeCurrency != nil {
 			if _, ok := env.feeCurrencyAllowlist[*ltx.FeeCurrency]; !ok {
 				log.Trace("Fee-currency not in local allowlist", "hash", ltx.Hash, "fee-currency", ltx.FeeCurrency)
+				env.report.skip(ltx.Hash, ltx.FeeCurrency, SkipFeeCurrencyNotAllowed, nil)
 				txs.Pop()
 				continue
 			}
@@ -441,11 +485,13 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		// If we don't have enough space for the next transaction, skip the account.
 		if env.gasPool.Gas() < ltx.Gas {
 			log.Trace("Not enough gas left for transaction", "hash", ltx.Hash, "left", env.gasPool.Gas(), "needed", ltx.Gas)
+			env.report.skip(ltx.Hash, ltx.FeeCurrency, SkipGasLimitReached, nil)
 			txs.Pop()
 			continue
 		}
 		if left := uint64(params.MaxBlobGasPerBlock - env.blobs*params.BlobTxBlobGasPerBlob); left < ltx.BlobGas {
 			log.Trace("Not enough blob gas left for transaction", "hash", ltx.Hash, "left", left, "needed", ltx.BlobGas)
+			env.report.skip(ltx.Hash, ltx.FeeCurrency, SkipBlobGasLimitReached, nil)
 			txs.Pop()
 			continue
 		}
@@ -455,6 +501,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 				"currency", ltx.FeeCurrency, "hash", ltx.Hash,
 				"left", left, "needed", ltx.Gas,
 			)
+			env.report.skip(ltx.Hash, ltx.FeeCurrency, SkipFeeCurrencyGasExhausted, nil)
 			txs.Pop()
 			continue
 		}
@@ -462,6 +509,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		tx := ltx.Resolve()
 		if tx == nil {
 			log.Trace("Ignoring evicted transaction", "hash", ltx.Hash)
+			env.report.skip(ltx.Hash, ltx.FeeCurrency, SkipEvicted, nil)
 			txs.Pop()
 			continue
 		}
@@ -473,9 +521,25 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		// phase, start ignoring the sender until we do.
 		if tx.Protected() && !miner.chainConfig.IsEIP155(env.header.Number) {
 			log.Trace("Ignoring replay protected transaction", "hash", ltx.Hash, "eip155", miner.chainConfig.EIP155Block)
+			env.report.skip(ltx.Hash, ltx.FeeCurrency, SkipReplayProtected, nil)
 			txs.Pop()
 			continue
 		}
+		// Check the inclusion conditions of the transaction, if any. Transactions
+		// whose conditional may still hold in a later block are only skipped,
+		// the rest are rejected and evicted from the pool on the next reset.
+		if cond := tx.Conditional(); cond != nil {
+			if err := checkConditional(env, cond); err != nil {
+				log.Debug("Transaction conditional failed", "hash", ltx.Hash, "err", err)
+				if !errors.Is(err, types.ErrConditionalBlockMin) && !errors.Is(err, types.ErrConditionalTimeMin) {
+					txConditionalRejectedMeter.Mark(1)
+					tx.SetRejected()
+				}
+				env.report.skip(ltx.Hash, ltx.FeeCurrency, SkipConditional, err)
+				txs.Pop()
+				continue
+			}
+		}
 		// Start executing the transaction
 		env.state.SetTxContext(tx.Hash(), env.tcount)
 
@@ -486,6 +550,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		case errors.Is(err, core.ErrNonceTooLow):
 			// New head notification data race between the transaction pool and miner, shift
 			log.Trace("Skipping transaction with low nonce", "hash", ltx.Hash, "sender", from, "nonce", tx.Nonce())
+			env.report.skip(ltx.Hash, ltx.FeeCurrency, SkipNonceTooLow, err)
 			txs.Shift()
 
 		case errors.Is(err, nil):
@@ -507,21 +572,38 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 			}
 
 			// Everything ok, collect the logs and shift in the next transaction from the same account
+			if tx.Conditional() != nil {
+				txConditionalMinedMeter.Mark(1)
+			}
+			env.report.include(tx, gasUsed)
 			txs.Shift()
 
 		default:
 			// Transaction is regarded as invalid, drop all consecutive transactions from
 			// the same sender because of `nonce-too-high` clause.
 			log.Debug("Transaction failed, account skipped", "hash", ltx.Hash, "err", err)
+			env.report.skip(ltx.Hash, ltx.FeeCurrency, skipReason(err), err)
 			txs.Pop()
 		}
 	}
 	return nil
 }
 
+// checkConditional checks the inclusion conditions of a transaction against the
+// block being built.
+func checkConditional(env *environment, cond *types.TransactionConditional) error {
+	if err := cond.CheckBlockNumber(env.header.Number); err != nil {
+		return err
+	}
+	if err := cond.CheckTimestamp(env.header.Time); err != nil {
+		return err
+	}
+	return env.state.CheckTransactionConditional(cond)
+}
+
 // fillTransactions retrieves the pending transactions from the txpool and fills them
-// into the given sealing block. The transaction selection and ordering strategy can
-// be customized with the plugin in the future.
+// into the given sealing block. The transactions are ordered by the configured
+// ordering policy.
 func (miner *Miner) fillTransactions(interrupt *atomic.Int32, env *environment) error {
 	miner.confMu.RLock()
 	tip := miner.config.GasPrice
@@ -543,32 +625,36 @@ func (miner *Miner) fillTransactions(interrupt *atomic.Int32, env *environment)
 	filter.OnlyPlainTxs, filter.OnlyBlobTxs = false, true
 	pendingBlobTxs := miner.txpool.Pending(filter)
 
-	// Split the pending transactions into locals and remotes.
+	// Split the pending transactions into locals and remotes. Only the priority
+	// ordering prefers local transactions, the other policies order all of them
+	// as a single set.
 	localPlainTxs, remotePlainTxs := make(map[common.Address][]*txpool.LazyTransaction), pendingPlainTxs
 	localBlobTxs, remoteBlobTxs := make(map[common.Address][]*txpool.LazyTransaction), pendingBlobTxs
 
-	for _, account := range miner.txpool.Locals() {
-		if txs := remotePlainTxs[account]; len(txs) > 0 {
-			delete(remotePlainTxs, account)
-			localPlainTxs[account] = txs
-		}
-		if txs := remoteBlobTxs[account]; len(txs) > 0 {
-			delete(remoteBlobTxs, account)
-			localBlobTxs[account] = txs
+	if miner.localsFirst {
+		for _, account := range miner.txpool.Locals() {
+			if txs := remotePlainTxs[account]; len(txs) > 0 {
+				delete(remotePlainTxs, account)
+				localPlainTxs[account] = txs
+			}
+			if txs := remoteBlobTxs[account]; len(txs) > 0 {
+				delete(remoteBlobTxs, account)
+				localBlobTxs[account] = txs
+			}
 		}
 	}
 	// Fill the block with all available pending transactions.
 	if len(localPlainTxs) > 0 || len(localBlobTxs) > 0 {
-		plainTxs := newTransactionsByPriceAndNonce(env.signer, localPlainTxs, env.header.BaseFee, env.feeCurrencyContext.ExchangeRates)
-		blobTxs := newTransactionsByPriceAndNonce(env.signer, localBlobTxs, env.header.BaseFee, env.feeCurrencyContext.ExchangeRates)
+		plainTxs := miner.ordering(env.signer, localPlainTxs, env.header.BaseFee, env.feeCurrencyContext.ExchangeRates)
+		blobTxs := miner.ordering(env.signer, localBlobTxs, env.header.BaseFee, env.feeCurrencyContext.ExchangeRates)
 
 		if err := miner.commitTransactions(env, plainTxs, blobTxs, interrupt); err != nil {
 			return err
 		}
 	}
 	if len(remotePlainTxs) > 0 || len(remoteBlobTxs) > 0 {
-		plainTxs := newTransactionsByPriceAndNonce(env.signer, remotePlainTxs, env.header.BaseFee, env.feeCurrencyContext.ExchangeRates)
-		blobTxs := newTransactionsByPriceAndNonce(env.signer, remoteBlobTxs, env.header.BaseFee, env.feeCurrencyContext.ExchangeRates)
+		plainTxs := miner.ordering(env.signer, remotePlainTxs, env.header.BaseFee, env.feeCurrencyContext.ExchangeRates)
+		blobTxs := miner.ordering(env.signer, remoteBlobTxs, env.header.BaseFee, env.feeCurrencyContext.ExchangeRates)
 
 		if err := miner.commitTransactions(env, plainTxs, blobTxs, interrupt); err != nil {
 			return err
@@ -102,6 +102,9 @@ type Config struct {
 	// relative), then that specific path is enforced. An empty path disables IPC.
 	IPCPath string
 
+	// IPCMethods restricts the methods served via the IPC endpoint.
+	IPCMethods rpc.MethodFilter `toml:",omitempty"`
+
 	// HTTPHost is the host interface on which to start the HTTP RPC server. If this
 	// field is empty, no HTTP API endpoint will be started.
 	HTTPHost string
@@ -130,6 +133,10 @@ type Config struct {
 	// exposed.
 	HTTPModules []string
 
+	// HTTPMethods restricts the methods served via the HTTP RPC interface beyond
+	// the selected modules, e.g. to expose only the tracing methods of "debug".
+	HTTPMethods rpc.MethodFilter `toml:",omitempty"`
+
 	// HTTPTimeouts allows for customization of the timeout values used by the HTTP RPC
 	// interface.
 	HTTPTimeouts rpc.HTTPTimeouts
@@ -137,6 +144,10 @@ type Config struct {
 	// HTTPPathPrefix specifies a path prefix on which http-rpc is to be served.
 	HTTPPathPrefix string `toml:",omitempty"`
 
+	// HTTPSSE enables subscriptions over HTTP, served as Server-Sent Events to
+	// requests accepting an event stream.
+	HTTPSSE bool `toml:",omitempty"`
+
 	// AuthAddr is the listening address on which authenticated APIs are provided.
 	AuthAddr string `toml:",omitempty"`
 
@@ -147,6 +158,9 @@ type Config struct {
 	// for the authenticated api. This is by default {'localhost'}.
 	AuthVirtualHosts []string `toml:",omitempty"`
 
+	// AuthMethods restricts the methods served via the authenticated APIs.
+	AuthMethods rpc.MethodFilter `toml:",omitempty"`
+
 	// WSHost is the host interface on which to start the websocket RPC server. If
 	// this field is empty, no websocket API endpoint will be started.
 	WSHost string
@@ -169,6 +183,10 @@ type Config struct {
 	// exposed.
 	WSModules []string
 
+	// WSMethods restricts the methods served via the websocket RPC interface beyond
+	// the selected modules.
+	WSMethods rpc.MethodFilter `toml:",omitempty"`
+
 	// WSExposeAll exposes all API modules via the WebSocket RPC interface rather
 	// than just the public ones.
 	//
@@ -204,6 +222,14 @@ type Config struct {
 	// BatchResponseMaxSize is the maximum number of bytes returned from a batched rpc call.
 	BatchResponseMaxSize int `toml:",omitempty"`
 
+	// RPCRateLimit configures per-client rate limiting of the HTTP and WebSocket
+	// RPC endpoints. The authenticated endpoints are not rate limited.
+	RPCRateLimit rpc.RateLimitConfig `toml:",omitempty"`
+
+	// RPCResponseCache is the size in bytes of the cache of HTTP and WebSocket RPC
+	// results tied to finalized blocks, 0 disables caching.
+	RPCResponseCache int `toml:",omitempty"`
+
 	// JWTSecret is the path to the hex-encoded jwt secret.
 	JWTSecret string `toml:",omitempty"`
 
@@ -52,6 +52,19 @@ var (
 	DefaultAuthModules = []string{"eth", "engine"}
 )
 
+// DefaultRPCMethodCosts are the rate limiting costs of expensive RPC methods,
+// relative to the cost of 1 of other methods.
+var DefaultRPCMethodCosts = map[string]int{
+	"debug_traceBlock*":      100,
+	"debug_traceChain":       100,
+	"debug_traceTransaction": 50,
+	"debug_traceCall":        50,
+	"eth_getLogs":            20,
+	"eth_call":               5,
+	"eth_estimateGas":        5,
+	"eth_createAccessList":   5,
+}
+
 // DefaultConfig contains reasonable default settings.
 var DefaultConfig = Config{
 	DataDir:              DefaultDataDir(),
@@ -66,6 +79,7 @@ var DefaultConfig = Config{
 	WSModules:            []string{"net", "web3"},
 	BatchRequestLimit:    1000,
 	BatchResponseMaxSize: 25 * 1000 * 1000,
+	RPCRateLimit:         rpc.RateLimitConfig{MethodCosts: DefaultRPCMethodCosts},
 	GraphQLVirtualHosts:  []string{"localhost"},
 	P2P: p2p.Config{
 		ListenAddr: ":30303",
@@ -41,6 +41,21 @@ func newJWTHandler(secret []byte, next http.Handler) http.Handler {
 	}
 }
 
+// jwtClaim returns the string value of the given claim in the bearer token of the
+// request. The token is not verified, which is left to the jwtHandler.
+func jwtClaim(r *http.Request, claim string) string {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return ""
+	}
+	var claims jwt.MapClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(strings.TrimPrefix(auth, "Bearer "), &claims); err != nil {
+		return ""
+	}
+	value, _ := claims[claim].(string)
+	return value
+}
+
 // ServeHTTP implements http.Handler
 func (handler *jwtHandler) ServeHTTP(out http.ResponseWriter, r *http.Request) {
 	var (
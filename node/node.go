@@ -66,6 +66,8 @@ type Node struct {
 	ipc           *ipcServer  // Stores information about the ipc http server
 	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
 
+	responseCache *rpc.ResponseCache // Cache of finalized results served over HTTP and WebSocket, nil if disabled
+
 	databases map[*closeTrackingDB]struct{} // All open databases
 }
 
@@ -114,6 +116,9 @@ func New(conf *Config) (*Node, error) {
 		server:        &p2p.Server{Config: conf.P2P},
 		databases:     make(map[*closeTrackingDB]struct{}),
 	}
+	if conf.RPCResponseCache > 0 {
+		node.responseCache = rpc.NewResponseCache(conf.RPCResponseCache)
+	}
 
 	// Register built-in APIs.
 	node.rpcAPIs = append(node.rpcAPIs, node.apis()...)
@@ -393,7 +398,7 @@ func (n *Node) startRPC() error {
 
 	// Configure IPC.
 	if n.ipc.endpoint != "" {
-		if err := n.ipc.start(apis); err != nil {
+		if err := n.ipc.start(apis, n.config.IPCMethods); err != nil {
 			return err
 		}
 	}
@@ -405,18 +410,26 @@ func (n *Node) startRPC() error {
 	rpcConfig := rpcEndpointConfig{
 		batchItemLimit:         n.config.BatchRequestLimit,
 		batchResponseSizeLimit: n.config.BatchResponseMaxSize,
+		responseCache:          n.responseCache,
+	}
+	if limit := n.config.RPCRateLimit; limit.Rate > 0 {
+		rpcConfig.rateLimiter = rpc.NewRateLimiter(limit)
+		rpcConfig.rateLimitKeyHeader = limit.KeyHeader
 	}
 
 	initHttp := func(server *httpServer, port int) error {
 		if err := server.setListenAddr(n.config.HTTPHost, port); err != nil {
 			return err
 		}
+		endpointConfig := rpcConfig
+		endpointConfig.methods = n.config.HTTPMethods
 		if err := server.enableRPC(openAPIs, httpConfig{
 			CorsAllowedOrigins: n.config.HTTPCors,
 			Vhosts:             n.config.HTTPVirtualHosts,
 			Modules:            n.config.HTTPModules,
 			prefix:             n.config.HTTPPathPrefix,
-			rpcEndpointConfig:  rpcConfig,
+			sse:                n.config.HTTPSSE,
+			rpcEndpointConfig:  endpointConfig,
 		}); err != nil {
 			return err
 		}
@@ -429,11 +442,13 @@ func (n *Node) startRPC() error {
 		if err := server.setListenAddr(n.config.WSHost, port); err != nil {
 			return err
 		}
+		endpointConfig := rpcConfig
+		endpointConfig.methods = n.config.WSMethods
 		if err := server.enableWS(openAPIs, wsConfig{
 			Modules:           n.config.WSModules,
 			Origins:           n.config.WSOrigins,
 			prefix:            n.config.WSPathPrefix,
-			rpcEndpointConfig: rpcConfig,
+			rpcEndpointConfig: endpointConfig,
 		}); err != nil {
 			return err
 		}
@@ -452,6 +467,14 @@ func (n *Node) startRPC() error {
 			batchItemLimit:         engineAPIBatchItemLimit,
 			batchResponseSizeLimit: engineAPIBatchResponseSizeLimit,
 			httpBodyLimit:          engineAPIBodyLimit,
+			methods:                n.config.AuthMethods,
+		}
+		// Authenticated clients are trusted, only those identified by a claim
+		// with a quota are rate limited.
+		if limit := n.config.RPCRateLimit; limit.KeyClaim != "" && len(limit.Quotas) > 0 {
+			limit.Rate = 0
+			sharedConfig.rateLimiter = rpc.NewRateLimiter(limit)
+			sharedConfig.rateLimitKeyClaim = limit.KeyClaim
 		}
 		err := server.enableRPC(allAPIs, httpConfig{
 			CorsAllowedOrigins: DefaultAuthCors,
@@ -623,6 +646,12 @@ func (n *Node) Attach() *rpc.Client {
 	return rpc.DialInProc(n.inprocHandler)
 }
 
+// ResponseCache returns the cache of RPC results tied to finalized blocks, or nil
+// if response caching is disabled. Backends set its finality function.
+func (n *Node) ResponseCache() *rpc.ResponseCache {
+	return n.responseCache
+}
+
 // RPCHandler returns the in-process RPC request handler.
 func (n *Node) RPCHandler() (*rpc.Server, error) {
 	n.lock.Lock()
@@ -42,6 +42,7 @@ type httpConfig struct {
 	CorsAllowedOrigins []string
 	Vhosts             []string
 	prefix             string // path prefix on which to mount http handler
+	sse                bool   // serve subscriptions as Server-Sent Events
 	rpcEndpointConfig
 }
 
@@ -58,6 +59,11 @@ type rpcEndpointConfig struct {
 	batchItemLimit         int
 	batchResponseSizeLimit int
 	httpBodyLimit          int
+	rateLimiter            *rpc.RateLimiter   // optional rate limiter shared by the endpoints
+	rateLimitKeyHeader     string             // header carrying the client key of rate limiting
+	rateLimitKeyClaim      string             // JWT claim carrying the client key of rate limiting
+	responseCache          *rpc.ResponseCache // optional cache of finalized results shared by the endpoints
+	methods                rpc.MethodFilter   // restricts the methods served beyond the modules
 }
 
 type rpcHandler struct {
@@ -309,12 +315,19 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit)
 	}
+	srv.SetRateLimiter(config.rateLimiter)
+	srv.SetResponseCache(config.responseCache)
+	srv.SetMethodFilter(config.methods)
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
+	var handler http.Handler = srv
+	if config.sse {
+		handler = newSSEHandler(srv)
+	}
 	h.httpConfig = config
 	h.httpHandler.Store(&rpcHandler{
-		Handler: NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
+		Handler: NewHTTPHandlerStack(config.newClientKeyHandler(handler), config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
 		server:  srv,
 	})
 	return nil
@@ -344,12 +357,15 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit)
 	}
+	srv.SetRateLimiter(config.rateLimiter)
+	srv.SetResponseCache(config.responseCache)
+	srv.SetMethodFilter(config.methods)
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
 	h.wsConfig = config
 	h.wsHandler.Store(&rpcHandler{
-		Handler: NewWSHandlerStack(srv.WebsocketHandler(config.Origins), config.jwtSecret),
+		Handler: NewWSHandlerStack(config.newClientKeyHandler(srv.WebsocketHandler(config.Origins)), config.jwtSecret),
 		server:  srv,
 	})
 	return nil
@@ -393,6 +409,19 @@ func isWebsocket(r *http.Request) bool {
 		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
 }
 
+// newSSEHandler returns a handler serving requests accepting an event stream as
+// subscriptions, and all other requests as regular JSON-RPC.
+func newSSEHandler(srv *rpc.Server) http.Handler {
+	sse := srv.SSEHandler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rpc.IsSSERequest(r) {
+			sse.ServeHTTP(w, r)
+			return
+		}
+		srv.ServeHTTP(w, r)
+	})
+}
+
 // NewHTTPHandlerStack returns wrapped http-related handlers
 func NewHTTPHandlerStack(srv http.Handler, cors []string, vhosts []string, jwtSecret []byte) http.Handler {
 	// Wrap the CORS-handler within a host-handler
@@ -412,6 +441,39 @@ func NewWSHandlerStack(srv http.Handler, jwtSecret []byte) http.Handler {
 	return srv
 }
 
+// newClientKeyHandler returns a handler identifying clients by the configured API
+// key header or JWT claim for rate limiting. The claim is only used when requests
+// are authenticated, as unverified tokens could claim any client key. Likewise,
+// only API keys with a configured quota are used, other clients are identified
+// by their IP address so they can't escape its limit with made up keys.
+func (config *rpcEndpointConfig) newClientKeyHandler(srv http.Handler) http.Handler {
+	if config.rateLimiter == nil || (config.rateLimitKeyHeader == "" && config.rateLimitKeyClaim == "") {
+		return srv
+	}
+	var (
+		header = config.rateLimitKeyHeader
+		claim  = config.rateLimitKeyClaim
+	)
+	if len(config.jwtSecret) == 0 {
+		claim = ""
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var key string
+		if claim != "" {
+			key = jwtClaim(r, claim)
+		}
+		if key == "" && header != "" {
+			if apiKey := r.Header.Get(header); config.rateLimiter.HasQuota(apiKey) {
+				key = apiKey
+			}
+		}
+		if key != "" {
+			r = r.WithContext(rpc.WithClientKey(r.Context(), key))
+		}
+		srv.ServeHTTP(w, r)
+	})
+}
+
 func newCorsHandler(srv http.Handler, allowedOrigins []string) http.Handler {
 	// disable CORS support if user has not specified a custom CORS configuration
 	if len(allowedOrigins) == 0 {
@@ -561,6 +623,11 @@ func (w *gzipResponseWriter) Flush() {
 	}
 }
 
+// Unwrap returns the underlying writer, for use by http.ResponseController.
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.resp
+}
+
 func (w *gzipResponseWriter) close() {
 	if w.gz == nil {
 		return
@@ -598,14 +665,14 @@ func newIPCServer(log log.Logger, endpoint string) *ipcServer {
 }
 
 // start starts the httpServer's http.Server
-func (is *ipcServer) start(apis []rpc.API) error {
+func (is *ipcServer) start(apis []rpc.API, methods rpc.MethodFilter) error {
 	is.mu.Lock()
 	defer is.mu.Unlock()
 
 	if is.listener != nil {
 		return nil // already running
 	}
-	listener, srv, err := rpc.StartIPCEndpoint(is.endpoint, apis)
+	listener, srv, err := rpc.StartIPCEndpointFiltered(is.endpoint, apis, methods)
 	if err != nil {
 		is.log.Warn("IPC opening failed", "url", is.endpoint, "error", err)
 		return err
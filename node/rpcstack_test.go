@@ -17,7 +17,9 @@
 package node
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -596,6 +598,104 @@ func TestHTTPWriteTimeout(t *testing.T) {
 	})
 }
 
+// This test checks that subscriptions are served as Server-Sent Events through the
+// handler stack, beyond the write timeout.
+func TestHTTPSSE(t *testing.T) {
+	timeouts := rpc.DefaultHTTPTimeouts
+	timeouts.WriteTimeout = 500 * time.Millisecond
+	srv := createAndStartServer(t, &httpConfig{Modules: []string{"test"}, sse: true}, false, &wsConfig{}, &timeouts)
+	defer srv.stop()
+
+	query := url.Values{"method": {"test_subscribe"}, "params": {`["ticks", 4]`}}
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%v/?%s", srv.listenAddr(), query.Encode()), nil)
+	req.Header.Set("accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var (
+		events = bufio.NewScanner(resp.Body)
+		count  int
+	)
+	for count < 5 && events.Scan() {
+		if strings.HasPrefix(events.Text(), "data: ") {
+			count++
+		}
+	}
+	if count != 5 {
+		t.Fatalf("wrong number of events: have %d, want 5 (err %v)", count, events.Err())
+	}
+	// Regular requests are still served.
+	resp = rpcRequest(t, fmt.Sprintf("http://%v", srv.listenAddr()), "test_greet")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("wrong status for call: %d", resp.StatusCode)
+	}
+}
+
+func TestHTTPMethodFilter(t *testing.T) {
+	cfg := &httpConfig{Modules: []string{"test"}, rpcEndpointConfig: rpcEndpointConfig{methods: rpc.MethodFilter{Deny: []string{"test_sleep"}}}}
+	srv := createAndStartServer(t, cfg, false, &wsConfig{}, nil)
+	defer srv.stop()
+
+	url := fmt.Sprintf("http://%v", srv.listenAddr())
+	for method, want := range map[string]string{"test_greet": `"result"`, "test_sleep": `"error"`} {
+		resp := rpcRequest(t, url, method)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(body), want) {
+			t.Errorf("%s: unexpected response %s", method, body)
+		}
+	}
+}
+
+// Tests that only API keys with a quota identify rate limited clients, while
+// others are limited by their IP address.
+func TestHTTPRateLimitKeyHeader(t *testing.T) {
+	limit := rpc.RateLimitConfig{
+		Rate:      0.001,
+		Burst:     1,
+		KeyHeader: "X-Api-Key",
+		Quotas:    map[string]rpc.RateQuota{"vip": {}},
+	}
+	cfg := &httpConfig{Modules: []string{"test"}, rpcEndpointConfig: rpcEndpointConfig{
+		rateLimiter:        rpc.NewRateLimiter(limit),
+		rateLimitKeyHeader: limit.KeyHeader,
+	}}
+	srv := createAndStartServer(t, cfg, false, &wsConfig{}, nil)
+	defer srv.stop()
+
+	url := fmt.Sprintf("http://%v", srv.listenAddr())
+	call := func(key string) string {
+		t.Helper()
+		resp := rpcRequest(t, url, "test_greet", "X-Api-Key", key)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(body)
+	}
+	// Made up keys share the budget of the IP address
+	if body := call("first"); !strings.Contains(body, `"result"`) {
+		t.Fatalf("call failed: %s", body)
+	}
+	if body := call("second"); !strings.Contains(body, `"error"`) {
+		t.Fatalf("call with made up key not rate limited: %s", body)
+	}
+	// Keys with a quota have their own budget
+	for i := 0; i < 3; i++ {
+		if body := call("vip"); !strings.Contains(body, `"result"`) {
+			t.Fatalf("call with quota failed: %s", body)
+		}
+	}
+}
+
 func apis() []rpc.API {
 	return []rpc.API{
 		{
@@ -614,3 +714,18 @@ func (s *testService) Greet() string {
 func (s *testService) Sleep() {
 	time.Sleep(1500 * time.Millisecond)
 }
+
+func (s *testService) Ticks(ctx context.Context, n int) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		for i := 0; i < n; i++ {
+			time.Sleep(250 * time.Millisecond)
+			notifier.Notify(sub.ID, i)
+		}
+	}()
+	return sub, nil
+}
@@ -442,8 +442,10 @@ type ChainConfig struct {
 
 	InteropTime *uint64 `json:"interopTime,omitempty"` // Interop switch time (nil = no fork, 0 = already on optimism interop)
 
-	Cel2Time         *uint64  `json:"cel2Time,omitempty"`         // Cel2 switch time (nil = no fork, 0 = already on optimism cel2)
-	GingerbreadBlock *big.Int `json:"gingerbreadBlock,omitempty"` // Gingerbread switch block (nil = no fork, 0 = already activated)
+	Cel2Time            *uint64  `json:"cel2Time,omitempty"`            // Cel2 switch time (nil = no fork, 0 = already on optimism cel2)
+	CeloTransferGasTime *uint64  `json:"celoTransferGasTime,omitempty"` // Access list aware transfer precompile gas switch time (nil = no fork, 0 = already activated)
+	Cip66Time           *uint64  `json:"cip66Time,omitempty"`           // CIP-66 (CeloDenominatedTx) switch time (nil = no fork, 0 = already activated)
+	GingerbreadBlock    *big.Int `json:"gingerbreadBlock,omitempty"`    // Gingerbread switch block (nil = no fork, 0 = already activated)
 
 	// TerminalTotalDifficulty is the amount of total difficulty reached by
 	// the network that triggers the consensus upgrade.
@@ -628,6 +630,12 @@ func (c *ChainConfig) Description() string {
 	if c.Cel2Time != nil {
 		banner += fmt.Sprintf(" - Cel2:                        @%-10v\n", *c.Cel2Time)
 	}
+	if c.CeloTransferGasTime != nil {
+		banner += fmt.Sprintf(" - CeloTransferGas:             @%-10v\n", *c.CeloTransferGasTime)
+	}
+	if c.Cip66Time != nil {
+		banner += fmt.Sprintf(" - CIP-66:                      @%-10v\n", *c.Cip66Time)
+	}
 	return banner
 }
 
@@ -773,6 +781,19 @@ func (c *ChainConfig) IsCel2(time uint64) bool {
 	return isTimestampForked(c.Cel2Time, time)
 }
 
+// IsCeloTransferGas returns whether time is either equal to the CeloTransferGas
+// fork time or greater. From this fork on, the transfer precompile charges gas
+// like a regular value transfer, including EIP-2929 account access costs.
+func (c *ChainConfig) IsCeloTransferGas(time uint64) bool {
+	return c.IsCel2(time) && isTimestampForked(c.CeloTransferGasTime, time)
+}
+
+// IsCip66 returns whether time is either equal to the CIP-66 fork time or
+// greater, enabling CeloDenominatedTx transactions. CIP-66 requires Cel2.
+func (c *ChainConfig) IsCip66(time uint64) bool {
+	return c.IsCel2(time) && isTimestampForked(c.Cip66Time, time)
+}
+
 // IsGingerbread returns whether num represents a block number after the Gingerbread fork
 func (c *ChainConfig) IsGingerbread(num *big.Int) bool {
 	return isBlockForked(c.GingerbreadBlock, num)
@@ -1002,6 +1023,12 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.InteropTime, newcfg.InteropTime, headTimestamp, genesisTimestamp) {
 		return newTimestampCompatError("Interop fork timestamp", c.InteropTime, newcfg.InteropTime)
 	}
+	if isForkTimestampIncompatible(c.CeloTransferGasTime, newcfg.CeloTransferGasTime, headTimestamp, genesisTimestamp) {
+		return newTimestampCompatError("CeloTransferGas fork timestamp", c.CeloTransferGasTime, newcfg.CeloTransferGasTime)
+	}
+	if isForkTimestampIncompatible(c.Cip66Time, newcfg.Cip66Time, headTimestamp, genesisTimestamp) {
+		return newTimestampCompatError("CIP-66 fork timestamp", c.Cip66Time, newcfg.Cip66Time)
+	}
 	return nil
 }
 
@@ -1197,7 +1224,7 @@ type Rules struct {
 	IsOptimismBedrock, IsOptimismRegolith                   bool
 	IsOptimismCanyon, IsOptimismFjord                       bool
 	IsOptimismGranite, IsOptimismHolocene                   bool
-	IsCel2                                                  bool
+	IsCel2, IsCeloTransferGas, IsCip66                      bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -1236,6 +1263,8 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 		IsOptimismGranite:  isMerge && c.IsOptimismGranite(timestamp),
 		IsOptimismHolocene: isMerge && c.IsOptimismHolocene(timestamp),
 		// Celo
-		IsCel2: c.IsCel2(timestamp),
+		IsCel2:            c.IsCel2(timestamp),
+		IsCeloTransferGas: c.IsCeloTransferGas(timestamp),
+		IsCip66:           c.IsCip66(timestamp),
 	}
 }
@@ -230,3 +230,45 @@ func TestConfigRulesCel2(t *testing.T) {
 		t.Errorf("expected %v to be Cel2", stamp)
 	}
 }
+
+func TestConfigRulesCeloTransferGas(t *testing.T) {
+	c := &ChainConfig{
+		Cel2Time:            newUint64(500),
+		CeloTransferGasTime: newUint64(1000),
+		Optimism:            &OptimismConfig{},
+	}
+	var stamp uint64 = 500
+	if r := c.Rules(big.NewInt(0), true, stamp); !r.IsCel2 || r.IsCeloTransferGas {
+		t.Errorf("expected %v to be Cel2 but not CeloTransferGas", stamp)
+	}
+	stamp = 1000
+	if r := c.Rules(big.NewInt(0), true, stamp); !r.IsCeloTransferGas {
+		t.Errorf("expected %v to be CeloTransferGas", stamp)
+	}
+	// CeloTransferGas must not activate without Cel2
+	c.Cel2Time = nil
+	if r := c.Rules(big.NewInt(0), true, stamp); r.IsCeloTransferGas {
+		t.Errorf("expected %v to not be CeloTransferGas without Cel2", stamp)
+	}
+}
+
+func TestConfigRulesCip66(t *testing.T) {
+	c := &ChainConfig{
+		Cel2Time:  newUint64(500),
+		Cip66Time: newUint64(1000),
+		Optimism:  &OptimismConfig{},
+	}
+	var stamp uint64 = 500
+	if r := c.Rules(big.NewInt(0), true, stamp); !r.IsCel2 || r.IsCip66 {
+		t.Errorf("expected %v to be Cel2 but not CIP-66", stamp)
+	}
+	stamp = 1000
+	if r := c.Rules(big.NewInt(0), true, stamp); !r.IsCip66 {
+		t.Errorf("expected %v to be CIP-66", stamp)
+	}
+	// CIP-66 must not activate without Cel2
+	c.Cel2Time = nil
+	if r := c.Rules(big.NewInt(0), true, stamp); r.IsCip66 {
+		t.Errorf("expected %v to not be CIP-66 without Cel2", stamp)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	cacheHitMeter     = metrics.NewRegisteredMeter("rpc/cache/hit", nil)
+	cacheMissMeter    = metrics.NewRegisteredMeter("rpc/cache/miss", nil)
+	cacheStaleMeter   = metrics.NewRegisteredMeter("rpc/cache/stale", nil)
+	cacheEvictedMeter = metrics.NewRegisteredMeter("rpc/cache/evicted", nil)
+)
+
+// FinalityFunc reports whether the block with the given hash and number is
+// canonical and finalized.
+type FinalityFunc func(hash common.Hash, number uint64) bool
+
+// ResponseCache caches the results of calls which only depend on a finalized block.
+// Methods opt in by calling CacheableAt with the block their result is tied to,
+// other results are never cached.
+//
+// Cached results are checked against the finality of their block on every hit, so
+// a reorg below finality invalidates them.
+type ResponseCache struct {
+	results *lru.SizeConstrainedCache[common.Hash, []byte] // Block hash, block number and result, keyed by call hash
+	final   atomic.Pointer[FinalityFunc]
+}
+
+// NewResponseCache creates a response cache retaining up to size bytes of results.
+// Results are only cached once a finality function is set.
+func NewResponseCache(size int) *ResponseCache {
+	return &ResponseCache{results: lru.NewSizeConstrainedCache[common.Hash, []byte](uint64(size))}
+}
+
+// SetFinalityFunc sets the function deciding whether the block of a result is
+// finalized.
+func (c *ResponseCache) SetFinalityFunc(final FinalityFunc) {
+	c.final.Store(&final)
+}
+
+// finalized reports whether the given block is finalized.
+func (c *ResponseCache) finalized(hash common.Hash, number uint64) bool {
+	final := c.final.Load()
+	return final != nil && (*final)(hash, number)
+}
+
+// get returns the cached result of a call, if its block is still finalized.
+func (c *ResponseCache) get(key common.Hash) (json.RawMessage, bool) {
+	entry, ok := c.results.Get(key)
+	if !ok {
+		cacheMissMeter.Mark(1)
+		return nil, false
+	}
+	hash := common.BytesToHash(entry[:common.HashLength])
+	number := binary.BigEndian.Uint64(entry[common.HashLength:])
+	if !c.finalized(hash, number) {
+		c.results.Remove(key)
+		cacheStaleMeter.Mark(1)
+		cacheMissMeter.Mark(1)
+		return nil, false
+	}
+	cacheHitMeter.Mark(1)
+	return entry[common.HashLength+8:], true
+}
+
+// add caches the result of a call tied to the given block, if it is finalized.
+func (c *ResponseCache) add(key common.Hash, hash common.Hash, number uint64, result json.RawMessage) {
+	if !c.finalized(hash, number) {
+		return
+	}
+	entry := make([]byte, common.HashLength+8+len(result))
+	copy(entry, hash[:])
+	binary.BigEndian.PutUint64(entry[common.HashLength:], number)
+	copy(entry[common.HashLength+8:], result)
+	if c.results.Add(key, entry) {
+		cacheEvictedMeter.Mark(1)
+	}
+}
+
+// cacheKey returns the key of a call, or false if its parameters are malformed.
+// Parameters are canonicalised so that formatting does not affect the key, and
+// hashed along with the method so that large parameters don't bloat the cache.
+func cacheKey(method string, params json.RawMessage) (common.Hash, bool) {
+	var (
+		dec  = json.NewDecoder(bytes.NewReader(params))
+		args interface{}
+	)
+	dec.UseNumber()
+	if len(params) > 0 {
+		if err := dec.Decode(&args); err != nil {
+			return common.Hash{}, false
+		}
+	}
+	canonical, err := json.Marshal(args)
+	if err != nil {
+		return common.Hash{}, false
+	}
+	return sha256.Sum256(append([]byte(method+"\x00"), canonical...)), true
+}
+
+// cacheMarker collects the block a call's result is tied to.
+type cacheMarker struct {
+	hash   common.Hash
+	number uint64
+	set    bool
+}
+
+type cacheMarkerContextKey struct{}
+
+// CacheableAt marks the result of the call served with the given context as only
+// depending on the given block, allowing the server to cache it once the block is
+// finalized. It does nothing if the server has no response cache.
+//
+// Methods must only mark calls whose parameters identify the block, e.g. by its hash,
+// rather than by a tag like "latest".
+func CacheableAt(ctx context.Context, hash common.Hash, number uint64) {
+	if marker, ok := ctx.Value(cacheMarkerContextKey{}).(*cacheMarker); ok {
+		marker.hash, marker.number, marker.set = hash, number, true
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type cacheTestService struct {
+	calls int
+}
+
+func (s *cacheTestService) Block(ctx context.Context, hash common.Hash, number uint64) int {
+	CacheableAt(ctx, hash, number)
+	s.calls++
+	return s.calls
+}
+
+func (s *cacheTestService) Latest(ctx context.Context) int {
+	s.calls++
+	return s.calls
+}
+
+func TestResponseCache(t *testing.T) {
+	var (
+		service = new(cacheTestService)
+		server  = NewServer()
+		cache   = NewResponseCache(1024)
+		lock    sync.Mutex
+		final   = map[common.Hash]uint64{{1}: 1}
+	)
+	defer server.Stop()
+	server.RegisterName("cache", service)
+	server.SetResponseCache(cache)
+	cache.SetFinalityFunc(func(hash common.Hash, number uint64) bool {
+		lock.Lock()
+		defer lock.Unlock()
+		n, ok := final[hash]
+		return ok && n == number
+	})
+	client := DialInProc(server)
+	defer client.Close()
+
+	call := func(method string, args ...interface{}) int {
+		t.Helper()
+		var result int
+		if err := client.Call(&result, method, args...); err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	// Results tied to a finalized block are served from the cache.
+	if have := call("cache_block", common.Hash{1}, 1); have != 1 {
+		t.Fatalf("wrong result: have %d, want 1", have)
+	}
+	if have := call("cache_block", common.Hash{1}, 1); have != 1 {
+		t.Fatalf("result not cached: have %d, want 1", have)
+	}
+	// Results tied to other blocks, or no block at all, are not cached.
+	if have := call("cache_block", common.Hash{2}, 2); have != 2 {
+		t.Fatalf("wrong result: have %d, want 2", have)
+	}
+	if have := call("cache_block", common.Hash{2}, 2); have != 3 {
+		t.Fatalf("unfinalized result cached: have %d, want 3", have)
+	}
+	call("cache_latest")
+	if have := call("cache_latest"); have != 5 {
+		t.Fatalf("unmarked result cached: have %d, want 5", have)
+	}
+	// Results are invalidated once their block is no longer final.
+	lock.Lock()
+	delete(final, common.Hash{1})
+	lock.Unlock()
+	if have := call("cache_block", common.Hash{1}, 1); have != 6 {
+		t.Fatalf("stale result served: have %d, want 6", have)
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	a, ok := cacheKey("eth_call", []byte(`[{"to": "0x01", "data":"0x"}, "0x10"]`))
+	if !ok {
+		t.Fatal("failed to derive key")
+	}
+	b, _ := cacheKey("eth_call", []byte(`[{"data":"0x","to":"0x01"},"0x10"]`))
+	if a != b {
+		t.Errorf("keys of equivalent params differ: %x != %x", a, b)
+	}
+	c, _ := cacheKey("eth_estimateGas", []byte(`[{"data":"0x","to":"0x01"},"0x10"]`))
+	if a == c {
+		t.Error("keys of different methods match")
+	}
+	if _, ok := cacheKey("eth_call", []byte(`[`)); ok {
+		t.Error("key derived from malformed params")
+	}
+}
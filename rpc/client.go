@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
 	"reflect"
@@ -28,6 +29,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum/internal/telemetry"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -86,8 +88,14 @@ type Client struct {
 	reconnectFunc reconnectFunc
 
 	// config fields
-	batchItemLimit       int
-	batchResponseMaxSize int
+	resubscribe           bool          // whether subscriptions survive connection loss
+	resubscribeMinBackoff time.Duration // delay before retrying a failed resubscribe
+	resubscribeMaxBackoff time.Duration // maximum delay between resubscribe attempts
+	batchItemLimit        int
+	batchResponseMaxSize  int
+	rateLimiter           *RateLimiter
+	responseCache         *ResponseCache
+	methodFilter          *MethodFilter
 
 	// writeConn is used for writing to the connection on the caller's goroutine. It should
 	// only be accessed outside of dispatch, with the write lock held. The write lock is
@@ -120,6 +128,9 @@ func (c *Client) newClientConn(conn ServerCodec) *clientConn {
 	ctx = context.WithValue(ctx, clientContextKey{}, c)
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, conn.peerInfo())
 	handler := newHandler(ctx, conn, c.idgen, c.services, c.batchItemLimit, c.batchResponseMaxSize)
+	handler.rateLimiter = c.rateLimiter
+	handler.responseCache = c.responseCache
+	handler.methodFilter = c.methodFilter
 	return &clientConn{conn, handler}
 }
 
@@ -140,6 +151,7 @@ type requestOp struct {
 	err         error
 	resp        chan []*jsonrpcMessage // the response goes here
 	sub         *ClientSubscription    // set for Subscribe requests.
+	resub       bool                   // true when re-establishing a running subscription
 	hadResponse bool                   // true when the request was responded to
 }
 
@@ -191,35 +203,82 @@ func DialContext(ctx context.Context, rawurl string) (*Client, error) {
 //
 // The client reconnects automatically when the connection is lost.
 func DialOptions(ctx context.Context, rawurl string, options ...ClientOption) (*Client, error) {
-	u, err := url.Parse(rawurl)
-	if err != nil {
-		return nil, err
-	}
-
 	cfg := new(clientConfig)
 	for _, opt := range options {
 		opt.applyOption(cfg)
 	}
+	reconnect, isHTTP, err := newClientTransport(rawurl, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.failoverURLs) > 0 {
+		if isHTTP {
+			return nil, errors.New("failover is not supported for HTTP endpoints")
+		}
+		transports := []reconnectFunc{reconnect}
+		for _, failover := range cfg.failoverURLs {
+			transport, isHTTP, err := newClientTransport(failover, cfg)
+			if err != nil {
+				return nil, err
+			}
+			if isHTTP {
+				return nil, errors.New("failover is not supported for HTTP endpoints")
+			}
+			transports = append(transports, transport)
+		}
+		reconnect = newFailoverTransport(transports)
+	}
+	return newClient(ctx, cfg, reconnect)
+}
 
-	var reconnect reconnectFunc
+// newClientTransport creates the transport for the given URL, reporting whether it
+// is HTTP.
+func newClientTransport(rawurl string, cfg *clientConfig) (reconnectFunc, bool, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, false, err
+	}
 	switch u.Scheme {
 	case "http", "https":
-		reconnect = newClientTransportHTTP(rawurl, cfg)
+		return newClientTransportHTTP(rawurl, cfg), true, nil
 	case "ws", "wss":
 		rc, err := newClientTransportWS(rawurl, cfg)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
-		reconnect = rc
+		return rc, false, nil
 	case "stdio":
-		reconnect = newClientTransportIO(os.Stdin, os.Stdout)
+		return newClientTransportIO(os.Stdin, os.Stdout), false, nil
 	case "":
-		reconnect = newClientTransportIPC(rawurl)
+		return newClientTransportIPC(rawurl), false, nil
 	default:
-		return nil, fmt.Errorf("no known transport for URL scheme %q", u.Scheme)
+		return nil, false, fmt.Errorf("no known transport for URL scheme %q", u.Scheme)
+	}
+}
+
+// newFailoverTransport creates a transport connecting to the first reachable one of
+// the given transports, starting from the one last connected to.
+func newFailoverTransport(transports []reconnectFunc) reconnectFunc {
+	var current atomic.Int32
+	return func(ctx context.Context) (ServerCodec, error) {
+		var errs []error
+		for i := 0; i < len(transports); i++ {
+			index := (int(current.Load()) + i) % len(transports)
+			conn, err := transports[index](ctx)
+			if err == nil {
+				if i > 0 {
+					log.Debug("RPC client failed over", "endpoint", index)
+				}
+				current.Store(int32(index))
+				return conn, nil
+			}
+			errs = append(errs, err)
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		return nil, errors.Join(errs...)
 	}
-
-	return newClient(ctx, cfg, reconnect)
 }
 
 // ClientFromContext retrieves the client from the context, if any. This can be used to perform
@@ -242,21 +301,27 @@ func newClient(initctx context.Context, cfg *clientConfig, connect reconnectFunc
 func initClient(conn ServerCodec, services *serviceRegistry, cfg *clientConfig) *Client {
 	_, isHTTP := conn.(*httpConn)
 	c := &Client{
-		isHTTP:               isHTTP,
-		services:             services,
-		idgen:                cfg.idgen,
-		batchItemLimit:       cfg.batchItemLimit,
-		batchResponseMaxSize: cfg.batchResponseLimit,
-		writeConn:            conn,
-		close:                make(chan struct{}),
-		closing:              make(chan struct{}),
-		didClose:             make(chan struct{}),
-		reconnected:          make(chan ServerCodec),
-		readOp:               make(chan readOp),
-		readErr:              make(chan error),
-		reqInit:              make(chan *requestOp),
-		reqSent:              make(chan error, 1),
-		reqTimeout:           make(chan *requestOp),
+		isHTTP:                isHTTP,
+		resubscribe:           cfg.resubscribe,
+		resubscribeMinBackoff: cfg.resubscribeMinBackoff,
+		resubscribeMaxBackoff: cfg.resubscribeMaxBackoff,
+		services:              services,
+		idgen:                 cfg.idgen,
+		batchItemLimit:        cfg.batchItemLimit,
+		batchResponseMaxSize:  cfg.batchResponseLimit,
+		rateLimiter:           cfg.rateLimiter,
+		responseCache:         cfg.responseCache,
+		methodFilter:          cfg.methodFilter,
+		writeConn:             conn,
+		close:                 make(chan struct{}),
+		closing:               make(chan struct{}),
+		didClose:              make(chan struct{}),
+		reconnected:           make(chan ServerCodec),
+		readOp:                make(chan readOp),
+		readErr:               make(chan error),
+		reqInit:               make(chan *requestOp),
+		reqSent:               make(chan error, 1),
+		reqTimeout:            make(chan *requestOp),
 	}
 
 	// Set defaults.
@@ -335,6 +400,19 @@ func (c *Client) Call(result interface{}, method string, args ...interface{}) er
 // The result must be a pointer so that package json can unmarshal into it. You
 // can also pass nil, in which case the result is ignored.
 func (c *Client) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	// Calls made while serving a traced request are traced, and the trace is
+	// propagated to HTTP servers.
+	ctx, span := telemetry.Start(ctx, "rpc.client", telemetry.String("rpc.method", method))
+	if span != nil {
+		defer span.End()
+		ctx = NewContextWithHeaders(ctx, http.Header{"Traceparent": {span.Traceparent()}})
+	}
+	err := c.callContext(ctx, result, method, args...)
+	span.SetError(err)
+	return err
+}
+
+func (c *Client) callContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
 	if result != nil && reflect.TypeOf(result).Kind() != reflect.Ptr {
 		return fmt.Errorf("call result parameter must be pointer or nil interface: %v", result)
 	}
@@ -524,7 +602,7 @@ func (c *Client) Subscribe(ctx context.Context, namespace string, channel interf
 	op := &requestOp{
 		ids:  []json.RawMessage{msg.ID},
 		resp: make(chan []*jsonrpcMessage, 1),
-		sub:  newClientSubscription(c, namespace, chanVal),
+		sub:  newClientSubscription(c, namespace, msg.Params, chanVal),
 	}
 
 	// Send the subscription request.
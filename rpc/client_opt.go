@@ -18,6 +18,7 @@ package rpc
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -41,6 +42,15 @@ type clientConfig struct {
 	idgen              func() ID
 	batchItemLimit     int
 	batchResponseLimit int
+	rateLimiter        *RateLimiter
+	responseCache      *ResponseCache
+	methodFilter       *MethodFilter
+
+	// Connection loss handling
+	resubscribe           bool
+	resubscribeMinBackoff time.Duration
+	resubscribeMaxBackoff time.Duration
+	failoverURLs          []string
 }
 
 func (cfg *clientConfig) initHeaders() {
@@ -142,3 +152,27 @@ func WithBatchResponseSizeLimit(sizeLimit int) ClientOption {
 		cfg.batchResponseLimit = sizeLimit
 	})
 }
+
+// WithResubscribe makes subscriptions survive the loss of the connection. The client
+// reconnects with an exponential backoff between minBackoff and maxBackoff, and then
+// re-establishes the subscription under the same ClientSubscription. Notifications sent
+// while the subscription was down are lost, which is reported on its Gaps channel.
+//
+// This option does not apply to HTTP clients, which don't support subscriptions.
+func WithResubscribe(minBackoff, maxBackoff time.Duration) ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.resubscribe = true
+		cfg.resubscribeMinBackoff = minBackoff
+		cfg.resubscribeMaxBackoff = max(minBackoff, maxBackoff)
+	})
+}
+
+// WithFailover sets additional endpoints the client connects to when the current one is
+// unreachable. Endpoints are tried in order, starting from the one last connected to.
+//
+// Failover is supported for WebSocket and IPC endpoints, but not HTTP.
+func WithFailover(urls ...string) ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.failoverURLs = append(cfg.failoverURLs, urls...)
+	})
+}
@@ -808,6 +808,80 @@ func TestClientReconnect(t *testing.T) {
 	}
 }
 
+// This test checks that subscriptions are re-established on the failover endpoint
+// when the connection is lost.
+func TestClientResubscribe(t *testing.T) {
+	startServer := func() (*Server, net.Listener) {
+		srv := newTestServer()
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal("can't listen:", err)
+		}
+		go http.Serve(l, srv.WebsocketHandler([]string{"*"}))
+		return srv, l
+	}
+	s1, l1 := startServer()
+	s2, l2 := startServer()
+	defer l2.Close()
+	defer s2.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := DialOptions(ctx, "ws://"+l1.Addr().String(),
+		WithResubscribe(10*time.Millisecond, 100*time.Millisecond),
+		WithFailover("ws://"+l2.Addr().String()))
+	if err != nil {
+		t.Fatal("can't dial:", err)
+	}
+	defer client.Close()
+
+	nc := make(chan int)
+	sub, err := client.Subscribe(ctx, "nftest", nc, "someSubscription", 2, 0)
+	if err != nil {
+		t.Fatal("can't subscribe:", err)
+	}
+	receive := func() {
+		t.Helper()
+		for i := 0; i < 2; i++ {
+			select {
+			case val := <-nc:
+				if val != i {
+					t.Fatalf("value mismatch: got %d, want %d", val, i)
+				}
+			case err := <-sub.Err():
+				t.Fatal("subscription failed:", err)
+			case <-ctx.Done():
+				t.Fatal("timed out waiting for notification")
+			}
+		}
+	}
+	receive()
+
+	// Shut down the first server, the subscription should move to the second one.
+	l1.Close()
+	s1.Stop()
+	select {
+	case gap := <-sub.Gaps():
+		if gap.Err == nil || gap.End.Before(gap.Start) {
+			t.Fatalf("invalid gap: %+v", gap)
+		}
+	case err := <-sub.Err():
+		t.Fatal("subscription failed:", err)
+	case <-ctx.Done():
+		t.Fatal("subscription not re-established")
+	}
+	receive()
+
+	sub.Unsubscribe()
+	if err, ok := <-sub.Err(); ok {
+		t.Fatal("error after unsubscribe:", err)
+	}
+	// Failover is rejected for HTTP endpoints.
+	if _, err := DialOptions(ctx, "http://"+l2.Addr().String(), WithFailover("http://127.0.0.1:1")); err == nil {
+		t.Fatal("HTTP failover accepted")
+	}
+}
+
 func httpTestClient(srv *Server, transport string, fl *flakeyListener) (*Client, *httptest.Server) {
 	// Create the HTTP server.
 	var hs *httptest.Server
@@ -25,12 +25,19 @@ import (
 
 // StartIPCEndpoint starts an IPC endpoint.
 func StartIPCEndpoint(ipcEndpoint string, apis []API) (net.Listener, *Server, error) {
+	return StartIPCEndpointFiltered(ipcEndpoint, apis, MethodFilter{})
+}
+
+// StartIPCEndpointFiltered starts an IPC endpoint serving the methods permitted
+// by the filter.
+func StartIPCEndpointFiltered(ipcEndpoint string, apis []API, methods MethodFilter) (net.Listener, *Server, error) {
 	// Register all the APIs exposed by the services.
 	var (
 		handler    = NewServer()
 		regMap     = make(map[string]struct{})
 		registered []string
 	)
+	handler.SetMethodFilter(methods)
 	for _, api := range apis {
 		if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
 			log.Info("IPC registration failed", "namespace", api.Namespace, "error", err)
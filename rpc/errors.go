@@ -16,7 +16,11 @@
 
 package rpc
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 // HTTPError is returned by client operations when the HTTP status code of the
 // response is not a 2xx status.
@@ -55,12 +59,14 @@ var (
 	_ Error = new(invalidMessageError)
 	_ Error = new(invalidParamsError)
 	_ Error = new(internalServerError)
+	_ Error = new(rateLimitError)
 )
 
 const (
 	errcodeDefault          = -32000
 	errcodeTimeout          = -32002
 	errcodeResponseTooLarge = -32003
+	errcodeLimitExceeded    = -32005
 	errcodePanic            = -32603
 	errcodeMarshalError     = -32603
 
@@ -71,6 +77,7 @@ const (
 	errMsgTimeout          = "request timed out"
 	errMsgResponseTooLarge = "response too large"
 	errMsgBatchTooLarge    = "batch too large"
+	errMsgRateLimited      = "rate limit exceeded"
 )
 
 var ErrNoHistoricalFallback = NoHistoricalFallbackError{}
@@ -164,3 +171,16 @@ type internalServerError struct {
 func (e *internalServerError) ErrorCode() int { return e.code }
 
 func (e *internalServerError) Error() string { return e.message }
+
+// rateLimitError is returned when a client exceeds its rate limit. It is the
+// JSON-RPC counterpart of HTTP status 429.
+type rateLimitError struct{ retryAfter time.Duration }
+
+func (e *rateLimitError) ErrorCode() int { return errcodeLimitExceeded }
+
+func (e *rateLimitError) Error() string { return errMsgRateLimited }
+
+// ErrorData reports the number of seconds after which the call can be retried.
+func (e *rateLimitError) ErrorData() interface{} {
+	return map[string]interface{}{"retryAfter": math.Ceil(e.retryAfter.Seconds())}
+}
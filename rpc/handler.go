@@ -28,6 +28,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/internal/telemetry"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -65,6 +66,9 @@ type handler struct {
 	allowSubscribe       bool
 	batchRequestLimit    int
 	batchResponseMaxSize int
+	rateLimiter          *RateLimiter   // nil if calls are not rate limited
+	responseCache        *ResponseCache // nil if results are not cached
+	methodFilter         *MethodFilter  // nil if all registered methods are served
 
 	subLock    sync.Mutex
 	serverSubs map[ID]*Subscription
@@ -228,6 +232,20 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 				break
 			}
 			resp := h.handleCallMsg(cp, msg)
+			if resp != nil && resp.stream != nil {
+				// Batch responses are written at once, streams are materialized
+				// within the remaining response size limit.
+				limit := 0
+				if h.batchResponseMaxSize != 0 {
+					limit = max(h.batchResponseMaxSize-responseBytes, 1)
+				}
+				var fits bool
+				if resp, fits = resp.materialize(limit); !fits {
+					err := &internalServerError{errcodeResponseTooLarge, errMsgResponseTooLarge}
+					callBuffer.respondWithError(cp.ctx, h.conn, err)
+					break
+				}
+			}
 			callBuffer.pushResponse(resp)
 			if resp != nil && h.batchResponseMaxSize != 0 {
 				responseBytes += len(resp.Result)
@@ -297,6 +315,13 @@ func (h *handler) handleNonBatchCall(cp *callProc, msg *jsonrpcMessage) {
 	}
 
 	answer := h.handleCallMsg(cp, msg)
+	if answer != nil && answer.stream != nil {
+		// Streams are produced while the response is written, so the timeout
+		// applies until the stream is complete.
+		responded.Do(func() {
+			h.writeStreamAnswer(cp.ctx, answer)
+		})
+	}
 	if timer != nil {
 		timer.Stop()
 	}
@@ -410,10 +435,16 @@ func (h *handler) handleResponses(batch []*jsonrpcMessage, handleCall func(*json
 			if msg.Error != nil {
 				op.err = msg.Error
 			} else {
-				op.err = json.Unmarshal(msg.Result, &op.sub.subid)
+				var subid string
+				op.err = json.Unmarshal(msg.Result, &subid)
 				if op.err == nil {
-					go op.sub.run()
-					h.clientSubs[op.sub.subid] = op.sub
+					// Re-established subscriptions are already running and
+					// update their ID themselves.
+					if !op.resub {
+						op.sub.subid = subid
+						go op.sub.run()
+					}
+					h.clientSubs[subid] = op.sub
 				}
 			}
 		}
@@ -494,6 +525,18 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	ctx, span := telemetry.StartTrace(cp.ctx, msg.Method)
+	defer span.End()
+
+	if !msg.isUnsubscribe() && !h.methodFilter.allows(msg.Method) {
+		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
+	}
+	if h.rateLimiter != nil && !msg.isUnsubscribe() {
+		if err := h.rateLimiter.allow(clientKey(PeerInfoFromContext(ctx)), msg.Method); err != nil {
+			span.SetError(err)
+			return msg.errorResponse(err)
+		}
+	}
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg)
 	}
@@ -512,7 +555,10 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 		return msg.errorResponse(&invalidParamsError{err.Error()})
 	}
 	start := time.Now()
-	answer := h.runMethod(cp.ctx, msg, callb, args)
+	answer := h.runCachedMethod(ctx, msg, callb, args)
+	if answer.Error != nil {
+		span.SetError(answer.Error)
+	}
 
 	// Collect the statistics for RPC calls if metrics is enabled.
 	// We only care about pure rpc call. Filter out subscription.
@@ -569,9 +615,33 @@ func (h *handler) runMethod(ctx context.Context, msg *jsonrpcMessage, callb *cal
 	if err != nil {
 		return msg.errorResponse(err)
 	}
+	if stream, ok := result.(*Stream); ok && stream != nil {
+		return &jsonrpcMessage{Version: vsn, ID: msg.ID, stream: stream}
+	}
 	return msg.response(result)
 }
 
+// runCachedMethod runs the Go callback for an RPC method, serving the result from
+// the response cache if possible.
+func (h *handler) runCachedMethod(ctx context.Context, msg *jsonrpcMessage, callb *callback, args []reflect.Value) *jsonrpcMessage {
+	if h.responseCache == nil || callb == h.unsubscribeCb {
+		return h.runMethod(ctx, msg, callb, args)
+	}
+	key, ok := cacheKey(msg.Method, msg.Params)
+	if !ok {
+		return h.runMethod(ctx, msg, callb, args)
+	}
+	if result, ok := h.responseCache.get(key); ok {
+		return &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: result}
+	}
+	marker := new(cacheMarker)
+	answer := h.runMethod(context.WithValue(ctx, cacheMarkerContextKey{}, marker), msg, callb, args)
+	if answer.Error == nil && answer.stream == nil && marker.set {
+		h.responseCache.add(key, marker.hash, marker.number, answer.Result)
+	}
+	return answer
+}
+
 // unsubscribe is the callback function for all *_unsubscribe calls.
 func (h *handler) unsubscribe(ctx context.Context, id ID) (bool, error) {
 	h.subLock.Lock()
@@ -30,6 +30,8 @@ import (
 	"strconv"
 	"sync"
 	"time"
+
+	"github.com/ethereum/go-ethereum/internal/telemetry"
 )
 
 const (
@@ -292,7 +294,9 @@ func (s *Server) newHTTPServerConn(r *http.Request, w http.ResponseWriter) Serve
 	dec := json.NewDecoder(conn)
 	dec.UseNumber()
 
-	return NewFuncCodec(conn, encoder, dec.Decode)
+	codec := NewFuncCodec(conn, encoder, dec.Decode).(*jsonCodec)
+	codec.out = conn
+	return codec
 }
 
 // Close does nothing and always returns nil.
@@ -324,9 +328,17 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	connInfo.HTTP.Host = r.Host
 	connInfo.HTTP.Origin = r.Header.Get("Origin")
 	connInfo.HTTP.UserAgent = r.Header.Get("User-Agent")
+	connInfo.clientKey = clientKeyFromContext(r.Context())
 	ctx := r.Context()
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, connInfo)
 
+	// Continue the caller's trace, echoing the trace ID to the caller.
+	ctx, span := telemetry.ContinueTrace(ctx, r.Header.Get("traceparent"), "rpc.http")
+	if span != nil {
+		w.Header().Set("traceresponse", span.Traceparent())
+		defer span.End()
+	}
+
 	// All checks passed, create a codec that reads directly from the request body
 	// until EOF, writes the response to w, and orders the server to process a
 	// single request.
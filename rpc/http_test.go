@@ -21,8 +21,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/internal/telemetry"
 )
 
 func confirmStatusCode(t *testing.T, got, want int) {
@@ -204,6 +207,48 @@ func TestHTTPPeerInfo(t *testing.T) {
 	}
 }
 
+func TestHTTPTraceresponse(t *testing.T) {
+	if err := telemetry.Enable(telemetry.Config{SampleRatio: 1, RemoteSampling: true, File: filepath.Join(t.TempDir(), "spans.json")}); err != nil {
+		t.Fatal(err)
+	}
+	defer telemetry.Close()
+
+	s := newTestServer()
+	defer s.Stop()
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	request := func(traceparent string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["x",1]}`))
+		req.Header.Set("content-type", contentType)
+		if traceparent != "" {
+			req.Header.Set("traceparent", traceparent)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	// The caller's trace is continued.
+	resp := request("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if tr := resp.Header.Get("traceresponse"); !strings.HasPrefix(tr, "00-4bf92f3577b34da6a3ce929d0e0e4736-") {
+		t.Errorf("wrong traceresponse %q", tr)
+	}
+	// Requests without a trace start a sampled one.
+	resp = request("")
+	if tr := resp.Header.Get("traceresponse"); len(tr) != 55 {
+		t.Errorf("wrong traceresponse %q", tr)
+	}
+	// Unsampled traces are not echoed.
+	resp = request("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	if tr := resp.Header.Get("traceresponse"); tr != "" {
+		t.Errorf("traceresponse for unsampled trace: %q", tr)
+	}
+}
+
 func TestNewContextWithHeaders(t *testing.T) {
 	expectedHeaders := 0
 	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
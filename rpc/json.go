@@ -17,6 +17,7 @@
 package rpc
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -37,6 +38,7 @@ const (
 	notificationMethodSuffix = "_subscription"
 
 	defaultWriteTimeout = 10 * time.Second // used if context has no deadline
+	streamBufferSize    = 64 * 1024        // write buffer of streamed responses
 )
 
 var null = json.RawMessage("null")
@@ -66,6 +68,8 @@ type jsonrpcMessage struct {
 	Params  json.RawMessage `json:"params,omitempty"`
 	Error   *jsonError      `json:"error,omitempty"`
 	Result  json.RawMessage `json:"result,omitempty"`
+
+	stream *Stream // result of a method returning a stream, written incrementally
 }
 
 func (msg *jsonrpcMessage) isNotification() bool {
@@ -185,6 +189,7 @@ type jsonCodec struct {
 	encMu   sync.Mutex       // guards the encoder
 	encode  encodeFunc       // encoder to allow multiple transports
 	conn    deadlineCloser
+	out     io.Writer // written directly by streamed responses, nil if unsupported
 }
 
 type encodeFunc = func(v interface{}, isErrorResponse bool) error
@@ -217,7 +222,9 @@ func NewCodec(conn Conn) ServerCodec {
 	encode := func(v interface{}, isErrorResponse bool) error {
 		return enc.Encode(v)
 	}
-	return NewFuncCodec(conn, encode, dec.Decode)
+	codec := NewFuncCodec(conn, encode, dec.Decode).(*jsonCodec)
+	codec.out = conn
+	return codec
 }
 
 func (c *jsonCodec) peerInfo() PeerInfo {
@@ -259,6 +266,31 @@ func (c *jsonCodec) writeJSON(ctx context.Context, v interface{}, isErrorRespons
 	return c.encode(v, isErrorResponse)
 }
 
+// writeStream writes a message incrementally. Codecs without a direct output buffer
+// the message and write it at once.
+func (c *jsonCodec) writeStream(ctx context.Context, write func(w io.Writer, deadline time.Time) error) error {
+	if c.out == nil {
+		var buf bytes.Buffer
+		if err := write(&buf, time.Time{}); err != nil {
+			c.close()
+			return err
+		}
+		return c.writeJSON(ctx, json.RawMessage(buf.Bytes()), false)
+	}
+	c.encMu.Lock()
+	defer c.encMu.Unlock()
+
+	bw := bufio.NewWriterSize(newDeadlineWriter(ctx, c.out, c.conn), streamBufferSize)
+	err := write(bw, time.Time{})
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err != nil {
+		c.close()
+	}
+	return err
+}
+
 func (c *jsonCodec) close() {
 	c.closer.Do(func() {
 		close(c.closeCh)
@@ -0,0 +1,78 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"strings"
+)
+
+// MethodFilter restricts the methods a server serves beyond the registered namespaces.
+//
+// Entries are either full method names like "debug_traceTransaction", or prefixes
+// ending in '*' like "debug_trace*". A method is served if it matches Allow, or
+// Allow is empty, and it does not match Deny. The methods of the "rpc" namespace
+// are served unless denied explicitly.
+type MethodFilter struct {
+	Allow []string `toml:",omitempty"`
+	Deny  []string `toml:",omitempty"`
+}
+
+// IsEmpty reports whether the filter permits all methods.
+func (f *MethodFilter) IsEmpty() bool {
+	return f == nil || (len(f.Allow) == 0 && len(f.Deny) == 0)
+}
+
+// allows reports whether the method is permitted. A nil filter permits all methods.
+func (f *MethodFilter) allows(method string) bool {
+	if f == nil {
+		return true
+	}
+	if matchMethod(f.Deny, method) {
+		return false
+	}
+	if len(f.Allow) == 0 || strings.HasPrefix(method, MetadataApi+serviceMethodSeparator) {
+		return true
+	}
+	return matchMethod(f.Allow, method)
+}
+
+// allowsService reports whether any method or subscription of the service is permitted.
+func (f *MethodFilter) allowsService(name string, svc service) bool {
+	if f == nil {
+		return true
+	}
+	for method := range svc.callbacks {
+		if f.allows(name + serviceMethodSeparator + method) {
+			return true
+		}
+	}
+	return len(svc.subscriptions) > 0 && f.allows(name+subscribeMethodSuffix)
+}
+
+// matchMethod reports whether the method matches any of the entries.
+func matchMethod(entries []string, method string) bool {
+	for _, entry := range entries {
+		if prefix, ok := strings.CutSuffix(entry, "*"); ok {
+			if strings.HasPrefix(method, prefix) {
+				return true
+			}
+		} else if entry == method {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMethodFilterAllows(t *testing.T) {
+	filter := &MethodFilter{
+		Allow: []string{"debug_trace*", "eth_*"},
+		Deny:  []string{"debug_traceChain", "eth_sendRawTransaction"},
+	}
+	tests := map[string]bool{
+		"debug_traceTransaction": true,
+		"debug_traceChain":       false,
+		"debug_setHead":          false,
+		"eth_call":               true,
+		"eth_sendRawTransaction": false,
+		"admin_peers":            false,
+		"rpc_modules":            true,
+	}
+	for method, want := range tests {
+		if got := filter.allows(method); got != want {
+			t.Errorf("%s: got %v, want %v", method, got, want)
+		}
+	}
+	// Deny-only filters serve everything else.
+	filter = &MethodFilter{Deny: []string{"debug_*", "rpc_modules"}}
+	if filter.allows("debug_traceTransaction") || filter.allows("rpc_modules") || !filter.allows("eth_call") {
+		t.Error("wrong deny-only filter result")
+	}
+	if !(*MethodFilter)(nil).allows("debug_setHead") {
+		t.Error("nil filter denies method")
+	}
+}
+
+func TestServerMethodFilter(t *testing.T) {
+	server := newTestServer()
+	server.SetMethodFilter(MethodFilter{Allow: []string{"test_echo", "nftest_subscribe"}})
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	var result echoResult
+	if err := client.Call(&result, "test_echo", "x", 1); err != nil {
+		t.Fatal(err)
+	}
+	err := client.Call(&result, "test_echoWithCtx", "x", 1)
+	var rpcErr Error
+	if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != -32601 {
+		t.Fatalf("expected method not found error, got %v", err)
+	}
+	// Subscriptions are served when their subscribe method is allowed.
+	sub, err := client.Subscribe(context.Background(), "nftest", make(chan int), "someSubscription", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub.Unsubscribe()
+
+	modules, err := client.SupportedModules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != 3 || modules["test"] == "" || modules["nftest"] == "" || modules["rpc"] == "" {
+		t.Fatalf("wrong modules: %v", modules)
+	}
+
+	// Namespaces without any served method are not reported.
+	server = newTestServer()
+	server.SetMethodFilter(MethodFilter{Deny: []string{"nftest_*"}})
+	defer server.Stop()
+	client = DialInProc(server)
+	defer client.Close()
+
+	if modules, _ = client.SupportedModules(); len(modules) != 2 || modules["nftest"] != "" {
+		t.Fatalf("wrong modules after denying nftest: %v", modules)
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/metrics"
+	"golang.org/x/time/rate"
+)
+
+// maxRateLimitedClients is the number of clients whose rate limiting state is
+// retained. The least recently seen clients are forgotten, restarting with a
+// full burst.
+const maxRateLimitedClients = 65536
+
+var rateLimitedMeter = metrics.NewRegisteredMeter("rpc/ratelimit/limited", nil)
+
+// RateLimitConfig configures per-client rate limiting of RPC calls. Each call
+// spends the cost of its method from the client's budget, which is replenished
+// at a fixed rate.
+type RateLimitConfig struct {
+	Rate        float64              // Cost units replenished per second for each client, 0 disables rate limiting
+	Burst       int                  // Cost units a client can spend at once, defaults to the rate
+	KeyHeader   string               `toml:",omitempty"` // HTTP header carrying the client's API key, only keys with a quota are accepted
+	KeyClaim    string               `toml:",omitempty"` // JWT claim identifying clients of the authenticated endpoints, limited by their quotas only
+	MethodCosts map[string]int       `toml:",omitempty"` // Cost of methods, a trailing '*' matches by prefix; unlisted methods cost 1
+	Quotas      map[string]RateQuota `toml:",omitempty"` // Limits of specific client keys or IP addresses
+}
+
+// RateQuota overrides the default limits for a single client.
+type RateQuota struct {
+	Rate  float64 // Cost units replenished per second, 0 means unlimited
+	Burst int     // Cost units the client can spend at once
+}
+
+// RateLimiter enforces the rate limits of a RateLimitConfig. A single limiter can
+// be shared by multiple servers so that a client's budget spans all endpoints.
+type RateLimiter struct {
+	config   RateLimitConfig
+	costs    map[string]int // Exact method costs
+	prefixes []string       // Method prefixes with costs, longest first
+
+	lock     sync.Mutex
+	limiters lru.BasicLRU[string, *rate.Limiter]
+}
+
+// NewRateLimiter creates a rate limiter from the given config.
+func NewRateLimiter(config RateLimitConfig) *RateLimiter {
+	l := &RateLimiter{
+		config:   config,
+		costs:    make(map[string]int),
+		limiters: lru.NewBasicLRU[string, *rate.Limiter](maxRateLimitedClients),
+	}
+	for method, cost := range config.MethodCosts {
+		if prefix, ok := strings.CutSuffix(method, "*"); ok {
+			l.prefixes = append(l.prefixes, prefix)
+		}
+		l.costs[method] = cost
+	}
+	sort.Slice(l.prefixes, func(i, j int) bool { return len(l.prefixes[i]) > len(l.prefixes[j]) })
+	return l
+}
+
+// ParseMethodCosts parses a comma separated list of method=cost entries.
+func ParseMethodCosts(s string) (map[string]int, error) {
+	costs := make(map[string]int)
+	for _, entry := range strings.Split(s, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		var (
+			method, value, _ = strings.Cut(entry, "=")
+			cost             int
+		)
+		if _, err := fmt.Sscanf(value, "%d", &cost); err != nil || method == "" || cost < 0 {
+			return nil, fmt.Errorf("invalid method cost %q", entry)
+		}
+		costs[method] = cost
+	}
+	return costs, nil
+}
+
+// ParseRateQuotas parses a comma separated list of key=rate:burst entries.
+func ParseRateQuotas(s string) (map[string]RateQuota, error) {
+	quotas := make(map[string]RateQuota)
+	for _, entry := range strings.Split(s, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		var (
+			key, value, _ = strings.Cut(entry, "=")
+			quota         RateQuota
+		)
+		if _, err := fmt.Sscanf(value, "%g:%d", &quota.Rate, &quota.Burst); err != nil || key == "" || quota.Rate < 0 || quota.Burst < 0 {
+			return nil, fmt.Errorf("invalid rate quota %q", entry)
+		}
+		quotas[key] = quota
+	}
+	return quotas, nil
+}
+
+// HasQuota reports whether limits are configured for the given client key.
+func (l *RateLimiter) HasQuota(key string) bool {
+	_, ok := l.config.Quotas[key]
+	return ok
+}
+
+// cost returns the cost of calling the given method.
+func (l *RateLimiter) cost(method string) int {
+	if cost, ok := l.costs[method]; ok {
+		return cost
+	}
+	for _, prefix := range l.prefixes {
+		if strings.HasPrefix(method, prefix) {
+			return l.costs[prefix+"*"]
+		}
+	}
+	return 1
+}
+
+// limiter returns the rate limiter of the given client, creating it on first use.
+func (l *RateLimiter) limiter(key string) *rate.Limiter {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if limiter, ok := l.limiters.Get(key); ok {
+		return limiter
+	}
+	limit, burst := l.config.Rate, l.config.Burst
+	if quota, ok := l.config.Quotas[key]; ok {
+		limit, burst = quota.Rate, quota.Burst
+	}
+	if limit <= 0 {
+		limit = float64(rate.Inf)
+	} else if burst <= 0 {
+		burst = int(math.Ceil(limit))
+	}
+	limiter := rate.NewLimiter(rate.Limit(limit), burst)
+	l.limiters.Add(key, limiter)
+	return limiter
+}
+
+// allow spends the cost of the method from the client's budget. If the budget
+// is exhausted, an error reporting when to retry is returned.
+func (l *RateLimiter) allow(key string, method string) error {
+	cost := l.cost(method)
+	if cost == 0 {
+		return nil
+	}
+	limiter := l.limiter(key)
+	// Methods costing more than the burst would never be allowed, charge
+	// them the full burst instead.
+	if burst := limiter.Burst(); cost > burst && limiter.Limit() != rate.Inf {
+		cost = burst
+	}
+	var (
+		now         = time.Now()
+		reservation = limiter.ReserveN(now, cost)
+	)
+	if delay := reservation.DelayFrom(now); delay > 0 || !reservation.OK() {
+		reservation.CancelAt(now)
+		l.mark(key, "limited")
+		rateLimitedMeter.Mark(1)
+		return &rateLimitError{retryAfter: delay}
+	}
+	l.mark(key, "allowed")
+	return nil
+}
+
+// mark updates the metrics of the client. To bound the number of metrics,
+// clients without a configured quota are reported together.
+func (l *RateLimiter) mark(key string, outcome string) {
+	if !metrics.Enabled {
+		return
+	}
+	if _, ok := l.config.Quotas[key]; !ok {
+		key = "default"
+	}
+	metrics.GetOrRegisterMeter(fmt.Sprintf("rpc/ratelimit/%s/%s", key, outcome), nil).Mark(1)
+}
+
+type clientKeyContextKey struct{}
+
+// WithClientKey returns a copy of the context identifying the client by the given
+// key, e.g. an API key. Rate limiting uses the key instead of the client's IP
+// address. The context must be that of the HTTP request served by the Server.
+func WithClientKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, clientKeyContextKey{}, key)
+}
+
+// clientKey returns the key identifying the client for rate limiting.
+func clientKey(info PeerInfo) string {
+	if info.clientKey != "" {
+		return info.clientKey
+	}
+	if host, _, err := net.SplitHostPort(info.RemoteAddr); err == nil {
+		return host
+	}
+	return info.RemoteAddr
+}
+
+// clientKeyFromContext returns the client key set by WithClientKey.
+func clientKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(clientKeyContextKey{}).(string)
+	return key
+}
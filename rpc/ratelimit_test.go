@@ -0,0 +1,131 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestRateLimit(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	costs, err := ParseMethodCosts("test_echo*=2, test_null=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	quotas, err := ParseRateQuotas("vip=0:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.SetRateLimiter(NewRateLimiter(RateLimitConfig{
+		Rate:        0.001,
+		Burst:       3,
+		MethodCosts: costs,
+		Quotas:      quotas,
+	}))
+	httpsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key := r.Header.Get("X-Api-Key"); key != "" {
+			r = r.WithContext(WithClientKey(r.Context(), key))
+		}
+		server.ServeHTTP(w, r)
+	}))
+	defer httpsrv.Close()
+
+	dial := func(key string) *Client {
+		var opts []ClientOption
+		if key != "" {
+			opts = append(opts, WithHeader("X-Api-Key", key))
+		}
+		client, err := DialOptions(context.Background(), httpsrv.URL, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return client
+	}
+	var (
+		client = dial("")
+		result echoResult
+	)
+	defer client.Close()
+
+	// Spend the burst of the client's IP address.
+	if err := client.Call(nil, "test_noArgsRets"); err != nil {
+		t.Fatal("call failed:", err)
+	}
+	if err := client.Call(&result, "test_echoWithCtx", "x", 1); err != nil {
+		t.Fatal("call failed:", err)
+	}
+	err = client.Call(nil, "test_noArgsRets")
+	var rpcErr DataError
+	if !errors.As(err, &rpcErr) || rpcErr.(Error).ErrorCode() != errcodeLimitExceeded {
+		t.Fatalf("wrong error for rate limited call: %v", err)
+	}
+	if _, ok := rpcErr.ErrorData().(map[string]interface{})["retryAfter"]; !ok {
+		t.Fatalf("rate limited call missing retry time: %v", rpcErr.ErrorData())
+	}
+	// Free methods are still served.
+	if err := client.Call(nil, "test_null"); err != nil {
+		t.Fatal("free call failed:", err)
+	}
+	// Clients with an API key have their own budget.
+	other := dial("other")
+	defer other.Close()
+	if err := other.Call(nil, "test_noArgsRets"); err != nil {
+		t.Fatal("call with API key failed:", err)
+	}
+	vip := dial("vip")
+	defer vip.Close()
+	for i := 0; i < 10; i++ {
+		if err := vip.Call(&result, "test_echoWithCtx", "x", 1); err != nil {
+			t.Fatal("call with unlimited quota failed:", err)
+		}
+	}
+}
+
+func TestRateLimitCosts(t *testing.T) {
+	costs, err := ParseMethodCosts("debug_trace*=10,debug_traceBlock*=100,eth_getLogs=20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	limiter := NewRateLimiter(RateLimitConfig{MethodCosts: costs})
+	for method, want := range map[string]int{
+		"debug_traceBlockByNumber": 100,
+		"debug_traceCall":          10,
+		"eth_getLogs":              20,
+		"eth_blockNumber":          1,
+	} {
+		if have := limiter.cost(method); have != want {
+			t.Errorf("%s: cost mismatch: have %d, want %d", method, have, want)
+		}
+	}
+	if _, err := ParseMethodCosts("eth_getLogs=x"); err == nil {
+		t.Error("invalid method cost accepted")
+	}
+	quotas, err := ParseRateQuotas("key=2.5:10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]RateQuota{"key": {Rate: 2.5, Burst: 10}}; !reflect.DeepEqual(quotas, want) {
+		t.Errorf("quota mismatch: have %v, want %v", quotas, want)
+	}
+}
@@ -54,6 +54,9 @@ type Server struct {
 	batchItemLimit     int
 	batchResponseLimit int
 	httpBodyLimit      int
+	rateLimiter        *RateLimiter
+	responseCache      *ResponseCache
+	methodFilter       *MethodFilter
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -89,6 +92,36 @@ func (s *Server) SetHTTPBodyLimit(limit int) {
 	s.httpBodyLimit = limit
 }
 
+// SetRateLimiter sets the rate limiter applied to calls from clients. A nil limiter
+// disables rate limiting.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetRateLimiter(limiter *RateLimiter) {
+	s.rateLimiter = limiter
+}
+
+// SetResponseCache sets the cache of results tied to finalized blocks. A nil cache
+// disables caching.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetResponseCache(cache *ResponseCache) {
+	s.responseCache = cache
+}
+
+// SetMethodFilter restricts the methods served to those permitted by the filter.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetMethodFilter(filter MethodFilter) {
+	if filter.IsEmpty() {
+		s.methodFilter = nil
+	} else {
+		s.methodFilter = &filter
+	}
+}
+
 // RegisterName creates a service for the given receiver type under the given name. When no
 // methods on the given receiver match the criteria to be either an RPC method or a
 // subscription an error is returned. Otherwise a new service is created and added to the
@@ -114,6 +147,9 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 		idgen:              s.idgen,
 		batchItemLimit:     s.batchItemLimit,
 		batchResponseLimit: s.batchResponseLimit,
+		rateLimiter:        s.rateLimiter,
+		responseCache:      s.responseCache,
+		methodFilter:       s.methodFilter,
 	}
 	c := initClient(codec, &s.services, cfg)
 	<-codec.closed()
@@ -149,6 +185,9 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 
 	h := newHandler(ctx, codec, s.idgen, &s.services, s.batchItemLimit, s.batchResponseLimit)
 	h.allowSubscribe = false
+	h.rateLimiter = s.rateLimiter
+	h.responseCache = s.responseCache
+	h.methodFilter = s.methodFilter
 	defer h.close(io.EOF, nil)
 
 	reqs, batch, err := codec.readBatch()
@@ -201,14 +240,17 @@ type RPCService struct {
 	server *Server
 }
 
-// Modules returns the list of RPC services with their version number
+// Modules returns the list of RPC services with their version number, omitting
+// services of which no method is served.
 func (s *RPCService) Modules() map[string]string {
 	s.server.services.mu.Lock()
 	defer s.server.services.mu.Unlock()
 
 	modules := make(map[string]string)
-	for name := range s.server.services.services {
-		modules[name] = "1.0"
+	for name, svc := range s.server.services.services {
+		if s.server.methodFilter.allowsService(name, svc) {
+			modules[name] = "1.0"
+		}
 	}
 	return modules
 }
@@ -220,7 +262,7 @@ func (s *RPCService) Modules() map[string]string {
 // the current method call.
 type PeerInfo struct {
 	// Transport is name of the protocol used by the client.
-	// This can be "http", "ws" or "ipc".
+	// This can be "http", "ws", "sse" or "ipc".
 	Transport string
 
 	// Address of client. This will usually contain the IP address and port.
@@ -235,6 +277,9 @@ type PeerInfo struct {
 		Origin    string
 		Host      string
 	}
+
+	// clientKey identifies the client for rate limiting, see WithClientKey.
+	clientKey string
 }
 
 type peerInfoContextKey struct{}
@@ -0,0 +1,212 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sseContentType  = "text/event-stream"
+	ssePingInterval = 30 * time.Second
+)
+
+// IsSSERequest reports whether the HTTP request asks for a Server-Sent Events stream.
+func IsSSERequest(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), sseContentType)
+}
+
+// SSEHandler returns a handler serving subscriptions as Server-Sent Events, allowing
+// clients to subscribe over plain HTTP.
+//
+// The subscribe request is either the JSON-RPC request body of a POST request, or given
+// by the 'method' and 'params' query parameters of a GET request, as sent by browsers.
+// The subscribe response and all notifications are sent as events, until the client
+// disconnects or the subscription ends.
+func (s *Server) SSEHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		msg, err := s.readSSERequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// Subscriptions outlive the HTTP server's write timeout, every write
+		// gets its own deadline instead.
+		rc := http.NewResponseController(w)
+		rc.SetWriteDeadline(time.Now().Add(defaultWriteTimeout))
+
+		w.Header().Set("content-type", sseContentType)
+		w.Header().Set("cache-control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		rc.Flush()
+
+		codec := newSSECodec(r, w, rc, msg)
+		s.ServeCodec(codec, 0)
+
+		// Wait for in-flight writes, w must not be used after returning.
+		codec.mu.Lock()
+		codec.mu.Unlock()
+	})
+}
+
+// readSSERequest reads the subscribe request of an event stream.
+func (s *Server) readSSERequest(r *http.Request) (*jsonrpcMessage, error) {
+	msg := new(jsonrpcMessage)
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		msg.Version, msg.ID, msg.Method = vsn, json.RawMessage("1"), query.Get("method")
+		if params := query.Get("params"); params != "" {
+			msg.Params = json.RawMessage(params)
+		}
+	case http.MethodPost:
+		body := io.LimitReader(r.Body, int64(s.httpBodyLimit))
+		if err := json.NewDecoder(body).Decode(msg); err != nil {
+			return nil, fmt.Errorf("invalid request: %v", err)
+		}
+	default:
+		return nil, errors.New("method not allowed")
+	}
+	if !msg.isSubscribe() || !msg.hasValidID() {
+		return nil, errors.New("only subscriptions are served as events")
+	}
+	return msg, nil
+}
+
+// sseCodec is the codec of an event stream. It reads the subscribe request once and
+// writes every message as an event.
+type sseCodec struct {
+	info PeerInfo
+	w    http.ResponseWriter
+	rc   *http.ResponseController
+	req  chan *jsonrpcMessage
+
+	mu        sync.Mutex // protects writes to w
+	closeOnce sync.Once
+	closeCh   chan interface{}
+	ctx       context.Context
+}
+
+func newSSECodec(r *http.Request, w http.ResponseWriter, rc *http.ResponseController, msg *jsonrpcMessage) *sseCodec {
+	c := &sseCodec{
+		info:    PeerInfo{Transport: "sse", RemoteAddr: r.RemoteAddr},
+		w:       w,
+		rc:      rc,
+		req:     make(chan *jsonrpcMessage, 1),
+		closeCh: make(chan interface{}),
+		ctx:     r.Context(),
+	}
+	c.info.HTTP.Version = r.Proto
+	c.info.HTTP.Host = r.Host
+	c.info.HTTP.Origin = r.Header.Get("Origin")
+	c.info.HTTP.UserAgent = r.Header.Get("User-Agent")
+	c.info.clientKey = clientKeyFromContext(r.Context())
+	c.req <- msg
+
+	go c.pingLoop()
+	return c
+}
+
+func (c *sseCodec) peerInfo() PeerInfo {
+	return c.info
+}
+
+func (c *sseCodec) remoteAddr() string {
+	return c.info.RemoteAddr
+}
+
+// readBatch returns the subscribe request, then blocks until the stream ends.
+func (c *sseCodec) readBatch() ([]*jsonrpcMessage, bool, error) {
+	select {
+	case msg := <-c.req:
+		return []*jsonrpcMessage{msg}, false, nil
+	case <-c.closeCh:
+		return nil, false, io.EOF
+	case <-c.ctx.Done():
+		c.close()
+		return nil, false, io.EOF
+	}
+}
+
+// writeJSON sends a message as an event. An error response to the subscribe
+// request ends the stream.
+func (c *sseCodec) writeJSON(ctx context.Context, v interface{}, isError bool) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := c.write("data: " + string(data) + "\n\n"); err != nil {
+		return err
+	}
+	if msg, ok := v.(*jsonrpcMessage); isError || (ok && msg.Error != nil) {
+		c.close()
+	}
+	return nil
+}
+
+func (c *sseCodec) write(s string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	select {
+	case <-c.closeCh:
+		return net.ErrClosed
+	default:
+	}
+	c.rc.SetWriteDeadline(time.Now().Add(defaultWriteTimeout))
+	if _, err := io.WriteString(c.w, s); err != nil {
+		c.close()
+		return err
+	}
+	if err := c.rc.Flush(); err != nil {
+		c.close()
+		return err
+	}
+	return nil
+}
+
+// pingLoop sends comments to keep proxies from closing idle streams.
+func (c *sseCodec) pingLoop() {
+	ticker := time.NewTicker(ssePingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.write(": ping\n\n")
+		case <-c.closeCh:
+			return
+		}
+	}
+}
+
+func (c *sseCodec) close() {
+	c.closeOnce.Do(func() { close(c.closeCh) })
+}
+
+func (c *sseCodec) closed() <-chan interface{} {
+	return c.closeCh
+}
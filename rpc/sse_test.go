@@ -0,0 +1,99 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSSESubscription(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	httpsrv := httptest.NewServer(server.SSEHandler())
+	defer httpsrv.Close()
+
+	subscribe := func(method, params string) (*http.Response, *bufio.Scanner) {
+		t.Helper()
+		query := url.Values{"method": {method}, "params": {params}}
+		req, _ := http.NewRequest(http.MethodGet, httpsrv.URL+"?"+query.Encode(), nil)
+		req.Header.Set("accept", sseContentType)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, bufio.NewScanner(resp.Body)
+	}
+	// readEvent returns the data of the next event.
+	readEvent := func(events *bufio.Scanner) *jsonrpcMessage {
+		t.Helper()
+		for events.Scan() {
+			line := events.Text()
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				msg := new(jsonrpcMessage)
+				if err := json.Unmarshal([]byte(data), msg); err != nil {
+					t.Fatalf("invalid event %q: %v", line, err)
+				}
+				return msg
+			}
+		}
+		return nil
+	}
+	resp, events := subscribe("nftest_subscribe", `["someSubscription", 3, 5]`)
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("content-type"); ct != sseContentType {
+		t.Fatalf("wrong content type: %q", ct)
+	}
+	msg := readEvent(events)
+	if msg == nil || msg.Error != nil || len(msg.Result) == 0 {
+		t.Fatalf("invalid subscribe response: %+v", msg)
+	}
+	for i := 0; i < 3; i++ {
+		msg := readEvent(events)
+		if msg == nil || !msg.isNotification() {
+			t.Fatalf("invalid notification: %+v", msg)
+		}
+		var result subscriptionResult
+		if err := json.Unmarshal(msg.Params, &result); err != nil {
+			t.Fatal(err)
+		}
+		if want := strconv.Itoa(5 + i); string(result.Result) != want {
+			t.Fatalf("wrong notification %d: %s", i, result.Result)
+		}
+	}
+	// Failed subscriptions end the stream.
+	resp, events = subscribe("nftest_subscribe", `["noSuchSubscription"]`)
+	defer resp.Body.Close()
+	if msg := readEvent(events); msg == nil || msg.Error == nil {
+		t.Fatalf("expected error event, got %+v", msg)
+	}
+	if msg := readEvent(events); msg != nil {
+		t.Fatalf("stream continued after error: %+v", msg)
+	}
+	// Regular calls are rejected.
+	resp, _ = subscribe("test_echo", `["x", 1]`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("wrong status for call: %d", resp.StatusCode)
+	}
+}
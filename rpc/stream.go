@@ -0,0 +1,208 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	errStreamTooLarge = errors.New("streamed result exceeds size limit")
+	errStreamTimeout  = errors.New("streamed result took too long")
+)
+
+// Stream is a method result produced incrementally. Methods returning a *Stream have
+// their result encoded as a JSON array, whose items are written to the connection as
+// they are produced instead of materializing the whole result in memory first.
+//
+// The producer function runs after the method has returned, while the response is
+// written, and is subject to the request timeout. It should stop when yield returns
+// an error. If the producer fails before yielding the first item, the error is
+// returned to the caller as usual. Later errors end the array and are reported in
+// the error member of the response, next to the partial result.
+type Stream struct {
+	produce func(yield func(item interface{}) error) error
+}
+
+// NewStream creates a stream result from a producer function.
+func NewStream(produce func(yield func(item interface{}) error) error) *Stream {
+	return &Stream{produce: produce}
+}
+
+// MarshalJSON produces the whole result as a JSON array. This is used when the result
+// is consumed in-process or can't be streamed.
+func (s *Stream) MarshalJSON() ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		enc = &streamEncoder{w: &buf}
+	)
+	if err := enc.encode(s, ""); err != nil {
+		return nil, err
+	}
+	enc.close("", "")
+	return buf.Bytes(), nil
+}
+
+// streamEncoder writes the items of a stream as a JSON array.
+type streamEncoder struct {
+	w        io.Writer
+	limit    int       // maximum size of the output if non-zero
+	deadline time.Time // time after which no more items are accepted if non-zero
+	written  int       // number of bytes written
+	opened   bool      // whether the array was opened
+	err      error     // first error of w
+}
+
+func (enc *streamEncoder) write(data []byte) error {
+	if enc.err != nil {
+		return enc.err
+	}
+	if enc.limit != 0 && enc.written+len(data) > enc.limit {
+		return errStreamTooLarge
+	}
+	n, err := enc.w.Write(data)
+	enc.written += n
+	enc.err = err
+	return err
+}
+
+// encode writes prefix and the items of the stream as a JSON array, leaving the array
+// open. The prefix is not written before the first item is produced, so nothing is
+// written if the producer fails right away. If the output would exceed the limit,
+// errStreamTooLarge is returned, and errStreamTimeout if the deadline has passed.
+func (enc *streamEncoder) encode(s *Stream, prefix string) error {
+	return s.produce(func(item interface{}) error {
+		if !enc.deadline.IsZero() && time.Now().After(enc.deadline) {
+			return errStreamTimeout
+		}
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if !enc.opened {
+			enc.opened = true
+			err = enc.write([]byte(prefix + "["))
+		} else {
+			err = enc.write([]byte{','})
+		}
+		if err != nil {
+			return err
+		}
+		return enc.write(data)
+	})
+}
+
+// close ends the array and writes suffix. If the array wasn't opened yet, the prefix
+// and an empty array are written.
+func (enc *streamEncoder) close(prefix, suffix string) error {
+	if !enc.opened {
+		enc.opened = true
+		return enc.write([]byte(prefix + "[]" + suffix))
+	}
+	return enc.write([]byte("]" + suffix))
+}
+
+// streamWriter is implemented by codecs which can write a message incrementally.
+type streamWriter interface {
+	// writeStream calls write with a writer for the next message. Items should only
+	// be added to the message until the deadline passed to write, if it is non-zero.
+	// If write fails, the message is incomplete and the connection is closed.
+	writeStream(ctx context.Context, write func(w io.Writer, deadline time.Time) error) error
+}
+
+// writeStreamAnswer writes the response of a method returning a stream. If the
+// context is canceled by the request timeout while the stream is produced, the
+// timeout is reported as the error of the response.
+func (h *handler) writeStreamAnswer(ctx context.Context, answer *jsonrpcMessage) {
+	sw, ok := h.conn.(streamWriter)
+	if !ok {
+		resp, _ := answer.materialize(0)
+		h.conn.writeJSON(ctx, resp, false)
+		return
+	}
+	prefix := `{"jsonrpc":"` + vsn + `","id":` + string(answer.ID) + `,"result":`
+	err := sw.writeStream(ctx, func(w io.Writer, deadline time.Time) error {
+		enc := &streamEncoder{w: w, deadline: deadline}
+		err := enc.encode(answer.stream, prefix)
+		if enc.err != nil {
+			return enc.err
+		}
+		if err == nil {
+			return enc.close(prefix, "}\n")
+		}
+		if ctx.Err() != nil {
+			err = &internalServerError{errcodeTimeout, errMsgTimeout}
+		}
+		if !enc.opened {
+			// Nothing was sent yet, respond with the error instead.
+			return json.NewEncoder(w).Encode(answer.errorResponse(err))
+		}
+		// End the partial result, reporting the error alongside it.
+		data, _ := json.Marshal(answer.errorResponse(err).Error)
+		return enc.close(prefix, `,"error":`+string(data)+"}\n")
+	})
+	if err != nil {
+		h.log.Warn("Streamed response aborted", "reqid", idForLog{answer.ID}, "err", err)
+	}
+}
+
+// materialize encodes the stream of the message as a regular result. If limit is
+// non-zero and the result exceeds it, nil and false are returned.
+func (msg *jsonrpcMessage) materialize(limit int) (*jsonrpcMessage, bool) {
+	var (
+		buf bytes.Buffer
+		enc = &streamEncoder{w: &buf, limit: limit}
+	)
+	err := enc.encode(msg.stream, "")
+	if err == nil {
+		err = enc.close("", "")
+	}
+	if err == errStreamTooLarge {
+		return nil, false
+	}
+	if err != nil {
+		return msg.errorResponse(err), true
+	}
+	return &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: buf.Bytes()}, true
+}
+
+// deadlineWriter extends the write deadline of the connection on every write, so
+// streams are not limited in their total duration, but in the time any write takes.
+type deadlineWriter struct {
+	w    io.Writer
+	conn deadlineCloser
+}
+
+// newDeadlineWriter returns a writer to w. If the context has a deadline, it applies
+// to the whole stream instead.
+func newDeadlineWriter(ctx context.Context, w io.Writer, conn deadlineCloser) io.Writer {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetWriteDeadline(deadline)
+		return w
+	}
+	return &deadlineWriter{w: w, conn: conn}
+}
+
+func (dw *deadlineWriter) Write(data []byte) (int, error) {
+	dw.conn.SetWriteDeadline(time.Now().Add(defaultWriteTimeout))
+	return dw.w.Write(data)
+}
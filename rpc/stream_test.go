@@ -0,0 +1,134 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStreamResult(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	check := func(t *testing.T, client *Client) {
+		var result []int
+		if err := client.Call(&result, "test_stream", 5, -1); err != nil {
+			t.Fatal(err)
+		}
+		if want := []int{0, 1, 2, 3, 4}; !reflect.DeepEqual(result, want) {
+			t.Fatalf("wrong result: %v", result)
+		}
+		if err := client.Call(&result, "test_stream", 0, -1); err != nil || len(result) != 0 {
+			t.Fatalf("wrong empty result: %v %v", result, err)
+		}
+		// Failures before the first item are reported as usual.
+		if err := client.Call(&result, "test_stream", 5, 0); err == nil || err.Error() != "failed at 0" {
+			t.Fatalf("wrong error: %v", err)
+		}
+		// Failures while streaming end the partial result and are reported alongside.
+		if err := client.Call(&result, "test_stream", 5, 3); err == nil || err.Error() != "failed at 3" {
+			t.Fatalf("wrong error for aborted stream: %v", err)
+		}
+		// The connection remains usable.
+		if err := client.Call(&result, "test_stream", 2, -1); err != nil || len(result) != 2 {
+			t.Fatalf("wrong result after aborted stream: %v %v", result, err)
+		}
+	}
+	t.Run("inproc", func(t *testing.T) {
+		client := DialInProc(server)
+		defer client.Close()
+		check(t, client)
+	})
+	t.Run("ipc", func(t *testing.T) {
+		client, l := ipcTestClient(server, nil)
+		defer l.Close()
+		defer client.Close()
+		check(t, client)
+	})
+	t.Run("http", func(t *testing.T) {
+		client, hs := httpTestClient(server, "http", nil)
+		defer hs.Close()
+		defer client.Close()
+		check(t, client)
+	})
+	t.Run("ws", func(t *testing.T) {
+		client, hs := httpTestClient(server, "ws", nil)
+		defer hs.Close()
+		defer client.Close()
+		check(t, client)
+	})
+}
+
+func TestStreamTimeout(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	hs := httptest.NewUnstartedServer(server)
+	hs.Config.WriteTimeout = time.Second
+	hs.Start()
+	defer hs.Close()
+	client, err := DialHTTP(hs.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// The request timeout applies while the stream is produced.
+	var result []int
+	err = client.Call(&result, "test_slowStream")
+	if re, ok := err.(Error); !ok || re.ErrorCode() != errcodeTimeout {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
+
+func TestStreamBatchResponseSizeLimit(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	server.SetBatchLimits(100, 30)
+	client := DialInProc(server)
+	defer client.Close()
+
+	batch := []BatchElem{
+		{Method: "test_stream", Args: []any{5, -1}, Result: new([]int)},
+		{Method: "test_stream", Args: []any{50, -1}, Result: new([]int)},
+	}
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatal(err)
+	}
+	if batch[0].Error != nil || len(*batch[0].Result.(*[]int)) != 5 {
+		t.Fatalf("wrong first result: %v %v", batch[0].Result, batch[0].Error)
+	}
+	if re, ok := batch[1].Error.(Error); !ok || re.ErrorCode() != errcodeResponseTooLarge {
+		t.Fatalf("expected response too large error, got %v", batch[1].Error)
+	}
+}
+
+func TestStreamMarshalJSON(t *testing.T) {
+	result, err := json.Marshal(new(testService).Stream(3, -1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result) != "[0,1,2]" {
+		t.Fatalf("wrong result: %s", result)
+	}
+	if _, err := json.Marshal(new(testService).Stream(3, 1)); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -29,6 +29,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/ethereum/go-ethereum/log"
 )
 
 var (
@@ -207,6 +209,7 @@ type ClientSubscription struct {
 	etype     reflect.Type
 	channel   reflect.Value
 	namespace string
+	params    json.RawMessage // parameters of the subscribe request, for resubscribing
 	subid     string
 
 	// The in channel receives notification values from client dispatcher.
@@ -223,15 +226,31 @@ type ClientSubscription struct {
 	quit        chan error
 	forwardDone chan struct{}
 	unsubDone   chan struct{}
+
+	// Interruptions of the subscription are reported on 'gaps' when the client
+	// resubscribes after losing its connection. 'resubs' tracks the goroutines
+	// re-establishing the subscription.
+	gaps   chan SubscriptionGap
+	resubs sync.WaitGroup
+}
+
+// SubscriptionGap describes an interruption of a subscription which was re-established
+// after the client lost its connection. Notifications sent by the server between Start
+// and End were not received, callers should backfill them if necessary.
+type SubscriptionGap struct {
+	Start time.Time // Time the connection was lost
+	End   time.Time // Time the subscription was re-established
+	Err   error     // Error which interrupted the subscription
 }
 
 // This is the sentinel value sent on sub.quit when Unsubscribe is called.
 var errUnsubscribed = errors.New("unsubscribed")
 
-func newClientSubscription(c *Client, namespace string, channel reflect.Value) *ClientSubscription {
+func newClientSubscription(c *Client, namespace string, params json.RawMessage, channel reflect.Value) *ClientSubscription {
 	sub := &ClientSubscription{
 		client:      c,
 		namespace:   namespace,
+		params:      params,
 		etype:       channel.Type().Elem(),
 		channel:     channel,
 		in:          make(chan json.RawMessage),
@@ -239,6 +258,7 @@ func newClientSubscription(c *Client, namespace string, channel reflect.Value) *
 		forwardDone: make(chan struct{}),
 		unsubDone:   make(chan struct{}),
 		err:         make(chan error, 1),
+		gaps:        make(chan SubscriptionGap, 1),
 	}
 	return sub
 }
@@ -255,6 +275,13 @@ func (sub *ClientSubscription) Err() <-chan error {
 	return sub.err
 }
 
+// Gaps returns the channel reporting interruptions of the subscription. Gaps are only
+// reported if the client was created with the WithResubscribe option. Unread gaps are
+// merged into a single one spanning all of them.
+func (sub *ClientSubscription) Gaps() <-chan SubscriptionGap {
+	return sub.gaps
+}
+
 // Unsubscribe unsubscribes the notification and closes the error channel.
 // It can safely be called more than once.
 func (sub *ClientSubscription) Unsubscribe() {
@@ -297,6 +324,9 @@ func (sub *ClientSubscription) run() {
 	// blocked in sub.deliver() or sub.close(). Closing forwardDone unblocks them.
 	close(sub.forwardDone)
 
+	// Wait for resubscribe attempts to give up, as they may change the ID.
+	sub.resubs.Wait()
+
 	// Call the unsubscribe method on the server.
 	if unsubscribe {
 		sub.requestUnsubscribe()
@@ -344,6 +374,13 @@ func (sub *ClientSubscription) forward() (unsubscribeServer bool, err error) {
 				// Exiting because Unsubscribe was called, unsubscribe on server.
 				return true, nil
 			}
+			if err != nil && err != ErrClientQuit && sub.client.resubscribe {
+				// The connection was lost, keep forwarding buffered values
+				// while the subscription is re-established.
+				sub.resubs.Add(1)
+				go sub.resubscribe(err)
+				continue
+			}
 			return false, err
 
 		case 1: // <-sub.in
@@ -373,3 +410,76 @@ func (sub *ClientSubscription) requestUnsubscribe() error {
 	var result interface{}
 	return sub.client.Call(&result, sub.namespace+unsubscribeMethodSuffix, sub.subid)
 }
+
+// resubscribe re-establishes the subscription after the connection was lost, retrying
+// with an exponential backoff until it succeeds, the subscription is ended, or the
+// client is closed.
+func (sub *ClientSubscription) resubscribe(cause error) {
+	defer sub.resubs.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-sub.forwardDone:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	var (
+		start   = time.Now()
+		backoff = sub.client.resubscribeMinBackoff
+	)
+	for {
+		err := sub.requestResubscribe(ctx)
+		switch {
+		case err == nil:
+			sub.reportGap(SubscriptionGap{Start: start, End: time.Now(), Err: cause})
+			return
+		case ctx.Err() != nil:
+			return
+		case errors.Is(err, ErrClientQuit):
+			sub.close(ErrClientQuit)
+			return
+		}
+		log.Debug("RPC resubscribe failed", "namespace", sub.namespace, "err", err, "retry", backoff)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		backoff = min(2*backoff, sub.client.resubscribeMaxBackoff)
+	}
+}
+
+// requestResubscribe sends the subscribe request again, re-registering the
+// subscription under its new ID.
+func (sub *ClientSubscription) requestResubscribe(ctx context.Context) error {
+	msg := &jsonrpcMessage{Version: vsn, ID: sub.client.nextID(), Method: sub.namespace + subscribeMethodSuffix, Params: sub.params}
+	op := &requestOp{
+		ids:   []json.RawMessage{msg.ID},
+		resp:  make(chan []*jsonrpcMessage, 1),
+		sub:   sub,
+		resub: true,
+	}
+	if err := sub.client.send(ctx, op, msg); err != nil {
+		return err
+	}
+	resp, err := op.wait(ctx, sub.client)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(resp[0].Result, &sub.subid)
+}
+
+// reportGap sends a gap on the gaps channel, merging it with an unread one.
+func (sub *ClientSubscription) reportGap(gap SubscriptionGap) {
+	for {
+		select {
+		case sub.gaps <- gap:
+			return
+		case pending := <-sub.gaps:
+			gap.Start = pending.Start
+		}
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -107,6 +108,37 @@ func (s *testService) Block(ctx context.Context) error {
 	return errors.New("context canceled in testservice_block")
 }
 
+// Stream produces the numbers up to n, failing at the given number if it is not negative.
+func (s *testService) Stream(n, failAt int) *Stream {
+	return NewStream(func(yield func(interface{}) error) error {
+		for i := 0; i < n; i++ {
+			if i == failAt {
+				return fmt.Errorf("failed at %d", i)
+			}
+			if err := yield(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SlowStream produces a number every 50ms until the context is canceled.
+func (s *testService) SlowStream(ctx context.Context) *Stream {
+	return NewStream(func(yield func(interface{}) error) error {
+		for i := 0; ; i++ {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(50 * time.Millisecond):
+			}
+			if err := yield(i); err != nil {
+				return err
+			}
+		}
+	})
+}
+
 func (s *testService) Rets() (string, error) {
 	return "", nil
 }
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -38,6 +39,7 @@ const (
 	wsPingInterval     = 30 * time.Second
 	wsPingWriteTimeout = 5 * time.Second
 	wsPongTimeout      = 30 * time.Second
+	wsStreamTimeout    = 20 * time.Second // maximum time a streamed message blocks other writes
 	wsDefaultReadLimit = 32 * 1024 * 1024
 )
 
@@ -61,6 +63,7 @@ func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			return
 		}
 		codec := newWebsocketCodec(conn, r.Host, r.Header, wsDefaultReadLimit)
+		codec.(*websocketCodec).info.clientKey = clientKeyFromContext(r.Context())
 		s.ServeCodec(codec, 0)
 	})
 }
@@ -344,6 +347,33 @@ func (wc *websocketCodec) writeJSON(ctx context.Context, v interface{}, isError
 	return err
 }
 
+// writeStream writes a message incrementally as a single websocket message. Other
+// messages, including pings, can't be written until it is complete, so items are
+// only added for wsStreamTimeout.
+func (wc *websocketCodec) writeStream(ctx context.Context, write func(w io.Writer, deadline time.Time) error) error {
+	wc.encMu.Lock()
+	wc.conn.SetWriteDeadline(time.Now().Add(defaultWriteTimeout))
+	w, err := wc.conn.NextWriter(websocket.TextMessage)
+	if err == nil {
+		if err = write(newDeadlineWriter(ctx, w, wc.conn), time.Now().Add(wsStreamTimeout)); err == nil {
+			err = w.Close()
+		}
+	}
+	wc.encMu.Unlock()
+
+	if err != nil {
+		// The ping loop must be able to take the lock to shut down.
+		wc.close()
+		return err
+	}
+	// Notify pingLoop to delay the next idle ping.
+	select {
+	case wc.pingReset <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
 // pingLoop sends periodic ping frames when the connection is idle.
 func (wc *websocketCodec) pingLoop() {
 	var pingTimer = time.NewTimer(wsPingInterval)